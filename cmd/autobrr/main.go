@@ -11,7 +11,9 @@ import (
 
 	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/api"
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/auth"
+	"github.com/autobrr/autobrr/internal/backup"
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/download_client"
@@ -26,6 +28,7 @@ import (
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/server"
+	"github.com/autobrr/autobrr/internal/session"
 	"github.com/autobrr/autobrr/internal/update"
 	"github.com/autobrr/autobrr/internal/user"
 
@@ -81,6 +84,7 @@ func main() {
 	// setup repos
 	var (
 		apikeyRepo         = database.NewAPIRepo(log, db)
+		auditRepo          = database.NewAuditRepo(log, db)
 		downloadClientRepo = database.NewDownloadClientRepo(log, db)
 		actionRepo         = database.NewActionRepo(log, db, downloadClientRepo)
 		filterRepo         = database.NewFilterRepo(log, db)
@@ -90,25 +94,29 @@ func main() {
 		ircRepo            = database.NewIrcRepo(log, db)
 		notificationRepo   = database.NewNotificationRepo(log, db)
 		releaseRepo        = database.NewReleaseRepo(log, db)
+		sessionRepo        = database.NewSessionRepo(log, db)
 		userRepo           = database.NewUserRepo(log, db)
 	)
 
 	// setup services
 	var (
 		apiService            = api.NewService(log, apikeyRepo)
+		auditService          = audit.NewService(log, auditRepo)
+		sessionService        = session.NewService(log, sessionRepo)
 		notificationService   = notification.NewService(log, notificationRepo)
 		updateService         = update.NewUpdate(log, cfg.Config)
-		schedulingService     = scheduler.NewService(log, cfg.Config, notificationService, updateService)
+		schedulingService     = scheduler.NewService(log, cfg.Config, db, notificationService, updateService, releaseRepo)
 		indexerAPIService     = indexer.NewAPIService(log)
-		userService           = user.NewService(userRepo)
-		authService           = auth.NewService(log, userService)
+		userService           = user.NewService(userRepo, sessionRepo)
+		authService           = auth.NewService(log, cfg.Config, userService)
 		downloadClientService = download_client.NewService(log, downloadClientRepo)
 		actionService         = action.NewService(log, actionRepo, downloadClientService, bus)
 		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, indexerAPIService, schedulingService)
-		filterService         = filter.NewService(log, filterRepo, actionRepo, releaseRepo, indexerAPIService, indexerService)
-		releaseService        = release.NewService(log, releaseRepo, actionService, filterService)
-		ircService            = irc.NewService(log, ircRepo, releaseService, indexerService, notificationService)
-		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, schedulingService)
+		filterService         = filter.NewService(log, filterRepo, actionRepo, releaseRepo, indexerAPIService, indexerService, downloadClientService)
+		releaseService        = release.NewService(log, cfg.Config, releaseRepo, feedRepo, actionService, filterService, indexerService, notificationService, schedulingService)
+		ircService            = irc.NewService(log, cfg.Config, ircRepo, releaseService, indexerService, notificationService, schedulingService, bus)
+		feedService           = feed.NewService(log, cfg.Config, feedRepo, feedCacheRepo, releaseService, schedulingService, bus)
+		backupService         = backup.NewService(log, version, downloadClientRepo, indexerRepo, ircRepo, feedRepo, filterRepo, actionRepo, notificationRepo)
 	)
 
 	// register event subscribers
@@ -127,7 +135,9 @@ func main() {
 			date,
 			actionService,
 			apiService,
+			auditService,
 			authService,
+			backupService,
 			downloadClientService,
 			filterService,
 			feedService,
@@ -135,7 +145,9 @@ func main() {
 			ircService,
 			notificationService,
 			releaseService,
+			sessionService,
 			updateService,
+			userService,
 		)
 		errorChannel <- httpServer.Open()
 	}()
@@ -143,7 +155,7 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGTERM)
 
-	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, schedulingService, updateService)
+	srv := server.NewServer(log, cfg.Config, ircService, indexerService, feedService, releaseService, schedulingService, updateService)
 	if err := srv.Start(); err != nil {
 		log.Fatal().Stack().Err(err).Msg("could not start server")
 		return
@@ -12,12 +12,16 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/backup"
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/user"
 	"github.com/autobrr/autobrr/pkg/argon2id"
 	"github.com/autobrr/autobrr/pkg/errors"
 
@@ -27,8 +31,15 @@ import (
 
 const usage = `usage: autobrrctl --config path <action>
 
-  create-user		<username>	Create user
-  change-password	<username>	Change password for user
+  create-user		<username> [role]	Create user, role is admin, editor or viewer (default admin)
+  change-password	<username>		Change password for user
+  set-role		<username> <role>	Change role for user
+  list-users				List users
+  delete-user		<username>		Delete user
+  disable-2fa		<username>		Disable two-factor authentication for user
+  backup		<file> [redact]		Export filters, actions, indexers, irc, feeds, clients and notifications to a .json or .zip file
+  restore		<file>			Import a backup, existing items are skipped. Stop autobrr first when using sqlite
+  db migrate		--from sqlite --to postgres	Copy all data between databases configured in config.toml. Stop autobrr first, the target must be empty
   version				Can be run without --config
   help					Show this help message
 
@@ -124,9 +135,19 @@ func main() {
 			log.Fatalf("failed to hash password: %v", err)
 		}
 
+		role := domain.UserRoleAdmin
+		if r := flag.Arg(2); r != "" {
+			role = domain.UserRole(r)
+		}
+
+		if !role.IsValid() {
+			log.Fatalf("invalid role: %v", role)
+		}
+
 		user := domain.CreateUserRequest{
 			Username: username,
 			Password: hashed,
+			Role:     role,
 		}
 		if err := userRepo.Store(context.Background(), user); err != nil {
 			log.Fatalf("failed to create user: %v", err)
@@ -179,6 +200,207 @@ func main() {
 		if err := userRepo.Update(context.Background(), *user); err != nil {
 			log.Fatalf("failed to create user: %v", err)
 		}
+	case "set-role":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		username := flag.Arg(1)
+		role := domain.UserRole(flag.Arg(2))
+		if username == "" || role == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		userSvc := newUserService(configPath)
+
+		u, err := userSvc.FindByUsername(context.Background(), username)
+		if err != nil {
+			log.Fatalf("failed to get user: %v", err)
+		}
+
+		if u == nil {
+			log.Fatalf("user not found: %v", username)
+		}
+
+		if err := userSvc.Update(context.Background(), domain.UpdateUserRequest{ID: u.ID, Role: role}); err != nil {
+			log.Fatalf("failed to update user: %v", err)
+		}
+	case "list-users":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		userSvc := newUserService(configPath)
+
+		users, err := userSvc.List(context.Background())
+		if err != nil {
+			log.Fatalf("failed to list users: %v", err)
+		}
+
+		for _, u := range users {
+			fmt.Printf("%v\t%v\n", u.Username, u.Role)
+		}
+	case "delete-user":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		username := flag.Arg(1)
+		if username == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		userSvc := newUserService(configPath)
+
+		u, err := userSvc.FindByUsername(context.Background(), username)
+		if err != nil {
+			log.Fatalf("failed to get user: %v", err)
+		}
+
+		if u == nil {
+			log.Fatalf("user not found: %v", username)
+		}
+
+		if err := userSvc.Delete(context.Background(), u.ID); err != nil {
+			log.Fatalf("failed to delete user: %v", err)
+		}
+	case "disable-2fa":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		username := flag.Arg(1)
+		if username == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		userSvc := newUserService(configPath)
+
+		u, err := userSvc.FindByUsername(context.Background(), username)
+		if err != nil {
+			log.Fatalf("failed to get user: %v", err)
+		}
+
+		if u == nil {
+			log.Fatalf("user not found: %v", username)
+		}
+
+		if err := userSvc.SetTwoFactor(context.Background(), u.ID, false, "", nil); err != nil {
+			log.Fatalf("failed to disable two-factor authentication: %v", err)
+		}
+
+		fmt.Printf("two-factor authentication disabled for user: %v\n", username)
+	case "backup":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		file := flag.Arg(1)
+		if file == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		format := backup.FormatJSON
+		if strings.EqualFold(filepath.Ext(file), ".zip") {
+			format = backup.FormatZip
+		}
+
+		b, err := newBackupService(configPath).Export(context.Background(), domain.BackupOptions{Redact: flag.Arg(2) == "redact"})
+		if err != nil {
+			log.Fatalf("failed to export backup: %v", err)
+		}
+
+		f, err := os.Create(file)
+		if err != nil {
+			log.Fatalf("failed to create file: %v", err)
+		}
+
+		if err := backup.Encode(f, b, format); err != nil {
+			f.Close()
+			log.Fatalf("failed to write backup: %v", err)
+		}
+
+		if err := f.Close(); err != nil {
+			log.Fatalf("failed to write backup: %v", err)
+		}
+
+		fmt.Printf("backup written to: %v\n", file)
+	case "restore":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		file := flag.Arg(1)
+		if file == "" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("failed to read backup: %v", err)
+		}
+
+		b, err := backup.Decode(data)
+		if err != nil {
+			log.Fatalf("failed to read backup: %v", err)
+		}
+
+		result, err := newBackupService(configPath).Import(context.Background(), b)
+		if err != nil {
+			log.Fatalf("failed to restore backup: %v", err)
+		}
+
+		fmt.Printf("restored backup: created %v skipped %v\n", result.Created, result.Skipped)
+	case "db":
+
+		if configPath == "" {
+			log.Fatal("--config required")
+		}
+
+		if flag.Arg(1) != "migrate" {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		migrateFlags := flag.NewFlagSet("db migrate", flag.ExitOnError)
+		from := migrateFlags.String("from", "sqlite", "source database: sqlite or postgres")
+		to := migrateFlags.String("to", "postgres", "target database: sqlite or postgres")
+		migrateFlags.Parse(flag.Args()[2:])
+
+		if *from == *to {
+			log.Fatal("--from and --to must be different databases")
+		}
+
+		cfg := config.New(configPath, version)
+		l := logger.New(cfg.Config)
+
+		src := openDatabase(cfg.Config, *from, l)
+		defer src.Close()
+
+		dst := openDatabase(cfg.Config, *to, l)
+		defer dst.Close()
+
+		tables, err := database.Copy(context.Background(), src, dst)
+		if err != nil {
+			log.Fatalf("failed to migrate database: %v", err)
+		}
+
+		for _, t := range tables {
+			fmt.Printf("%-30s %d rows\n", t.Table, t.Rows)
+		}
+
+		fmt.Printf("migrated %s to %s, set databaseType = \"%s\" in config.toml to use it\n", *from, *to, *to)
 	default:
 		flag.Usage()
 		if cmd != "help" {
@@ -187,6 +409,63 @@ func main() {
 	}
 }
 
+// openDB reads the config and opens the database connection
+// newUserService opens the database and returns the user service on top of it
+func newUserService(configPath string) user.Service {
+	l, db := openDB(configPath)
+
+	return user.NewService(database.NewUserRepo(l, db), database.NewSessionRepo(l, db))
+}
+
+func openDB(configPath string) (logger.Logger, *database.DB) {
+	cfg := config.New(configPath, version)
+
+	l := logger.New(cfg.Config)
+
+	db, _ := database.NewDB(cfg.Config, l)
+	if err := db.Open(); err != nil {
+		log.Fatal("could not open db connection")
+	}
+
+	return l, db
+}
+
+// openDatabase opens the database of the given type with the settings from the config
+func openDatabase(cfg *domain.Config, databaseType string, l logger.Logger) *database.DB {
+	dbCfg := *cfg
+	dbCfg.DatabaseType = databaseType
+
+	db, err := database.NewDB(&dbCfg, l)
+	if err != nil {
+		log.Fatalf("could not setup %s database: %v", databaseType, err)
+	}
+
+	if err := db.Open(); err != nil {
+		log.Fatalf("could not open %s database: %v", databaseType, err)
+	}
+
+	return db
+}
+
+// newBackupService sets up the backup service on top of the repos
+func newBackupService(configPath string) backup.Service {
+	l, db := openDB(configPath)
+
+	downloadClientRepo := database.NewDownloadClientRepo(l, db)
+
+	return backup.NewService(
+		l,
+		version,
+		downloadClientRepo,
+		database.NewIndexerRepo(l, db),
+		database.NewIrcRepo(l, db),
+		database.NewFeedRepo(l, db),
+		database.NewFilterRepo(l, db),
+		database.NewActionRepo(l, db, downloadClientRepo),
+		database.NewNotificationRepo(l, db),
+	)
+}
+
 func readPassword() ([]byte, error) {
 	var password []byte
 	var err error
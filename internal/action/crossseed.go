@@ -0,0 +1,183 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/porla"
+
+	"github.com/autobrr/go-qbittorrent"
+	delugeClient "github.com/gdm85/go-libdeluge"
+	"github.com/hekmon/transmissionrpc/v2"
+	"github.com/mrobinsn/go-rtorrent/rtorrent"
+)
+
+// crossSeedSizeTolerance allows for announced sizes being rounded
+const crossSeedSizeTolerance = 0.01
+
+// crossSeedTorrent is a torrent already in a download client
+type crossSeedTorrent struct {
+	Name     string
+	Size     uint64
+	SavePath string
+}
+
+// crossSeedCheck looks for the release content among the client torrents.
+// If found it either rejects the release or points the action at the existing data so the client hash checks it.
+func (s *service) crossSeedCheck(action *domain.Action, release domain.Release, client string, torrents []crossSeedTorrent) []string {
+	match := findCrossSeedMatch(release, torrents)
+	if match == nil {
+		return nil
+	}
+
+	switch action.CrossSeed {
+	case domain.ActionCrossSeedSkip:
+		rejection := fmt.Sprintf("cross-seed: %s already exists in client %s", match.Name, client)
+
+		s.log.Debug().Msg(rejection)
+
+		return []string{rejection}
+
+	case domain.ActionCrossSeedInject:
+		s.log.Info().Msgf("cross-seed: injecting '%s' into existing data at %s on client %s", release.TorrentName, match.SavePath, client)
+
+		action.SavePath = match.SavePath
+		action.ContentLayout = domain.ActionContentLayoutOriginal
+		action.SkipHashCheck = false
+	}
+
+	return nil
+}
+
+// findCrossSeedMatch finds a torrent with the same name and size as the release
+func findCrossSeedMatch(release domain.Release, torrents []crossSeedTorrent) *crossSeedTorrent {
+	for i := range torrents {
+		t := &torrents[i]
+
+		if !sameContentName(release.TorrentName, t.Name) {
+			continue
+		}
+
+		if release.Size > 0 && t.Size > 0 && !sameContentSize(release.Size, t.Size) {
+			continue
+		}
+
+		return t
+	}
+
+	return nil
+}
+
+// sameContentName compares names case-insensitive, allowing single file torrents to carry a file extension
+func sameContentName(releaseName, torrentName string) bool {
+	a := strings.ToLower(releaseName)
+	b := strings.ToLower(torrentName)
+
+	if a == b {
+		return true
+	}
+
+	ext := strings.TrimPrefix(b, a)
+	if ext == b || len(ext) > 5 || !strings.HasPrefix(ext, ".") {
+		return false
+	}
+
+	return !strings.Contains(ext[1:], ".")
+}
+
+func sameContentSize(a, b uint64) bool {
+	diff := float64(a) - float64(b)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= float64(b)*crossSeedSizeTolerance
+}
+
+func (s *service) qbittorrentCheckCrossSeed(ctx context.Context, action *domain.Action, release domain.Release, client *domain.DownloadClient, qbt *qbittorrent.Client) ([]string, error) {
+	torrents, err := qbt.GetTorrentsCtx(ctx, qbittorrent.TorrentFilterOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch torrents")
+	}
+
+	existing := make([]crossSeedTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		existing = append(existing, crossSeedTorrent{Name: t.Name, Size: uint64(t.TotalSize), SavePath: t.SavePath})
+	}
+
+	return s.crossSeedCheck(action, release, client.Name, existing), nil
+}
+
+func (s *service) delugeCheckCrossSeed(deluge delugeClient.DelugeClient, action *domain.Action, release domain.Release, client *domain.DownloadClient) ([]string, error) {
+	torrents, err := deluge.TorrentsStatus(delugeClient.StateUnspecified, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch torrents")
+	}
+
+	existing := make([]crossSeedTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		existing = append(existing, crossSeedTorrent{Name: t.Name, Size: uint64(t.TotalSize), SavePath: t.DownloadLocation})
+	}
+
+	return s.crossSeedCheck(action, release, client.Name, existing), nil
+}
+
+func (s *service) transmissionCheckCrossSeed(ctx context.Context, action *domain.Action, release domain.Release, client *domain.DownloadClient, tbt *transmissionrpc.Client) ([]string, error) {
+	torrents, err := tbt.TorrentGet(ctx, []string{"name", "totalSize", "downloadDir"}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch torrents")
+	}
+
+	existing := make([]crossSeedTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		if t.Name == nil || t.DownloadDir == nil {
+			continue
+		}
+
+		var size uint64
+		if t.TotalSize != nil {
+			// total size is reported in bits
+			size = uint64(*t.TotalSize) / 8
+		}
+
+		existing = append(existing, crossSeedTorrent{Name: *t.Name, Size: size, SavePath: *t.DownloadDir})
+	}
+
+	return s.crossSeedCheck(action, release, client.Name, existing), nil
+}
+
+func (s *service) rtorrentCheckCrossSeed(action *domain.Action, release domain.Release, client *domain.DownloadClient, rt *rtorrent.RTorrent) ([]string, error) {
+	torrents, err := rt.GetTorrents(rtorrent.ViewMain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch torrents")
+	}
+
+	existing := make([]crossSeedTorrent, 0, len(torrents))
+	for _, t := range torrents {
+		// the base path points at the content itself, the torrent is added to its parent
+		existing = append(existing, crossSeedTorrent{Name: t.Name, Size: uint64(t.Size), SavePath: filepath.Dir(t.Path)})
+	}
+
+	return s.crossSeedCheck(action, release, client.Name, existing), nil
+}
+
+func (s *service) porlaCheckCrossSeed(ctx context.Context, action *domain.Action, release domain.Release, client *domain.DownloadClient, prl *porla.Client) ([]string, error) {
+	torrents, err := prl.TorrentsList(ctx, &porla.TorrentsListFilters{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch torrents")
+	}
+
+	existing := make([]crossSeedTorrent, 0, len(torrents.Torrents))
+	for _, t := range torrents.Torrents {
+		existing = append(existing, crossSeedTorrent{Name: t.Name, Size: uint64(t.Size), SavePath: t.SavePath})
+	}
+
+	return s.crossSeedCheck(action, release, client.Name, existing), nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findCrossSeedMatch(t *testing.T) {
+	torrents := []crossSeedTorrent{
+		{Name: "That.Show.S01E01.1080p.WEB.H264-GROUP", Size: 2_000_000_000, SavePath: "/data/tv"},
+		{Name: "That.Movie.2020.1080p.BluRay.x264-GROUP.mkv", Size: 8_000_000_000, SavePath: "/data/movies"},
+	}
+
+	tests := []struct {
+		name    string
+		release domain.Release
+		want    string
+	}{
+		{
+			name:    "same_name_and_size",
+			release: domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Size: 2_000_000_000},
+			want:    "/data/tv",
+		},
+		{
+			name:    "rounded_size",
+			release: domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Size: 1_990_000_000},
+			want:    "/data/tv",
+		},
+		{
+			name:    "unknown_size",
+			release: domain.Release{TorrentName: "that.show.s01e01.1080p.web.h264-group"},
+			want:    "/data/tv",
+		},
+		{
+			name:    "single_file_extension",
+			release: domain.Release{TorrentName: "That.Movie.2020.1080p.BluRay.x264-GROUP", Size: 8_000_000_000},
+			want:    "/data/movies",
+		},
+		{
+			name:    "different_size",
+			release: domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Size: 3_000_000_000},
+		},
+		{
+			name:    "different_name",
+			release: domain.Release{TorrentName: "That.Show.S01E02.1080p.WEB.H264-GROUP", Size: 2_000_000_000},
+		},
+		{
+			name:    "name_prefix_only",
+			release: domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB", Size: 2_000_000_000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findCrossSeedMatch(tt.release, torrents)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.SavePath)
+			}
+		})
+	}
+}
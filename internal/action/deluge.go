@@ -119,6 +119,17 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	if action.CrossSeed != "" {
+		rejections, err := s.delugeCheckCrossSeed(deluge, action, release, client)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -152,7 +163,7 @@ func (s *service) delugeV1(ctx context.Context, client *domain.DownloadClient, a
 		return nil, nil
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				s.log.Error().Err(err).Msgf("could not download torrent file for release: %s", release.TorrentName)
 				return nil, err
 			}
@@ -232,6 +243,17 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 		return rejections, nil
 	}
 
+	if action.CrossSeed != "" {
+		rejections, err := s.delugeCheckCrossSeed(deluge, action, release, client)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareDelugeOptions(action)
 		if err != nil {
@@ -265,7 +287,7 @@ func (s *service) delugeV2(ctx context.Context, client *domain.DownloadClient, a
 		return nil, nil
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				s.log.Error().Err(err).Msgf("could not download torrent file for release: %s", release.TorrentName)
 				return nil, err
 			}
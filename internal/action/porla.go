@@ -51,6 +51,17 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 		return rejections, nil
 	}
 
+	if action.CrossSeed != "" {
+		rejections, err = s.porlaCheckCrossSeed(ctx, action, release, client, prl)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		opts := &porla.TorrentsAddReq{
 			DownloadLimit: -1,
@@ -76,7 +87,7 @@ func (s *service) porla(ctx context.Context, action *domain.Action, release doma
 		return nil, nil
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				return nil, errors.Wrap(err, "error downloading torrent file for release: %s", release.TorrentName)
 			}
 		}
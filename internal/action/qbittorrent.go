@@ -27,6 +27,17 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		return rejections, nil
 	}
 
+	if action.CrossSeed != "" {
+		rejections, err := s.qbittorrentCheckCrossSeed(ctx, action, release, c.Dc, c.Qbt)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		options, err := s.prepareQbitOptions(action)
 		if err != nil {
@@ -44,7 +55,7 @@ func (s *service) qbittorrent(ctx context.Context, action *domain.Action, releas
 		return nil, nil
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				return nil, errors.Wrap(err, "error downloading torrent file for release: %s", release.TorrentName)
 			}
 		}
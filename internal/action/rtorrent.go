@@ -34,6 +34,17 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 	// create client
 	rt := rtorrent.New(client.Host, true)
 
+	if action.CrossSeed != "" {
+		rejections, err = s.rtorrentCheckCrossSeed(action, release, client, rt)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		var args []*rtorrent.FieldValue
 
@@ -74,7 +85,7 @@ func (s *service) rtorrent(ctx context.Context, action *domain.Action, release d
 
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				s.log.Error().Err(err).Msgf("could not download torrent file for release: %s", release.TorrentName)
 				return nil, err
 			}
@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -40,6 +42,13 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		return nil, err
 	}
 
+	// download the file the macros need here, so the download is observed
+	if release.TorrentTmpFile == "" && action.NeedsTorrentFile() {
+		if err := s.downloadTorrentFile(ctx, release); err != nil {
+			return nil, errors.Wrap(err, "could not download torrent file for release: %v", release.TorrentName)
+		}
+	}
+
 	// parse all macros in one go
 	if err := action.ParseMacros(release); err != nil {
 		return nil, err
@@ -100,6 +109,7 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 		Event:          domain.NotificationEventPushApproved,
 		ReleaseName:    release.TorrentName,
 		Filter:         release.Filter.Name,
+		FilterID:       release.Filter.ID,
 		Indexer:        release.Indexer,
 		InfoHash:       release.TorrentHash,
 		Size:           release.Size,
@@ -133,9 +143,44 @@ func (s *service) RunAction(ctx context.Context, action *domain.Action, release
 	// send separate event for notifications
 	s.bus.Publish("events:notification", &payload.Event, payload)
 
+	if err != nil && action.Client != nil && isConnectionError(err) {
+		clientPayload := &domain.NotificationPayload{
+			Subject:      "Download client error",
+			Message:      fmt.Sprintf("Could not connect to download client %v: %v", action.Client.Name, err),
+			Event:        domain.NotificationEventClientError,
+			ReleaseName:  release.TorrentName,
+			Filter:       release.Filter.Name,
+			FilterID:     release.Filter.ID,
+			Indexer:      release.Indexer,
+			Action:       action.Name,
+			ActionType:   action.Type,
+			ActionClient: action.Client.Name,
+			Timestamp:    time.Now(),
+		}
+
+		s.bus.Publish("events:notification", &clientPayload.Event, clientPayload)
+	}
+
 	return rejections, err
 }
 
+// isConnectionError checks if the error was caused by not being able to reach the download client
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// downloadTorrentFile downloads the torrent file of the release and observes how long it took
+func (s *service) downloadTorrentFile(ctx context.Context, release *domain.Release) error {
+	start := time.Now()
+
+	err := release.DownloadTorrentFileCtx(ctx)
+
+	metrics.ObserveTorrentDownload(release.Indexer, start, err)
+
+	return err
+}
+
 func (s *service) test(name string) {
 	s.log.Info().Msgf("action TEST: %v", name)
 }
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package action
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "url_error",
+			err:  &url.Error{Op: "Post", URL: "http://localhost:8080", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}},
+			want: true,
+		},
+		{
+			name: "wrapped_url_error",
+			err:  errors.Wrap(&url.Error{Op: "Get", URL: "http://localhost:8080", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}, "could not login"),
+			want: true,
+		},
+		{
+			name: "other_error",
+			err:  errors.New("torrent already exists"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isConnectionError(tt.err))
+		})
+	}
+}
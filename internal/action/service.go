@@ -19,6 +19,7 @@ import (
 type Service interface {
 	Store(ctx context.Context, action domain.Action) (*domain.Action, error)
 	List(ctx context.Context) ([]domain.Action, error)
+	FindByID(ctx context.Context, actionID int) (*domain.Action, error)
 	Delete(actionID int) error
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	ToggleEnabled(actionID int) error
@@ -51,6 +52,10 @@ func (s *service) Store(ctx context.Context, action domain.Action) (*domain.Acti
 	return s.repo.Store(ctx, action)
 }
 
+func (s *service) FindByID(ctx context.Context, actionID int) (*domain.Action, error) {
+	return s.repo.FindByID(ctx, actionID)
+}
+
 func (s *service) Delete(actionID int) error {
 	return s.repo.Delete(actionID)
 }
@@ -38,6 +38,17 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 		return nil, errors.Wrap(err, "error logging into client: %s", client.Host)
 	}
 
+	if action.CrossSeed != "" {
+		rejections, err = s.transmissionCheckCrossSeed(ctx, action, release, client, tbt)
+		if err != nil {
+			return nil, errors.Wrap(err, "error checking cross-seed: %s", action.Name)
+		}
+
+		if len(rejections) > 0 {
+			return rejections, nil
+		}
+	}
+
 	if release.HasMagnetUri() {
 		payload := transmissionrpc.TorrentAddPayload{
 			Filename: &release.MagnetURI,
@@ -61,7 +72,7 @@ func (s *service) transmission(ctx context.Context, action *domain.Action, relea
 
 	} else {
 		if release.TorrentTmpFile == "" {
-			if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+			if err := s.downloadTorrentFile(ctx, &release); err != nil {
 				s.log.Error().Err(err).Msgf("could not download torrent file for release: %s", release.TorrentName)
 				return nil, err
 			}
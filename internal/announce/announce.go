@@ -11,6 +11,7 @@ import (
 	"text/template"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
 
@@ -98,6 +99,7 @@ func (a *announceProcessor) processQueue(queue chan string) {
 		}
 
 		if parseFailed {
+			metrics.AnnouncesTotal.WithLabelValues(a.indexer.Identifier, "failed").Inc()
 			continue
 		}
 
@@ -107,9 +109,12 @@ func (a *announceProcessor) processQueue(queue chan string) {
 		// on lines matched
 		if err := a.onLinesMatched(a.indexer, tmpVars, rls); err != nil {
 			a.log.Error().Err(err).Msg("error match line")
+			metrics.AnnouncesTotal.WithLabelValues(a.indexer.Identifier, "failed").Inc()
 			continue
 		}
 
+		metrics.AnnouncesTotal.WithLabelValues(a.indexer.Identifier, "parsed").Inc()
+
 		// process release in a new go routine
 		go a.releaseSvc.Process(rls)
 	}
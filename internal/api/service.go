@@ -10,6 +10,7 @@ import (
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/rs/zerolog"
 )
@@ -20,6 +21,7 @@ type Service interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	GetKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type service struct {
@@ -72,17 +74,25 @@ func (s *service) Delete(ctx context.Context, key string) error {
 }
 
 func (s *service) ValidateAPIKey(ctx context.Context, key string) bool {
+	if _, err := s.GetKey(ctx, key); err != nil {
+		return false
+	}
+	return true
+}
+
+func (s *service) GetKey(ctx context.Context, key string) (*domain.APIKey, error) {
 	keys, err := s.repo.GetKeys(ctx)
 	if err != nil {
-		return false
+		return nil, err
 	}
 
 	for _, k := range keys {
 		if k.Key == key {
-			return true
+			return &k, nil
 		}
 	}
-	return false
+
+	return nil, errors.New("api key not found")
 }
 
 func GenerateSecureToken(length int) string {
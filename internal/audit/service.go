@@ -0,0 +1,48 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package audit
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	Record(ctx context.Context, event *domain.AuditEvent)
+	List(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEvent, error)
+}
+
+type service struct {
+	log  zerolog.Logger
+	repo domain.AuditRepo
+}
+
+func NewService(log logger.Logger, repo domain.AuditRepo) Service {
+	return &service{
+		log:  log.With().Str("module", "audit").Logger(),
+		repo: repo,
+	}
+}
+
+// Record stores the event. Failures are logged but never block the change that was made.
+func (s *service) Record(ctx context.Context, event *domain.AuditEvent) {
+	if err := s.repo.Store(ctx, event); err != nil {
+		s.log.Error().Err(err).Msgf("could not store audit event: %+v", event)
+		return
+	}
+
+	s.log.Debug().Msgf("audit: %v %v %v %v", event.Username, event.Action, event.Resource, event.Path)
+}
+
+func (s *service) List(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEvent, error) {
+	if params.Limit == 0 {
+		params.Limit = 100
+	}
+
+	return s.repo.List(ctx, params)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCIdentity is the verified identity from an id token
+type OIDCIdentity struct {
+	Subject  string
+	Username string
+	Email    string
+	Groups   []string
+}
+
+type oidcClaims struct {
+	PreferredUsername string          `json:"preferred_username"`
+	Email             string          `json:"email"`
+	EmailVerified     bool            `json:"email_verified"`
+	Groups            json.RawMessage `json:"groups"`
+}
+
+// oidcProvider implements the OpenID Connect authorization code flow against the configured issuer
+type oidcProvider struct {
+	config *domain.Config
+	client *http.Client
+
+	m        sync.Mutex
+	provider *oidc.Provider
+}
+
+func newOIDCProvider(config *domain.Config) *oidcProvider {
+	return &oidcProvider{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// getProvider runs the discovery once, the provider keeps the signing keys up to date itself
+func (p *oidcProvider) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, p.client), p.config.OIDCIssuer)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get openid configuration")
+	}
+
+	p.provider = provider
+
+	return p.provider, nil
+}
+
+func (p *oidcProvider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.config.OIDCClientID,
+		ClientSecret: p.config.OIDCClientSecret,
+		RedirectURL:  p.config.OIDCRedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.scopes(),
+	}
+}
+
+// scopes only asks for groups when they are checked, it is not a standard scope and some providers reject it
+func (p *oidcProvider) scopes() []string {
+	scopes := []string{oidc.ScopeOpenID, "profile", "email"}
+	if len(p.config.OIDCAllowedGroups) > 0 {
+		scopes = append(scopes, "groups")
+	}
+
+	return scopes
+}
+
+// AuthCodeURL returns the url to redirect the user to the identity provider
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	provider, err := p.getProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return p.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce)), nil
+}
+
+// Exchange trades the authorization code for an id token and returns the verified identity
+func (p *oidcProvider) Exchange(ctx context.Context, code, nonce string) (*OIDCIdentity, error) {
+	provider, err := p.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.oauth2Config(provider).Exchange(oidc.ClientContext(ctx, p.client), code)
+	if err != nil {
+		return nil, errors.Wrap(err, "token request failed")
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	// users are linked by subject, the only claim that is stable and can't be changed by the user
+	if idToken.Subject == "" {
+		return nil, errors.New("id token has no sub claim")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token claims")
+	}
+
+	identity := &OIDCIdentity{
+		Subject:  idToken.Subject,
+		Username: claims.PreferredUsername,
+		Groups:   parseGroups(claims.Groups),
+	}
+
+	if claims.EmailVerified {
+		identity.Email = claims.Email
+	}
+
+	if identity.Username == "" {
+		identity.Username = identity.Email
+	}
+
+	if identity.Username == "" {
+		return nil, errors.New("id token has no preferred_username or verified email claim")
+	}
+
+	if !p.inAllowedGroups(identity.Groups) {
+		return nil, errors.New("user %s is not in any allowed group", identity.Username)
+	}
+
+	return identity, nil
+}
+
+// verify checks the signature, issuer, audience and expiry of the id token
+func (p *oidcProvider) verify(ctx context.Context, rawIDToken string) (*oidc.IDToken, error) {
+	provider, err := p.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.config.OIDCClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid id token")
+	}
+
+	return idToken, nil
+}
+
+func (p *oidcProvider) inAllowedGroups(groups []string) bool {
+	if len(p.config.OIDCAllowedGroups) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.config.OIDCAllowedGroups {
+		for _, g := range groups {
+			if g == allowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseGroups reads the groups claim, which some providers send as a single string
+func parseGroups(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var groups []string
+	if err := json.Unmarshal(raw, &groups); err == nil {
+		return groups
+	}
+
+	var group string
+	if err := json.Unmarshal(raw, &group); err == nil && group != "" {
+		return []string{group}
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIssuer is a minimal stand-in identity provider
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{}
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	m := &mockIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "autobrr" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"id_token":     m.sign(t, m.claims),
+		})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+func (m *mockIssuer) sign(t *testing.T, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, hashed[:])
+	assert.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (m *mockIssuer) defaultClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                m.server.URL,
+		"sub":                "1234",
+		"aud":                "autobrr",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              "nonce",
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"groups":             []string{"media", "autobrr"},
+	}
+}
+
+func Test_oidcProvider_AuthCodeURL(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	p := newOIDCProvider(&domain.Config{
+		OIDCIssuer:      issuer.server.URL,
+		OIDCClientID:    "autobrr",
+		OIDCRedirectURL: "http://localhost:7474/api/auth/oidc/callback",
+	})
+
+	got, err := p.AuthCodeURL(context.Background(), "state", "nonce")
+	assert.NoError(t, err)
+
+	u, err := url.Parse(got)
+	assert.NoError(t, err)
+	assert.Equal(t, "/authorize", u.Path)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "autobrr", u.Query().Get("client_id"))
+	assert.Equal(t, "state", u.Query().Get("state"))
+	assert.Equal(t, "nonce", u.Query().Get("nonce"))
+	assert.Equal(t, "http://localhost:7474/api/auth/oidc/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+
+	// groups is not a standard scope, so it is only requested when groups are checked
+	p.config.OIDCAllowedGroups = []string{"autobrr"}
+
+	got, err = p.AuthCodeURL(context.Background(), "state", "nonce")
+	assert.NoError(t, err)
+
+	u, err = url.Parse(got)
+	assert.NoError(t, err)
+	assert.Equal(t, "openid profile email groups", u.Query().Get("scope"))
+}
+
+func Test_oidcProvider_Exchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	tests := []struct {
+		name          string
+		allowedGroups []string
+		code          string
+		nonce         string
+		claims        func(c map[string]interface{})
+		want          *OIDCIdentity
+		wantErr       string
+	}{
+		{
+			name:  "valid",
+			code:  "valid-code",
+			nonce: "nonce",
+			want:  &OIDCIdentity{Subject: "1234", Username: "alice", Email: "alice@example.com", Groups: []string{"media", "autobrr"}},
+		},
+		{
+			name:          "allowed_group",
+			allowedGroups: []string{"autobrr"},
+			code:          "valid-code",
+			nonce:         "nonce",
+			want:          &OIDCIdentity{Subject: "1234", Username: "alice", Email: "alice@example.com", Groups: []string{"media", "autobrr"}},
+		},
+		{
+			name:          "not_in_allowed_group",
+			allowedGroups: []string{"admins"},
+			code:          "valid-code",
+			nonce:         "nonce",
+			wantErr:       "user alice is not in any allowed group",
+		},
+		{
+			name:  "email_fallback",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				delete(c, "preferred_username")
+				c["groups"] = "media"
+			},
+			want: &OIDCIdentity{Subject: "1234", Username: "alice@example.com", Email: "alice@example.com", Groups: []string{"media"}},
+		},
+		{
+			name:  "unverified_email",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				delete(c, "preferred_username")
+				c["email_verified"] = false
+			},
+			wantErr: "no preferred_username or verified email claim",
+		},
+		{
+			name:  "missing_subject",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				delete(c, "sub")
+			},
+			wantErr: "no sub claim",
+		},
+		{
+			name:    "bad_code",
+			code:    "bad-code",
+			nonce:   "nonce",
+			wantErr: "token request failed",
+		},
+		{
+			name:    "nonce_mismatch",
+			code:    "valid-code",
+			nonce:   "other",
+			wantErr: "nonce mismatch",
+		},
+		{
+			name:  "wrong_audience",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				c["aud"] = []string{"other-client"}
+			},
+			wantErr: "expected audience",
+		},
+		{
+			name:  "expired",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				c["exp"] = time.Now().Add(-time.Minute).Unix()
+			},
+			wantErr: "token is expired",
+		},
+		{
+			name:  "wrong_issuer",
+			code:  "valid-code",
+			nonce: "nonce",
+			claims: func(c map[string]interface{}) {
+				c["iss"] = "https://evil.example.com"
+			},
+			wantErr: "issued by a different provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer.claims = issuer.defaultClaims()
+			if tt.claims != nil {
+				tt.claims(issuer.claims)
+			}
+
+			p := newOIDCProvider(&domain.Config{
+				OIDCIssuer:        issuer.server.URL,
+				OIDCClientID:      "autobrr",
+				OIDCClientSecret:  "secret",
+				OIDCRedirectURL:   "http://localhost:7474/api/auth/oidc/callback",
+				OIDCAllowedGroups: tt.allowedGroups,
+			})
+
+			got, err := p.Exchange(context.Background(), tt.code, tt.nonce)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_oidcProvider_verify_tampered(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	p := newOIDCProvider(&domain.Config{
+		OIDCIssuer:   issuer.server.URL,
+		OIDCClientID: "autobrr",
+	})
+
+	token := issuer.sign(t, issuer.defaultClaims())
+
+	// swap the payload for one with another username, keeping the signature
+	claims := issuer.defaultClaims()
+	claims["preferred_username"] = "mallory"
+	payload, _ := json.Marshal(claims)
+
+	parts := splitToken(token)
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+
+	_, err := p.verify(context.Background(), tampered)
+	assert.ErrorContains(t, err, "failed to verify signature")
+}
+
+func splitToken(token string) []string {
+	var parts []string
+	start := 0
+	for i, c := range token {
+		if c == '.' {
+			parts = append(parts, token[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, token[start:])
+}
+
+func Test_service_loginOIDCIdentity(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		autoCreate bool
+		identity   *OIDCIdentity
+		wantID     int
+		wantErr    string
+	}{
+		{name: "linked", identity: &OIDCIdentity{Subject: "sub-bob", Username: "someone-else"}, wantID: 2},
+		{name: "same_username_not_linked", identity: &OIDCIdentity{Subject: "sub-evil", Username: "admin"}, wantErr: "not linked to a user"},
+		{name: "same_username_auto_create", autoCreate: true, identity: &OIDCIdentity{Subject: "sub-evil", Username: "admin"}, wantErr: "username is taken by a local user"},
+		{name: "auto_create", autoCreate: true, identity: &OIDCIdentity{Subject: "sub-carol", Username: "carol"}, wantID: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mock.UserRepo{Users: []domain.User{
+				{ID: 1, Username: "admin", Role: domain.UserRoleAdmin},
+				{ID: 2, Username: "bob", Role: domain.UserRoleEditor, OIDCSubject: "sub-bob"},
+			}}
+			s := newTestService(repo)
+			s.config.OIDCAutoCreateUsers = tt.autoCreate
+
+			got, err := s.loginOIDCIdentity(ctx, tt.identity)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantID, got.ID)
+			assert.Equal(t, tt.identity.Subject, got.OIDCSubject)
+		})
+	}
+}
@@ -5,6 +5,8 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -19,17 +21,31 @@ type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	FindUserByID(ctx context.Context, id int) (*domain.User, error)
+	OIDCEnabled() bool
+	OIDCAuthURL(ctx context.Context, state, nonce string) (string, error)
+	LoginOIDC(ctx context.Context, code, nonce string) (*domain.User, error)
+	LinkOIDC(ctx context.Context, userID int, code, nonce string) error
+	UnlinkOIDC(ctx context.Context, userID int) error
+	SetupTwoFactor(ctx context.Context, userID int) (*domain.TwoFactorSetup, error)
+	EnableTwoFactor(ctx context.Context, userID int, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID int, code string) error
+	VerifyTwoFactor(ctx context.Context, userID int, code string) error
 }
 
 type service struct {
 	log     zerolog.Logger
+	config  *domain.Config
 	userSvc user.Service
+	oidc    *oidcProvider
 }
 
-func NewService(log logger.Logger, userSvc user.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, userSvc user.Service) Service {
 	return &service{
 		log:     log.With().Str("module", "auth").Logger(),
+		config:  config,
 		userSvc: userSvc,
+		oidc:    newOIDCProvider(config),
 	}
 }
 
@@ -79,8 +95,9 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 		return err
 	}
 
+	// onboarding only creates the first user, the rest are added by an admin
 	if userCount > 0 {
-		return errors.New("only 1 user account is supported at the moment")
+		return errors.New("onboarding unavailable: users already exist")
 	}
 
 	hashed, err := argon2id.CreateHash(req.Password, argon2id.DefaultParams)
@@ -89,6 +106,7 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 	}
 
 	req.Password = hashed
+	req.Role = domain.UserRoleAdmin
 
 	if err := s.userSvc.CreateUser(ctx, req); err != nil {
 		s.log.Error().Err(err).Msgf("could not create user: %s", req.Username)
@@ -97,3 +115,107 @@ func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest)
 
 	return nil
 }
+
+func (s *service) FindUserByID(ctx context.Context, id int) (*domain.User, error) {
+	return s.userSvc.FindByID(ctx, id)
+}
+
+func (s *service) OIDCEnabled() bool {
+	return s.config.OIDCEnabled && s.config.OIDCIssuer != "" && s.config.OIDCClientID != ""
+}
+
+func (s *service) OIDCAuthURL(ctx context.Context, state, nonce string) (string, error) {
+	if !s.OIDCEnabled() {
+		return "", errors.New("oidc login is not enabled")
+	}
+
+	return s.oidc.AuthCodeURL(ctx, state, nonce)
+}
+
+// LoginOIDC verifies the identity from the provider and maps it to the local user linked to its subject.
+// Unknown identities get a new user with the viewer role if auto create is enabled, existing local users
+// have to link their identity from the account settings first.
+// Two-factor authentication is left to the identity provider.
+func (s *service) LoginOIDC(ctx context.Context, code, nonce string) (*domain.User, error) {
+	if !s.OIDCEnabled() {
+		return nil, errors.New("oidc login is not enabled")
+	}
+
+	identity, err := s.oidc.Exchange(ctx, code, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loginOIDCIdentity(ctx, identity)
+}
+
+func (s *service) loginOIDCIdentity(ctx context.Context, identity *OIDCIdentity) (*domain.User, error) {
+	u, err := s.userSvc.FindByOIDCSubject(ctx, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if u != nil {
+		return u, nil
+	}
+
+	if !s.config.OIDCAutoCreateUsers {
+		return nil, errors.Errorf("oidc identity %s is not linked to a user", identity.Username)
+	}
+
+	// never take over a local user with the same name, it has to be linked by its owner
+	existing, err := s.userSvc.FindByUsername(ctx, identity.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.Errorf("oidc identity %s is not linked and the username is taken by a local user", identity.Username)
+	}
+
+	// the password is never used, oidc users log in through the provider
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return nil, errors.Wrap(err, "could not generate password")
+	}
+
+	req := domain.CreateUserRequest{
+		Username:    identity.Username,
+		Password:    hex.EncodeToString(password),
+		Role:        domain.UserRoleViewer,
+		OIDCSubject: identity.Subject,
+	}
+
+	if err := s.userSvc.Create(ctx, req); err != nil {
+		return nil, errors.Wrapf(err, "could not create user: %s", identity.Username)
+	}
+
+	s.log.Info().Msgf("created user %s from oidc login", identity.Username)
+
+	return s.userSvc.FindByOIDCSubject(ctx, identity.Subject)
+}
+
+// LinkOIDC links the identity from the provider to a user that is already logged in
+func (s *service) LinkOIDC(ctx context.Context, userID int, code, nonce string) error {
+	if !s.OIDCEnabled() {
+		return errors.New("oidc login is not enabled")
+	}
+
+	identity, err := s.oidc.Exchange(ctx, code, nonce)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userSvc.LinkOIDC(ctx, userID, identity.Subject); err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("linked oidc identity %s to user %d", identity.Username, userID)
+
+	return nil
+}
+
+// UnlinkOIDC removes the link between the user and its oidc identity
+func (s *service) UnlinkOIDC(ctx context.Context, userID int) error {
+	return s.userSvc.LinkOIDC(ctx, userID, "")
+}
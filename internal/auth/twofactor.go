@@ -0,0 +1,172 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/totp"
+
+	"github.com/pkg/errors"
+)
+
+const recoveryCodeCount = 10
+
+// SetupTwoFactor generates a new secret for the user. It is not active until confirmed with EnableTwoFactor.
+func (s *service) SetupTwoFactor(ctx context.Context, userID int) (*domain.TwoFactorSetup, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate secret")
+	}
+
+	if err := s.userSvc.SetTwoFactor(ctx, u.ID, false, secret, nil); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetup{
+		Secret: secret,
+		URL:    totp.URL("autobrr", u.Username, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms the pending secret with a code from the authenticator app
+// and returns a new set of recovery codes. The codes are only shown this once.
+func (s *service) EnableTwoFactor(ctx context.Context, userID int, code string) ([]string, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	if u.TwoFactorSecret == "" {
+		return nil, errors.New("two-factor authentication has not been set up")
+	}
+
+	if !totp.Validate(code, u.TwoFactorSecret, time.Now()) {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userSvc.SetTwoFactor(ctx, u.ID, true, u.TwoFactorSecret, hashed); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().Msgf("two-factor authentication enabled for user: %s", u.Username)
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication after checking a code or recovery code
+func (s *service) DisableTwoFactor(ctx context.Context, userID int, code string) error {
+	if err := s.VerifyTwoFactor(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := s.userSvc.SetTwoFactor(ctx, userID, false, "", nil); err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("two-factor authentication disabled for user id: %d", userID)
+
+	return nil
+}
+
+// VerifyTwoFactor checks a code from the authenticator app or a recovery code.
+// Recovery codes can only be used once.
+func (s *service) VerifyTwoFactor(ctx context.Context, userID int, code string) error {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.TwoFactorEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if totp.Validate(code, u.TwoFactorSecret, time.Now()) {
+		return nil
+	}
+
+	hashed := hashRecoveryCode(code)
+	for i, c := range u.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(c), []byte(hashed)) != 1 {
+			continue
+		}
+
+		remaining := append(append([]string{}, u.RecoveryCodes[:i]...), u.RecoveryCodes[i+1:]...)
+		if err := s.userSvc.SetTwoFactor(ctx, u.ID, true, u.TwoFactorSecret, remaining); err != nil {
+			return err
+		}
+
+		s.log.Warn().Msgf("recovery code used by user: %s, %d remaining", u.Username, len(remaining))
+
+		return nil
+	}
+
+	return errors.New("invalid two-factor code")
+}
+
+func (s *service) findUser(ctx context.Context, userID int) (*domain.User, error) {
+	u, err := s.userSvc.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, errors.Errorf("user not found: %d", userID)
+	}
+
+	return u, nil
+}
+
+// generateRecoveryCodes returns the plain codes and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashed := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, errors.Wrap(err, "could not generate recovery code")
+		}
+
+		code := hex.EncodeToString(b)
+		code = code[:5] + "-" + code[5:]
+
+		codes = append(codes, code)
+		hashed = append(hashed, hashRecoveryCode(code))
+	}
+
+	return codes, hashed, nil
+}
+
+// hashRecoveryCode hashes the normalized code. The codes are random so a plain sha256 is enough.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/mock"
+	"github.com/autobrr/autobrr/internal/user"
+	"github.com/autobrr/autobrr/pkg/totp"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(repo domain.UserRepo) *service {
+	return &service{
+		log:     zerolog.Nop(),
+		config:  &domain.Config{},
+		userSvc: user.NewService(repo, mock.NewSessionRepo()),
+	}
+}
+
+func TestService_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	repo := &mock.UserRepo{Users: []domain.User{{ID: 1, Username: "alice", Role: domain.UserRoleAdmin}}}
+	s := newTestService(repo)
+
+	// verifying before enrollment fails
+	assert.ErrorContains(t, s.VerifyTwoFactor(ctx, 1, "123456"), "not enabled")
+
+	setup, err := s.SetupTwoFactor(ctx, 1)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, setup.Secret)
+	assert.Contains(t, setup.URL, "otpauth://totp/autobrr:alice")
+	assert.False(t, repo.Users[0].TwoFactorEnabled)
+
+	// a wrong code does not enable it
+	_, err = s.EnableTwoFactor(ctx, 1, "000000")
+	assert.ErrorContains(t, err, "invalid two-factor code")
+
+	code, err := totp.GenerateCode(setup.Secret, time.Now())
+	assert.NoError(t, err)
+
+	recoveryCodes, err := s.EnableTwoFactor(ctx, 1, code)
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+	assert.True(t, repo.Users[0].TwoFactorEnabled)
+	assert.NotContains(t, repo.Users[0].RecoveryCodes, recoveryCodes[0], "recovery codes must be stored hashed")
+
+	_, err = s.SetupTwoFactor(ctx, 1)
+	assert.ErrorContains(t, err, "already enabled")
+
+	assert.NoError(t, s.VerifyTwoFactor(ctx, 1, code))
+	assert.Error(t, s.VerifyTwoFactor(ctx, 1, "000000"))
+
+	// recovery codes work once
+	assert.NoError(t, s.VerifyTwoFactor(ctx, 1, recoveryCodes[0]))
+	assert.Len(t, repo.Users[0].RecoveryCodes, recoveryCodeCount-1)
+	assert.Error(t, s.VerifyTwoFactor(ctx, 1, recoveryCodes[0]))
+
+	// disable requires a valid code
+	assert.Error(t, s.DisableTwoFactor(ctx, 1, "000000"))
+	assert.NoError(t, s.DisableTwoFactor(ctx, 1, recoveryCodes[1]))
+	assert.False(t, repo.Users[0].TwoFactorEnabled)
+	assert.Empty(t, repo.Users[0].TwoFactorSecret)
+	assert.Empty(t, repo.Users[0].RecoveryCodes)
+}
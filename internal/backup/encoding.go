@@ -0,0 +1,88 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatZip  Format = "zip"
+)
+
+// zipEntry is the name of the backup inside zip archives
+const zipEntry = "autobrr-backup.json"
+
+// Encode writes the backup as indented json, or as json inside a zip archive
+func Encode(w io.Writer, b *domain.Backup, format Format) error {
+	switch format {
+	case FormatJSON, "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(b)
+
+	case FormatZip:
+		zw := zip.NewWriter(w)
+
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     zipEntry,
+			Method:   zip.Deflate,
+			Modified: b.CreatedAt,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not create zip entry")
+		}
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(b); err != nil {
+			return errors.Wrap(err, "could not encode backup")
+		}
+
+		return zw.Close()
+	}
+
+	return errors.New("unsupported backup format: %s", format)
+}
+
+// Decode reads a backup written by Encode, detecting zip archives by their signature
+func Decode(data []byte) (*domain.Backup, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read zip archive")
+		}
+
+		f, err := zr.Open(zipEntry)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find %s in zip archive", zipEntry)
+		}
+
+		defer f.Close()
+
+		if data, err = io.ReadAll(f); err != nil {
+			return nil, errors.Wrap(err, "could not read %s", zipEntry)
+		}
+	}
+
+	var b domain.Backup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, errors.Wrap(err, "could not decode backup")
+	}
+
+	if b.Version == 0 {
+		return nil, errors.New("not an autobrr backup")
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backup
+
+import (
+	"net/url"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// redact blanks secrets so the backup can be shared.
+// Indexer settings are mostly passkeys and tokens, so all of them are removed.
+func redact(b *domain.Backup) {
+	for i := range b.DownloadClients {
+		c := &b.DownloadClients[i]
+		c.Password = ""
+		c.Settings.APIKey = ""
+		c.Settings.Basic.Password = ""
+	}
+
+	for i := range b.Indexers {
+		for key := range b.Indexers[i].Settings {
+			b.Indexers[i].Settings[key] = ""
+		}
+	}
+
+	for i := range b.IrcNetworks {
+		n := &b.IrcNetworks[i]
+		n.Pass = ""
+		n.Auth.Password = ""
+		// invite commands usually contain the irc key
+		n.InviteCommand = ""
+
+		for j := range n.Channels {
+			n.Channels[j].Password = ""
+		}
+	}
+
+	for i := range b.Feeds {
+		b.Feeds[i].ApiKey = ""
+		b.Feeds[i].Cookie = ""
+		// rss urls usually carry the passkey in the query
+		b.Feeds[i].URL = redactURL(b.Feeds[i].URL)
+	}
+
+	for i := range b.Filters {
+		for _, a := range b.Filters[i].Actions {
+			a.WebhookHeaders = nil
+		}
+	}
+
+	for i := range b.Notifications {
+		n := &b.Notifications[i]
+		n.Token = ""
+		n.APIKey = ""
+		n.Password = ""
+		// webhook urls carry their own token
+		n.Webhook = ""
+	}
+}
+
+// redactURL strips the credentials and query from the url
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
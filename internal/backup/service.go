@@ -0,0 +1,411 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	Export(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error)
+	Import(ctx context.Context, backup *domain.Backup) (*domain.BackupImportResult, error)
+}
+
+type service struct {
+	log     zerolog.Logger
+	version string
+
+	downloadClientRepo domain.DownloadClientRepo
+	indexerRepo        domain.IndexerRepo
+	ircRepo            domain.IrcRepo
+	feedRepo           domain.FeedRepo
+	filterRepo         domain.FilterRepo
+	actionRepo         domain.ActionRepo
+	notificationRepo   domain.NotificationRepo
+}
+
+// NewService works directly on the repos so it can be used by autobrrctl against a stopped instance
+func NewService(log logger.Logger, version string, downloadClientRepo domain.DownloadClientRepo, indexerRepo domain.IndexerRepo, ircRepo domain.IrcRepo, feedRepo domain.FeedRepo, filterRepo domain.FilterRepo, actionRepo domain.ActionRepo, notificationRepo domain.NotificationRepo) Service {
+	return &service{
+		log:                log.With().Str("module", "backup").Logger(),
+		version:            version,
+		downloadClientRepo: downloadClientRepo,
+		indexerRepo:        indexerRepo,
+		ircRepo:            ircRepo,
+		feedRepo:           feedRepo,
+		filterRepo:         filterRepo,
+		actionRepo:         actionRepo,
+		notificationRepo:   notificationRepo,
+	}
+}
+
+func (s *service) Export(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error) {
+	b := &domain.Backup{
+		Version:    domain.BackupVersion,
+		AppVersion: s.version,
+		CreatedAt:  time.Now(),
+		Redacted:   opts.Redact,
+	}
+
+	var err error
+
+	if b.DownloadClients, err = s.downloadClientRepo.List(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	if b.Indexers, err = s.indexerRepo.List(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not list indexers")
+	}
+
+	if b.IrcNetworks, err = s.ircRepo.ListNetworks(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not list irc networks")
+	}
+
+	for i, network := range b.IrcNetworks {
+		channels, err := s.ircRepo.ListChannels(network.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list channels for network: %s", network.Name)
+		}
+
+		b.IrcNetworks[i].Channels = channels
+		b.IrcNetworks[i].Connected = false
+		b.IrcNetworks[i].ConnectedSince = nil
+	}
+
+	if b.Feeds, err = s.feedRepo.Find(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not list feeds")
+	}
+
+	for i := range b.Feeds {
+		b.Feeds[i].LastRunData = ""
+	}
+
+	filters, err := s.filterRepo.ListFilters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filters")
+	}
+
+	for _, f := range filters {
+		filter, err := s.filterRepo.FindByID(ctx, f.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %d", f.ID)
+		}
+
+		if filter.Actions, err = s.actionRepo.FindByFilterID(ctx, filter.ID); err != nil {
+			return nil, errors.Wrap(err, "could not find actions for filter: %s", filter.Name)
+		}
+
+		// the client is referenced by id and exported on its own
+		for _, a := range filter.Actions {
+			a.Client = nil
+		}
+
+		if filter.Indexers, err = s.indexerRepo.FindByFilterID(ctx, filter.ID); err != nil {
+			return nil, errors.Wrap(err, "could not find indexers for filter: %s", filter.Name)
+		}
+
+		b.Filters = append(b.Filters, *filter)
+	}
+
+	if b.Notifications, err = s.notificationRepo.List(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not list notifications")
+	}
+
+	if opts.Redact {
+		redact(b)
+	}
+
+	return b, nil
+}
+
+// Import recreates everything in the backup. Items that already exist are skipped
+// and the existing ones are used for references:
+// download clients and filters by name, indexers by identifier, irc networks by server and account, feeds and notifications by name.
+func (s *service) Import(ctx context.Context, b *domain.Backup) (*domain.BackupImportResult, error) {
+	if b.Version > domain.BackupVersion {
+		return nil, errors.New("unsupported backup version %d, this version of autobrr supports up to %d", b.Version, domain.BackupVersion)
+	}
+
+	result := &domain.BackupImportResult{
+		Created:  map[string]int{},
+		Skipped:  map[string]int{},
+		Warnings: make([]string, 0),
+	}
+
+	// redacted backups have no credentials, so nothing should start connecting before they are filled in
+	if b.Redacted {
+		result.Warnings = append(result.Warnings, "backup is redacted, imported indexers, irc networks and feeds are disabled until their credentials are filled in")
+	}
+
+	// old id to new id
+	clientIDs := map[int]int{}
+	indexerIDs := map[int64]int64{}
+	filterIDs := map[int]int{}
+
+	// identifier to new id, feeds reference indexers by identifier
+	indexersByIdentifier := map[string]int64{}
+
+	existingClients, err := s.downloadClientRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	clientsByName := map[string]int{}
+	for _, client := range existingClients {
+		clientsByName[strings.ToLower(client.Name)] = client.ID
+	}
+
+	for _, client := range b.DownloadClients {
+		if id, ok := clientsByName[strings.ToLower(client.Name)]; ok {
+			clientIDs[client.ID] = id
+			result.Skipped["download_clients"]++
+			continue
+		}
+
+		oldID := client.ID
+		client.ID = 0
+
+		created, err := s.downloadClientRepo.Store(ctx, client)
+		if err != nil {
+			return result, errors.Wrap(err, "could not store download client: %s", client.Name)
+		}
+
+		clientIDs[oldID] = created.ID
+		result.Created["download_clients"]++
+	}
+
+	existingIndexers, err := s.indexerRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list indexers")
+	}
+
+	for _, indexer := range existingIndexers {
+		indexersByIdentifier[indexer.Identifier] = indexer.ID
+	}
+
+	for _, indexer := range b.Indexers {
+		if id, ok := indexersByIdentifier[indexer.Identifier]; ok {
+			indexerIDs[indexer.ID] = id
+			result.Skipped["indexers"]++
+			continue
+		}
+
+		oldID := indexer.ID
+		indexer.ID = 0
+		if b.Redacted {
+			indexer.Enabled = false
+		}
+
+		created, err := s.indexerRepo.Store(ctx, indexer)
+		if err != nil {
+			return result, errors.Wrap(err, "could not store indexer: %s", indexer.Name)
+		}
+
+		indexerIDs[oldID] = created.ID
+		indexersByIdentifier[created.Identifier] = created.ID
+		result.Created["indexers"]++
+	}
+
+	for _, network := range b.IrcNetworks {
+		network := network
+
+		existing, err := s.ircRepo.CheckExistingNetwork(ctx, &network)
+		if err != nil {
+			return result, errors.Wrap(err, "could not check existing irc network: %s", network.Name)
+		}
+
+		if existing != nil {
+			result.Skipped["irc_networks"]++
+			continue
+		}
+
+		network.ID = 0
+		network.Connected = false
+		network.ConnectedSince = nil
+		if b.Redacted {
+			network.Enabled = false
+		}
+
+		if err := s.ircRepo.StoreNetwork(&network); err != nil {
+			return result, errors.Wrap(err, "could not store irc network: %s", network.Name)
+		}
+
+		if len(network.Channels) > 0 {
+			if err := s.ircRepo.StoreNetworkChannels(ctx, network.ID, network.Channels); err != nil {
+				return result, errors.Wrap(err, "could not store channels for irc network: %s", network.Name)
+			}
+		}
+
+		result.Created["irc_networks"]++
+	}
+
+	existingFeeds, err := s.feedRepo.Find(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list feeds")
+	}
+
+	feedNames := map[string]bool{}
+	for _, feed := range existingFeeds {
+		feedNames[strings.ToLower(feed.Name)] = true
+	}
+
+	for _, feed := range b.Feeds {
+		feed := feed
+
+		if feedNames[strings.ToLower(feed.Name)] {
+			result.Skipped["feeds"]++
+			continue
+		}
+
+		indexerID, ok := indexersByIdentifier[feed.Indexer]
+		if !ok {
+			s.log.Warn().Msgf("skipping feed %s, indexer %s is missing", feed.Name, feed.Indexer)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("feed %q: indexer %q not found, feed skipped", feed.Name, feed.Indexer))
+			result.Skipped["feeds"]++
+			continue
+		}
+
+		feed.ID = 0
+		feed.IndexerID = int(indexerID)
+		if b.Redacted {
+			feed.Enabled = false
+		}
+
+		if err := s.feedRepo.Store(ctx, &feed); err != nil {
+			return result, errors.Wrap(err, "could not store feed: %s", feed.Name)
+		}
+
+		// store does not take every field
+		if err := s.feedRepo.Update(ctx, &feed); err != nil {
+			return result, errors.Wrap(err, "could not update feed: %s", feed.Name)
+		}
+
+		result.Created["feeds"]++
+	}
+
+	existingFilters, err := s.filterRepo.ListFilters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list filters")
+	}
+
+	filtersByName := map[string]int{}
+	for _, filter := range existingFilters {
+		filtersByName[strings.ToLower(filter.Name)] = filter.ID
+	}
+
+	for _, filter := range b.Filters {
+		if id, ok := filtersByName[strings.ToLower(filter.Name)]; ok {
+			filterIDs[filter.ID] = id
+			result.Skipped["filters"]++
+			continue
+		}
+
+		oldID := filter.ID
+		actions := filter.Actions
+		indexers := filter.Indexers
+
+		filter.ID = 0
+		filter.Actions = nil
+		filter.Indexers = nil
+
+		created, err := s.filterRepo.Store(ctx, filter)
+		if err != nil {
+			return result, errors.Wrap(err, "could not store filter: %s", filter.Name)
+		}
+
+		filterIDs[oldID] = created.ID
+
+		connections := make([]domain.Indexer, 0, len(indexers))
+		for _, indexer := range indexers {
+			id, ok := indexerIDs[indexer.ID]
+			if !ok {
+				id, ok = indexersByIdentifier[indexer.Identifier]
+			}
+
+			if !ok {
+				s.log.Warn().Msgf("filter %s: indexer %s is missing", filter.Name, indexer.Identifier)
+				result.Warnings = append(result.Warnings, fmt.Sprintf("filter %q: indexer %q not found", filter.Name, indexer.Identifier))
+				continue
+			}
+
+			indexer.ID = id
+			connections = append(connections, indexer)
+		}
+
+		if err := s.filterRepo.StoreIndexerConnections(ctx, created.ID, connections); err != nil {
+			return result, errors.Wrap(err, "could not store indexers for filter: %s", filter.Name)
+		}
+
+		for _, action := range actions {
+			action.ID = 0
+			action.FilterID = created.ID
+			action.Client = nil
+
+			if action.ClientID > 0 {
+				id, ok := clientIDs[int(action.ClientID)]
+				if !ok {
+					// an action without its client can not push anything, so keep it off until a client is picked
+					s.log.Warn().Msgf("filter %s: action %s references a missing download client", filter.Name, action.Name)
+					result.Warnings = append(result.Warnings, fmt.Sprintf("filter %q: download client for action %q not found, action disabled", filter.Name, action.Name))
+					action.Enabled = false
+				}
+				action.ClientID = int32(id)
+			}
+		}
+
+		if len(actions) > 0 {
+			if _, err := s.actionRepo.StoreFilterActions(ctx, actions, int64(created.ID)); err != nil {
+				return result, errors.Wrap(err, "could not store actions for filter: %s", filter.Name)
+			}
+		}
+
+		result.Created["filters"]++
+	}
+
+	existingNotifications, err := s.notificationRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list notifications")
+	}
+
+	notificationNames := map[string]bool{}
+	for _, notification := range existingNotifications {
+		notificationNames[strings.ToLower(notification.Name)] = true
+	}
+
+	for _, notification := range b.Notifications {
+		if notificationNames[strings.ToLower(notification.Name)] {
+			result.Skipped["notifications"]++
+			continue
+		}
+
+		filters := make([]int, 0, len(notification.Filters))
+		for _, id := range notification.Filters {
+			if newID, ok := filterIDs[id]; ok {
+				filters = append(filters, newID)
+			}
+		}
+
+		notification.ID = 0
+		notification.Filters = filters
+
+		if _, err := s.notificationRepo.Store(ctx, notification); err != nil {
+			return result, errors.Wrap(err, "could not store notification: %s", notification.Name)
+		}
+
+		result.Created["notifications"]++
+	}
+
+	s.log.Info().Msgf("backup imported: created %v skipped %v", result.Created, result.Skipped)
+
+	return result, nil
+}
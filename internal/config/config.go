@@ -84,6 +84,157 @@ checkForUpdates = true
 # Session secret
 #
 sessionSecret = "{{ .sessionSecret }}"
+
+# Save rejected releases
+# Store every announced release, including those no filter matched, with the rejection reasons per filter.
+#
+# Default: false
+#
+#saveRejectedReleases = false
+
+# Rejected releases retention
+# Days to keep releases rejected by all filters. Set to 0 to keep them forever.
+#
+# Default: 7
+#
+#rejectedReleasesRetention = 7
+
+# Duplicate protection
+# Reject releases already grabbed from any indexer within the duplicate window.
+# Filters can allow duplicates from other indexers.
+#
+# Default: false
+#
+#dupeProtection = false
+
+# Duplicate window
+# Hours to look back for an already grabbed release. Set to 0 to look back forever.
+#
+# Default: 24
+#
+#dupeWindow = 24
+
+# Duplicate episode matching
+# Also treat releases with the same title, season, episode, resolution and source as duplicates,
+# not only releases with the same name.
+#
+# Default: false
+#
+#dupeMatchEpisode = false
+
+# Feed failure threshold
+# Consecutive failed runs of a feed before the feed failed notification is sent. Set to 0 to disable.
+#
+# Default: 3
+#
+#feedFailureThreshold = 3
+
+# IRC quiet threshold
+# Hours without announces before the channel quiet notification is sent. Set to 0 to disable.
+#
+# Default: 12
+#
+#ircQuietThreshold = 12
+
+# Digest hour
+# Hour of the day, in local time, to send the daily digest of grabbed releases. Set to -1 to disable.
+#
+# Default: 9
+#
+#digestHour = 9
+
+# OpenID Connect
+# Log in through an external identity provider. Identities are matched to users by the sub claim,
+# existing users link their identity under Settings > Account.
+# The redirect url must point to <baseUrl>api/auth/oidc/callback
+#
+# Default: false
+#
+#oidcEnabled = false
+#oidcIssuer = "https://auth.example.com"
+#oidcClientId = ""
+#oidcClientSecret = ""
+#oidcRedirectUrl = "https://autobrr.example.com/api/auth/oidc/callback"
+
+# OpenID Connect allowed groups
+# Only members of these groups from the groups claim can log in. Leave empty to allow everyone.
+# The groups scope is only requested when this is set, the provider must support it.
+#
+#oidcAllowedGroups = ["autobrr"]
+
+# OpenID Connect auto create users
+# Create a user with the viewer role for unlinked identities on their first login.
+# The login is refused if a local user already has the same username.
+#
+# Default: false
+#
+#oidcAutoCreateUsers = false
+
+# Login max attempts
+# Failed logins allowed per user and per ip before logins are locked out. Set to 0 to disable.
+#
+# Default: 5
+#
+#loginMaxAttempts = 5
+
+# Login lockout minutes
+# How long logins are locked out after too many failed attempts.
+#
+# Default: 15
+#
+#loginLockoutMinutes = 15
+
+# Trusted proxies
+# Addresses or networks of reverse proxies allowed to set the client address with X-Real-Ip or X-Forwarded-For.
+# The headers are ignored from everyone else, and the address of the connection is used for logins and sessions.
+#
+# Default: loopback and private networks
+#
+#trustedProxies = ["127.0.0.1", "172.16.0.0/12"]
+
+# Database backup interval
+# Hours between automatic backups of the sqlite database. Set to 0 to disable.
+# A backup is always taken before the database schema is upgraded.
+#
+# Default: 24
+#
+#databaseBackupInterval = 24
+
+# Database backup retention
+# Number of sqlite database backups to keep. Set to 0 to keep them all.
+#
+# Default: 7
+#
+#databaseBackupRetention = 7
+
+# Database backup directory
+# Where sqlite database backups are written. Defaults to the backups folder next to the config.
+#
+#databaseBackupDir = ""
+
+# Metrics
+# Serve Prometheus metrics on /metrics. Scrape it with an api key with the metrics:read scope,
+# passed as the X-API-Token header or the apikey query parameter.
+#
+# Default: false
+#
+#metricsEnabled = false
+
+# Release retention
+# Rules to delete old releases from the history, checked every 6 hours.
+# A rule deletes releases older than days, limited to the statuses and indexers when set.
+# Statuses are PUSH_APPROVED, PUSH_ERROR, PUSH_REJECTED, PENDING and FILTER_REJECTED.
+# A release has the best status of its actions, approved over error over rejected over pending.
+# Keep these at the end of the file, settings below a [[releaseRetention]] block belong to the rule.
+#
+#[[releaseRetention]]
+#days = 7
+#statuses = ["PUSH_REJECTED", "FILTER_REJECTED"]
+#
+#[[releaseRetention]]
+#days = 365
+#statuses = ["PUSH_APPROVED"]
+#indexers = ["mock"]
 `
 
 func writeConfig(configPath string, configFile string) error {
@@ -185,23 +336,37 @@ func New(configPath string, version string) *AppConfig {
 
 func (c *AppConfig) defaults() {
 	c.Config = &domain.Config{
-		Version:           "dev",
-		Host:              "localhost",
-		Port:              7474,
-		LogLevel:          "TRACE",
-		LogPath:           "",
-		LogMaxSize:        50,
-		LogMaxBackups:     3,
-		BaseURL:           "/",
-		SessionSecret:     "secret-session-key",
-		CustomDefinitions: "",
-		CheckForUpdates:   true,
-		DatabaseType:      "sqlite",
-		PostgresHost:      "",
-		PostgresPort:      0,
-		PostgresDatabase:  "",
-		PostgresUser:      "",
-		PostgresPass:      "",
+		Version:                   "dev",
+		Host:                      "localhost",
+		Port:                      7474,
+		LogLevel:                  "TRACE",
+		LogPath:                   "",
+		LogMaxSize:                50,
+		LogMaxBackups:             3,
+		BaseURL:                   "/",
+		SessionSecret:             "secret-session-key",
+		CustomDefinitions:         "",
+		CheckForUpdates:           true,
+		DatabaseType:              "sqlite",
+		SaveRejectedReleases:      false,
+		RejectedReleasesRetention: 7,
+		DupeProtection:            false,
+		DupeWindow:                24,
+		DupeMatchEpisode:          false,
+		FeedFailureThreshold:      3,
+		IRCQuietThreshold:         12,
+		DigestHour:                9,
+		LoginMaxAttempts:          5,
+		LoginLockoutMinutes:       15,
+		DatabaseBackupInterval:    24,
+		DatabaseBackupRetention:   7,
+		DatabaseBackupDir:         "",
+		MetricsEnabled:            false,
+		PostgresHost:              "",
+		PostgresPort:              0,
+		PostgresDatabase:          "",
+		PostgresUser:              "",
+		PostgresPass:              "",
 	}
 }
 
@@ -89,6 +89,9 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"retry_attempts",
+			"retry_interval",
+			"cross_seed",
 			"client_id",
 		).
 		From("action").
@@ -110,7 +113,7 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, crossSeed sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
@@ -118,7 +121,7 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 		// filterID
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &a.RetryAttempts, &a.RetryInterval, &crossSeed, &clientID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -142,6 +145,7 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
 
 		a.ClientID = clientID.Int32
 
@@ -153,6 +157,118 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 
 	return actions, nil
 }
+
+func (r *ActionRepo) FindByID(ctx context.Context, actionID int) (*domain.Action, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	queryBuilder := r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"type",
+			"enabled",
+			"exec_cmd",
+			"exec_args",
+			"watch_folder",
+			"category",
+			"tags",
+			"label",
+			"save_path",
+			"paused",
+			"ignore_rules",
+			"skip_hash_check",
+			"content_layout",
+			"limit_download_speed",
+			"limit_upload_speed",
+			"limit_ratio",
+			"limit_seed_time",
+			"reannounce_skip",
+			"reannounce_delete",
+			"reannounce_interval",
+			"reannounce_max_attempts",
+			"webhook_host",
+			"webhook_type",
+			"webhook_method",
+			"webhook_data",
+			"retry_attempts",
+			"retry_interval",
+			"cross_seed",
+			"client_id",
+			"filter_id",
+		).
+		From("action").
+		Where(sq.Eq{"id": actionID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := tx.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var a domain.Action
+
+	var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData, crossSeed sql.NullString
+	var limitUl, limitDl, limitSeedTime sql.NullInt64
+	var limitRatio sql.NullFloat64
+	var clientID, filterID sql.NullInt32
+	var paused, ignoreRules sql.NullBool
+
+	if err := row.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &a.RetryAttempts, &a.RetryInterval, &crossSeed, &clientID, &filterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(domain.ErrRecordNotFound, "action not found: %d", actionID)
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	a.ExecCmd = execCmd.String
+	a.ExecArgs = execArgs.String
+	a.WatchFolder = watchFolder.String
+	a.Category = category.String
+	a.Tags = tags.String
+	a.Label = label.String
+	a.SavePath = savePath.String
+	a.Paused = paused.Bool
+	a.IgnoreRules = ignoreRules.Bool
+	a.ContentLayout = domain.ActionContentLayout(contentLayout.String)
+
+	a.LimitDownloadSpeed = limitDl.Int64
+	a.LimitUploadSpeed = limitUl.Int64
+	a.LimitRatio = limitRatio.Float64
+	a.LimitSeedTime = limitSeedTime.Int64
+
+	a.WebhookHost = webhookHost.String
+	a.WebhookType = webhookType.String
+	a.WebhookMethod = webhookMethod.String
+	a.WebhookData = webhookData.String
+	a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
+
+	a.ClientID = clientID.Int32
+	a.FilterID = int(filterID.Int32)
+
+	if a.ClientID > 0 {
+		client, err := r.attachDownloadClient(ctx, tx, a.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		if client != nil {
+			a.Client = client
+		}
+	}
+
+	return &a, nil
+}
+
 func (r *ActionRepo) attachDownloadClient(ctx context.Context, tx *Tx, clientID int32) (*domain.DownloadClient, error) {
 
 	queryBuilder := r.db.squirrel.
@@ -230,6 +346,9 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"retry_attempts",
+			"retry_interval",
+			"cross_seed",
 			"client_id",
 		).
 		From("action")
@@ -250,13 +369,13 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, webhookHost, webhookType, webhookMethod, webhookData, crossSeed sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 		var clientID sql.NullInt32
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &a.RetryAttempts, &a.RetryInterval, &crossSeed, &clientID); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -276,6 +395,7 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.WebhookType = webhookType.String
 		a.WebhookMethod = webhookMethod.String
 		a.WebhookData = webhookData.String
+		a.CrossSeed = domain.ActionCrossSeed(crossSeed.String)
 
 		a.ClientID = clientID.Int32
 
@@ -341,6 +461,7 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 	webhookData := toNullString(action.WebhookData)
 	webhookType := toNullString(action.WebhookType)
 	webhookMethod := toNullString(action.WebhookMethod)
+	crossSeed := toNullString(string(action.CrossSeed))
 
 	limitDL := toNullInt64(action.LimitDownloadSpeed)
 	limitUL := toNullInt64(action.LimitUploadSpeed)
@@ -378,6 +499,9 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"webhook_type",
 			"webhook_method",
 			"webhook_data",
+			"retry_attempts",
+			"retry_interval",
+			"cross_seed",
 			"client_id",
 			"filter_id",
 		).
@@ -408,6 +532,9 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			webhookType,
 			webhookMethod,
 			webhookData,
+			action.RetryAttempts,
+			action.RetryInterval,
+			crossSeed,
 			clientID,
 			filterID,
 		).
@@ -439,6 +566,7 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 	webhookHost := toNullString(action.WebhookHost)
 	webhookType := toNullString(action.WebhookType)
 	webhookMethod := toNullString(action.WebhookMethod)
+	crossSeed := toNullString(string(action.CrossSeed))
 	webhookData := toNullString(action.WebhookData)
 
 	limitDL := toNullInt64(action.LimitDownloadSpeed)
@@ -479,6 +607,9 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("webhook_type", webhookType).
 		Set("webhook_method", webhookMethod).
 		Set("webhook_data", webhookData).
+		Set("retry_attempts", action.RetryAttempts).
+		Set("retry_interval", action.RetryInterval).
+		Set("cross_seed", crossSeed).
 		Set("client_id", clientID).
 		Set("filter_id", filterID).
 		Where(sq.Eq{"id": action.ID})
@@ -531,6 +662,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 		webhookHost := toNullString(action.WebhookHost)
 		webhookType := toNullString(action.WebhookType)
 		webhookMethod := toNullString(action.WebhookMethod)
+		crossSeed := toNullString(string(action.CrossSeed))
 		webhookData := toNullString(action.WebhookData)
 
 		limitDL := toNullInt64(action.LimitDownloadSpeed)
@@ -568,6 +700,9 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				"webhook_type",
 				"webhook_method",
 				"webhook_data",
+				"retry_attempts",
+				"retry_interval",
+				"cross_seed",
 				"client_id",
 				"filter_id",
 			).
@@ -598,6 +733,9 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				webhookType,
 				webhookMethod,
 				webhookData,
+				action.RetryAttempts,
+				action.RetryInterval,
+				crossSeed,
 				clientID,
 				filterID,
 			).
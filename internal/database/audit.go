@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type AuditRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewAuditRepo(log logger.Logger, db *DB) domain.AuditRepo {
+	return &AuditRepo{
+		log: log.With().Str("repo", "audit").Logger(),
+		db:  db,
+	}
+}
+
+func (r *AuditRepo) Store(ctx context.Context, event *domain.AuditEvent) error {
+	queryBuilder := r.db.squirrel.
+		Insert("audit_log").
+		Columns("user_id", "username", "action", "resource", "path").
+		Values(toNullInt32(int32(event.UserID)), event.Username, event.Action, event.Resource, event.Path).
+		Suffix("RETURNING id, timestamp").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&event.ID, &event.Timestamp); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) List(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEvent, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "user_id", "username", "action", "resource", "path", "timestamp").
+		From("audit_log").
+		OrderBy("id DESC")
+
+	if params.Resource != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"resource": params.Resource})
+	}
+
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	events := make([]domain.AuditEvent, 0)
+	for rows.Next() {
+		var e domain.AuditEvent
+
+		var userID sql.NullInt32
+
+		if err := rows.Scan(&e.ID, &userID, &e.Username, &e.Action, &e.Resource, &e.Path, &e.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		e.UserID = int(userID.Int32)
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return events, nil
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// TableCopy is the number of rows copied for a table
+type TableCopy struct {
+	Table string
+	Rows  int
+}
+
+type tableColumn struct {
+	Name string
+	Type string
+}
+
+// Copy copies all rows from src to dst. Both databases must be on the same schema version,
+// which is the case when both are opened by the same build, and dst must be empty.
+// Tables are copied in foreign key order in a single transaction, afterwards postgres
+// sequences are moved past the copied ids and the row counts of both sides are compared.
+func Copy(ctx context.Context, src *DB, dst *DB) ([]TableCopy, error) {
+	srcTables, err := src.tables(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list %s tables", src.Driver)
+	}
+
+	dstTables, err := dst.tables(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list %s tables", dst.Driver)
+	}
+
+	tables := make([]string, 0, len(dstTables))
+	for _, t := range dstTables {
+		if !containsString(srcTables, t) {
+			dst.log.Warn().Msgf("table %s does not exist in %s, skipping", t, src.Driver)
+			continue
+		}
+
+		tables = append(tables, t)
+	}
+
+	deps := make(map[string][]string, len(tables))
+	for _, t := range tables {
+		if deps[t], err = dst.references(ctx, t); err != nil {
+			return nil, errors.Wrap(err, "could not find foreign keys of table: %s", t)
+		}
+	}
+
+	tables = sortTables(tables, deps)
+
+	for _, t := range tables {
+		count, err := dst.countRows(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+
+		if count > 0 {
+			return nil, errors.New("%s table %s is not empty, copy into a new database", dst.Driver, t)
+		}
+	}
+
+	tx, err := dst.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	result := make([]TableCopy, 0, len(tables))
+
+	for _, t := range tables {
+		dstColumns, err := dst.columns(ctx, t)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find columns of %s table: %s", dst.Driver, t)
+		}
+
+		srcColumns, err := src.columns(ctx, t)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find columns of %s table: %s", src.Driver, t)
+		}
+
+		columns := make([]tableColumn, 0, len(dstColumns))
+		for _, c := range dstColumns {
+			if hasColumn(srcColumns, c.Name) {
+				columns = append(columns, c)
+			}
+		}
+
+		rows, err := copyTable(ctx, src, tx, t, columns)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not copy table: %s", t)
+		}
+
+		if dst.Driver == "postgres" {
+			if err := resetSequence(ctx, tx, t, columns); err != nil {
+				return nil, errors.Wrap(err, "could not reset sequence of table: %s", t)
+			}
+		}
+
+		dst.log.Info().Msgf("copied %d rows into %s", rows, t)
+
+		result = append(result, TableCopy{Table: t, Rows: rows})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "could not commit transaction")
+	}
+
+	for _, r := range result {
+		srcCount, err := src.countRows(ctx, r.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		dstCount, err := dst.countRows(ctx, r.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		if srcCount != dstCount {
+			return result, errors.New("row count mismatch for table %s: %s has %d rows, %s has %d", r.Table, src.Driver, srcCount, dst.Driver, dstCount)
+		}
+	}
+
+	return result, nil
+}
+
+func copyTable(ctx context.Context, src *DB, tx *sql.Tx, table string, columns []tableColumn) (int, error) {
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = quoteIdentifier(c.Name)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	rows, err := src.handler.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(names, ", "), quoteIdentifier(table)))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not query rows")
+	}
+
+	defer rows.Close()
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(table), strings.Join(names, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not prepare insert")
+	}
+
+	defer stmt.Close()
+
+	count := 0
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, errors.Wrap(err, "could not scan row")
+		}
+
+		args := make([]interface{}, len(columns))
+		for i, c := range columns {
+			args[i] = convertValue(values[i], c.Type)
+		}
+
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return count, errors.Wrap(err, "could not insert row")
+		}
+
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		return count, errors.Wrap(err, "could not read rows")
+	}
+
+	return count, nil
+}
+
+// resetSequence moves the serial sequence of the id column past the copied ids
+func resetSequence(ctx context.Context, tx *sql.Tx, table string, columns []tableColumn) error {
+	for _, c := range columns {
+		if c.Name != "id" {
+			continue
+		}
+
+		switch strings.ToLower(c.Type) {
+		case "integer", "bigint", "smallint":
+		default:
+			return nil
+		}
+
+		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, 'id'), MAX(id)) FROM %s HAVING MAX(id) IS NOT NULL", quoteIdentifier(table))
+		_, err := tx.ExecContext(ctx, query, quoteIdentifier(table))
+		return err
+	}
+
+	return nil
+}
+
+// convertValue adjusts values that are stored differently between sqlite and postgres
+func convertValue(v interface{}, columnType string) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		// text, text arrays and timestamps are returned as bytes by some drivers
+		return string(val)
+
+	case int64:
+		// sqlite stores booleans as integers
+		if strings.EqualFold(columnType, "boolean") {
+			return val != 0
+		}
+	}
+
+	return v
+}
+
+// sortTables orders tables so referenced tables come before the tables referencing them
+func sortTables(tables []string, deps map[string][]string) []string {
+	sort.Strings(tables)
+
+	sorted := make([]string, 0, len(tables))
+	visited := make(map[string]int, len(tables))
+
+	var visit func(t string)
+	visit = func(t string) {
+		// 1 is in progress, which means a cycle, 2 is done
+		if visited[t] > 0 {
+			return
+		}
+		visited[t] = 1
+
+		for _, d := range deps[t] {
+			if d != t && containsString(tables, d) {
+				visit(d)
+			}
+		}
+
+		visited[t] = 2
+		sorted = append(sorted, t)
+	}
+
+	for _, t := range tables {
+		visit(t)
+	}
+
+	return sorted
+}
+
+func (db *DB) tables(ctx context.Context) ([]string, error) {
+	query := `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`
+	if db.Driver == "postgres" {
+		query = `SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'`
+	}
+
+	return db.queryStrings(ctx, query)
+}
+
+// references returns the tables the foreign keys of the table point to
+func (db *DB) references(ctx context.Context, table string) ([]string, error) {
+	query := `SELECT DISTINCT "table" FROM pragma_foreign_key_list($1)`
+	if db.Driver == "postgres" {
+		query = `SELECT DISTINCT ccu.table_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.constraint_column_usage ccu
+				ON tc.constraint_name = ccu.constraint_name AND tc.table_schema = ccu.table_schema
+			WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = current_schema() AND tc.table_name = $1`
+	}
+
+	return db.queryStrings(ctx, query, table)
+}
+
+func (db *DB) columns(ctx context.Context, table string) ([]tableColumn, error) {
+	query := `SELECT name, type FROM pragma_table_info($1) ORDER BY cid`
+	if db.Driver == "postgres" {
+		query = `SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position`
+	}
+
+	rows, err := db.handler.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	var columns []tableColumn
+	for rows.Next() {
+		var c tableColumn
+		if err := rows.Scan(&c.Name, &c.Type); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		columns = append(columns, c)
+	}
+
+	return columns, rows.Err()
+}
+
+func (db *DB) countRows(ctx context.Context, table string) (int, error) {
+	var count int
+	if err := db.handler.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdentifier(table))).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, "could not count rows of %s table: %s", db.Driver, table)
+	}
+
+	return count, nil
+}
+
+func (db *DB) queryStrings(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		values = append(values, v)
+	}
+
+	return values, rows.Err()
+}
+
+// quoteIdentifier quotes table and column names, needed for reserved words like user
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func hasColumn(columns []tableColumn, name string) bool {
+	for _, c := range columns {
+		if c.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
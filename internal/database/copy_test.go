@@ -0,0 +1,155 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sortTables(t *testing.T) {
+	tables := []string{"release_action_status", "action", "filter", "client", "release", "filter_indexer", "indexer", "user", "user_session"}
+	deps := map[string][]string{
+		"action":                {"filter", "client"},
+		"filter_indexer":        {"filter", "indexer"},
+		"release_action_status": {"release", "action", "filter"},
+		"user_session":          {"user"},
+		// self references and missing tables are ignored
+		"release": {"release", "missing"},
+	}
+
+	sorted := sortTables(tables, deps)
+	assert.Len(t, sorted, len(tables))
+
+	position := make(map[string]int, len(sorted))
+	for i, table := range sorted {
+		position[table] = i
+	}
+
+	for table, references := range deps {
+		for _, ref := range references {
+			if ref == table || ref == "missing" {
+				continue
+			}
+			assert.Less(t, position[ref], position[table], "%s must be copied before %s", ref, table)
+		}
+	}
+}
+
+func Test_sortTables_cycle(t *testing.T) {
+	sorted := sortTables([]string{"a", "b"}, map[string][]string{"a": {"b"}, "b": {"a"}})
+	assert.ElementsMatch(t, []string{"a", "b"}, sorted)
+}
+
+func Test_convertValue(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		columnType string
+		want       interface{}
+	}{
+		{name: "bool_from_int_true", value: int64(1), columnType: "boolean", want: true},
+		{name: "bool_from_int_false", value: int64(0), columnType: "BOOLEAN", want: false},
+		{name: "int", value: int64(5), columnType: "integer", want: int64(5)},
+		{name: "bytes", value: []byte("{a,b}"), columnType: "ARRAY", want: "{a,b}"},
+		{name: "time", value: now, columnType: "timestamp with time zone", want: now},
+		{name: "nil", value: nil, columnType: "text", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, convertValue(tt.value, tt.columnType))
+		})
+	}
+}
+
+func Test_quoteIdentifier(t *testing.T) {
+	assert.Equal(t, `"user"`, quoteIdentifier("user"))
+	assert.Equal(t, `"we""ird"`, quoteIdentifier(`we"ird`))
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+
+	src := setupTestSQLite(t)
+	dst := setupTestSQLite(t)
+
+	filterRepo := NewFilterRepo(logger.Mock(), src)
+	releaseRepo := NewReleaseRepo(logger.Mock(), src)
+
+	f, err := filterRepo.Store(ctx, domain.Filter{Name: "tv", Enabled: true, Freeleech: true, Resolutions: []string{"1080p"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	var releaseID int64
+	for _, name := range []string{"That.Show.S01E01.1080p.WEB.H264-GROUP", "That.Show.S01E02.PROPER.1080p.WEB.H264-GROUP"} {
+		r, err := releaseRepo.Store(ctx, &domain.Release{
+			FilterStatus: domain.ReleaseStatusFilterApproved,
+			Indexer:      "mock",
+			TorrentName:  name,
+			Timestamp:    timestamp,
+			Proper:       true,
+			Freeleech:    true,
+			Tags:         []string{"tv", "hd"},
+			FilterID:     f.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		releaseID = r.ID
+	}
+
+	result, err := Copy(ctx, src, dst)
+	assert.NoError(t, err)
+
+	rows := make(map[string]int, len(result))
+	for _, r := range result {
+		rows[r.Table] = r.Rows
+	}
+
+	assert.Equal(t, 1, rows["filter"])
+	assert.Equal(t, 2, rows["release"])
+	assert.Equal(t, 0, rows["release_action_status"])
+
+	// filters are copied before the releases referencing them
+	position := make(map[string]int, len(result))
+	for i, r := range result {
+		position[r.Table] = i
+	}
+	assert.Less(t, position["filter"], position["release"])
+
+	copiedFilter, err := NewFilterRepo(logger.Mock(), dst).FindByID(ctx, f.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "tv", copiedFilter.Name)
+		assert.True(t, copiedFilter.Enabled)
+		assert.True(t, copiedFilter.Freeleech)
+		assert.False(t, copiedFilter.SmartEpisode)
+		assert.Equal(t, []string{"1080p"}, copiedFilter.Resolutions)
+	}
+
+	copiedRelease, err := NewReleaseRepo(logger.Mock(), dst).FindByID(ctx, releaseID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "That.Show.S01E02.PROPER.1080p.WEB.H264-GROUP", copiedRelease.TorrentName)
+		assert.True(t, timestamp.Equal(copiedRelease.Timestamp), "timestamp %s", copiedRelease.Timestamp)
+		assert.True(t, copiedRelease.Proper)
+		assert.False(t, copiedRelease.Repack)
+		assert.True(t, copiedRelease.Freeleech)
+		assert.Equal(t, []string{"tv", "hd"}, copiedRelease.Tags)
+		assert.Equal(t, f.ID, copiedRelease.FilterID)
+	}
+
+	// copying again would mix two databases
+	_, err = Copy(ctx, src, dst)
+	assert.ErrorContains(t, err, "is not empty")
+}
@@ -29,6 +29,10 @@ type DB struct {
 	Driver string
 	DSN    string
 
+	// sqlite backups
+	backupDir       string
+	backupRetention int
+
 	squirrel sq.StatementBuilderType
 }
 
@@ -45,6 +49,11 @@ func NewDB(cfg *domain.Config, log logger.Logger) (*DB, error) {
 		databaseDriver = "sqlite"
 		db.Driver = "sqlite"
 		db.DSN = dataSourceName(cfg.ConfigPath, "autobrr.db")
+		db.backupDir = cfg.DatabaseBackupDir
+		if db.backupDir == "" {
+			db.backupDir = dataSourceName(cfg.ConfigPath, "backups")
+		}
+		db.backupRetention = cfg.DatabaseBackupRetention
 	case "postgres":
 		if cfg.PostgresHost == "" || cfg.PostgresPort == 0 || cfg.PostgresDatabase == "" {
 			return nil, errors.New("postgres: bad variables")
@@ -0,0 +1,31 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+)
+
+// setupTestSQLite opens a new sqlite database with the current schema in a temp dir
+func setupTestSQLite(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := NewDB(&domain.Config{DatabaseType: "sqlite", ConfigPath: t.TempDir()}, logger.Mock())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
@@ -6,6 +6,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -31,7 +32,7 @@ func NewNotificationRepo(log logger.Logger, db *DB) domain.NotificationRepo {
 func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQueryParams) ([]domain.Notification, int, error) {
 
 	queryBuilder := r.db.squirrel.
-		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "priority", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
+		Select("id", "name", "type", "enabled", "events", "filters", "indexers", "webhook", "token", "api_key", "title", "icon", "host", "username", "password", "channel", "rooms", "targets", "devices", "priority", "templates", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
 		From("notification").
 		OrderBy("name")
 
@@ -52,16 +53,31 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 	for rows.Next() {
 		var n domain.Notification
 
-		var webhook, token, apiKey, channel sql.NullString
+		var webhook, token, apiKey, title, icon, host, username, password, channel, rooms, targets, devices, templates sql.NullString
 
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.Priority, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
+		var filters pq.Int64Array
+
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &filters, pq.Array(&n.Indexers), &webhook, &token, &apiKey, &title, &icon, &host, &username, &password, &channel, &rooms, &targets, &devices, &n.Priority, &templates, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
 			return nil, 0, errors.Wrap(err, "error scanning row")
 		}
 
+		n.Filters = toIntSlice(filters)
 		n.APIKey = apiKey.String
 		n.Webhook = webhook.String
 		n.Token = token.String
+		n.Title = title.String
+		n.Icon = icon.String
+		n.Host = host.String
+		n.Username = username.String
+		n.Password = password.String
 		n.Channel = channel.String
+		n.Rooms = rooms.String
+		n.Targets = targets.String
+		n.Devices = devices.String
+
+		if err := scanNotificationTemplates(templates, &n); err != nil {
+			return nil, 0, err
+		}
 
 		notifications = append(notifications, n)
 	}
@@ -74,7 +90,7 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 
 func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, error) {
 
-	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key,  webhook, title, icon, host, username, password, channel, targets, devices, priority,created_at, updated_at FROM notification ORDER BY name ASC")
+	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, filters, indexers, token, api_key, webhook, title, icon, host, username, password, channel, rooms, targets, devices, priority, templates, created_at, updated_at FROM notification ORDER BY name ASC")
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -86,12 +102,14 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		var n domain.Notification
 		//var eventsSlice []string
 
-		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		var token, apiKey, webhook, title, icon, host, username, password, channel, rooms, targets, devices, templates sql.NullString
+		var filters pq.Int64Array
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &filters, pq.Array(&n.Indexers), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &rooms, &targets, &devices, &n.Priority, &templates, &n.CreatedAt, &n.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		//n.Events = ([]domain.NotificationEvent)(eventsSlice)
+		n.Filters = toIntSlice(filters)
 		n.Token = token.String
 		n.APIKey = apiKey.String
 		n.Webhook = webhook.String
@@ -101,9 +119,14 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		n.Username = username.String
 		n.Password = password.String
 		n.Channel = channel.String
+		n.Rooms = rooms.String
 		n.Targets = targets.String
 		n.Devices = devices.String
 
+		if err := scanNotificationTemplates(templates, &n); err != nil {
+			return nil, err
+		}
+
 		notifications = append(notifications, n)
 	}
 	if err := rows.Err(); err != nil {
@@ -122,6 +145,8 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 			"type",
 			"enabled",
 			"events",
+			"filters",
+			"indexers",
 			"token",
 			"api_key",
 			"webhook",
@@ -131,9 +156,11 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 			"username",
 			"password",
 			"channel",
+			"rooms",
 			"targets",
 			"devices",
 			"priority",
+			"templates",
 			"created_at",
 			"updated_at",
 		).
@@ -152,11 +179,13 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 
 	var n domain.Notification
 
-	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
-	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.Priority, &n.CreatedAt, &n.UpdatedAt); err != nil {
+	var token, apiKey, webhook, title, icon, host, username, password, channel, rooms, targets, devices, templates sql.NullString
+	var filters pq.Int64Array
+	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &filters, pq.Array(&n.Indexers), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &rooms, &targets, &devices, &n.Priority, &templates, &n.CreatedAt, &n.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	n.Filters = toIntSlice(filters)
 	n.Token = token.String
 	n.APIKey = apiKey.String
 	n.Webhook = webhook.String
@@ -166,9 +195,14 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 	n.Username = username.String
 	n.Password = password.String
 	n.Channel = channel.String
+	n.Rooms = rooms.String
 	n.Targets = targets.String
 	n.Devices = devices.String
 
+	if err := scanNotificationTemplates(templates, &n); err != nil {
+		return nil, err
+	}
+
 	return &n, nil
 }
 
@@ -176,7 +210,20 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 	webhook := toNullString(notification.Webhook)
 	token := toNullString(notification.Token)
 	apiKey := toNullString(notification.APIKey)
+	title := toNullString(notification.Title)
+	icon := toNullString(notification.Icon)
+	host := toNullString(notification.Host)
+	username := toNullString(notification.Username)
+	password := toNullString(notification.Password)
 	channel := toNullString(notification.Channel)
+	rooms := toNullString(notification.Rooms)
+	targets := toNullString(notification.Targets)
+	devices := toNullString(notification.Devices)
+
+	templates, err := marshalNotificationTemplates(notification.Templates)
+	if err != nil {
+		return nil, err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Insert("notification").
@@ -185,22 +232,44 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			"type",
 			"enabled",
 			"events",
+			"filters",
+			"indexers",
 			"webhook",
 			"token",
 			"api_key",
+			"title",
+			"icon",
+			"host",
+			"username",
+			"password",
 			"channel",
+			"rooms",
+			"targets",
+			"devices",
 			"priority",
+			"templates",
 		).
 		Values(
 			notification.Name,
 			notification.Type,
 			notification.Enabled,
 			pq.Array(notification.Events),
+			toInt64Array(notification.Filters),
+			pq.Array(notification.Indexers),
 			webhook,
 			token,
 			apiKey,
+			title,
+			icon,
+			host,
+			username,
+			password,
 			channel,
+			rooms,
+			targets,
+			devices,
 			notification.Priority,
+			templates,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -221,7 +290,20 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 	webhook := toNullString(notification.Webhook)
 	token := toNullString(notification.Token)
 	apiKey := toNullString(notification.APIKey)
+	title := toNullString(notification.Title)
+	icon := toNullString(notification.Icon)
+	host := toNullString(notification.Host)
+	username := toNullString(notification.Username)
+	password := toNullString(notification.Password)
 	channel := toNullString(notification.Channel)
+	rooms := toNullString(notification.Rooms)
+	targets := toNullString(notification.Targets)
+	devices := toNullString(notification.Devices)
+
+	templates, err := marshalNotificationTemplates(notification.Templates)
+	if err != nil {
+		return nil, err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Update("notification").
@@ -229,11 +311,22 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 		Set("type", notification.Type).
 		Set("enabled", notification.Enabled).
 		Set("events", pq.Array(notification.Events)).
+		Set("filters", toInt64Array(notification.Filters)).
+		Set("indexers", pq.Array(notification.Indexers)).
 		Set("webhook", webhook).
 		Set("token", token).
 		Set("api_key", apiKey).
+		Set("title", title).
+		Set("icon", icon).
+		Set("host", host).
+		Set("username", username).
+		Set("password", password).
 		Set("channel", channel).
+		Set("rooms", rooms).
+		Set("targets", targets).
+		Set("devices", devices).
 		Set("priority", notification.Priority).
+		Set("templates", templates).
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{"id": notification.ID})
 
@@ -269,3 +362,29 @@ func (r *NotificationRepo) Delete(ctx context.Context, notificationID int) error
 
 	return nil
 }
+
+// marshalNotificationTemplates returns the templates as json, or null if there are none
+func marshalNotificationTemplates(templates domain.NotificationTemplates) (sql.NullString, error) {
+	if len(templates) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(templates)
+	if err != nil {
+		return sql.NullString{}, errors.Wrap(err, "error marshaling notification templates")
+	}
+
+	return toNullString(string(data)), nil
+}
+
+func scanNotificationTemplates(templates sql.NullString, n *domain.Notification) error {
+	if !templates.Valid || templates.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(templates.String), &n.Templates); err != nil {
+		return errors.Wrap(err, "error unmarshal notification templates")
+	}
+
+	return nil
+}
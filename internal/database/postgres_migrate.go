@@ -6,14 +6,23 @@ package database
 const postgresSchema = `
 CREATE TABLE users
 (
-    id         SERIAL PRIMARY KEY,
-    username   TEXT NOT NULL,
-    password   TEXT NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                 SERIAL PRIMARY KEY,
+    username           TEXT NOT NULL,
+    password           TEXT NOT NULL,
+    role               TEXT DEFAULT 'admin' NOT NULL,
+    two_factor_enabled BOOLEAN DEFAULT FALSE,
+    two_factor_secret  TEXT DEFAULT '' NOT NULL,
+    recovery_codes     TEXT [] DEFAULT '{}' NOT NULL,
+    oidc_subject       TEXT DEFAULT '' NOT NULL,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (username)
 );
 
+CREATE UNIQUE INDEX users_oidc_subject_index
+    ON users (oidc_subject)
+    WHERE oidc_subject != '';
+
 CREATE TABLE indexer
 (
     id             SERIAL PRIMARY KEY,
@@ -87,9 +96,12 @@ CREATE TABLE filter
     freeleech                      BOOLEAN,
     freeleech_percent              TEXT,
     smart_episode                  BOOLEAN DEFAULT FALSE,
+    smart_episode_upgrade          BOOLEAN DEFAULT FALSE,
+    allow_dupe_other_indexers      BOOLEAN DEFAULT FALSE,
     shows                          TEXT,
     seasons                        TEXT,
     episodes                       TEXT,
+    episode_type                   TEXT,
     resolutions                    TEXT []   DEFAULT '{}' NOT NULL,
     codecs                         TEXT []   DEFAULT '{}' NOT NULL,
     sources                        TEXT []   DEFAULT '{}' NOT NULL,
@@ -188,6 +200,9 @@ CREATE TABLE action
     webhook_type            TEXT,
     webhook_data            TEXT,
     webhook_headers         TEXT[] DEFAULT '{}',
+    retry_attempts          INTEGER DEFAULT 0,
+    retry_interval          INTEGER DEFAULT 60,
+    cross_seed              TEXT,
     client_id               INTEGER,
     filter_id               INTEGER,
     FOREIGN KEY (filter_id) REFERENCES filter (id),
@@ -206,6 +221,7 @@ CREATE TABLE "release"
     timestamp         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     info_url          TEXT,
     download_url      TEXT,
+    magnet_uri        TEXT,
     group_id          TEXT,
     torrent_id        TEXT,
     torrent_name      TEXT,
@@ -215,7 +231,10 @@ CREATE TABLE "release"
     category          TEXT,
     season            INTEGER,
     episode           INTEGER,
+    episode_end       INTEGER,
     year              INTEGER,
+    month             INTEGER,
+    day               INTEGER,
     resolution        TEXT,
     source            TEXT,
     codec             TEXT,
@@ -241,8 +260,9 @@ CREATE TABLE "release"
     is_scene          BOOLEAN,
     origin            TEXT,
     tags              TEXT []   DEFAULT '{}' NOT NULL,
-    freeleech         BOOLEAN,
-    freeleech_percent INTEGER,
+    freeleech         BOOLEAN   DEFAULT FALSE,
+    freeleech_percent INTEGER   DEFAULT 0,
+    bonus             TEXT []   DEFAULT '{}' NOT NULL,
     uploader          TEXT,
 	pre_time          TEXT,
     filter_id         INTEGER
@@ -284,6 +304,39 @@ CREATE TABLE release_action_status
 CREATE INDEX release_action_status_release_id_index
     ON release_action_status (release_id);
 
+CREATE TABLE release_delay
+(
+    id         SERIAL PRIMARY KEY,
+    release_id INTEGER NOT NULL,
+    filter_id  INTEGER NOT NULL,
+    run_at     TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES "filter"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_delay_run_at_index
+    ON release_delay (run_at);
+
+CREATE TABLE release_action_retry
+(
+    id               SERIAL PRIMARY KEY,
+    release_id       INTEGER NOT NULL,
+    action_status_id INTEGER NOT NULL,
+    filter_id        INTEGER NOT NULL,
+    action_id        INTEGER NOT NULL,
+    attempts         INTEGER DEFAULT 0,
+    next_run_at      TIMESTAMP NOT NULL,
+    last_error       TEXT,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (action_status_id) REFERENCES release_action_status(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES "filter"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_action_retry_next_run_at_index
+    ON release_action_retry (next_run_at);
+
 CREATE TABLE notification
 (
 	id         SERIAL PRIMARY KEY,
@@ -291,6 +344,8 @@ CREATE TABLE notification
 	type       TEXT,
 	enabled    BOOLEAN,
 	events     TEXT []   DEFAULT '{}' NOT NULL,
+	filters    INTEGER [] DEFAULT '{}' NOT NULL,
+	indexers   TEXT []   DEFAULT '{}' NOT NULL,
 	token      TEXT,
 	api_key    TEXT,
 	webhook    TEXT,
@@ -304,6 +359,7 @@ CREATE TABLE notification
 	targets    TEXT,
 	devices    TEXT,
 	priority   INTEGER DEFAULT 0,
+	templates  TEXT,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -347,6 +403,34 @@ CREATE TABLE api_key
 	scopes     TEXT []   DEFAULT '{}' NOT NULL,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE audit_log
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER,
+    username   TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    resource   TEXT NOT NULL,
+    path       TEXT NOT NULL,
+    timestamp  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_timestamp_index
+    ON audit_log (timestamp);
+
+CREATE TABLE user_session
+(
+    id         TEXT PRIMARY KEY,
+    user_id    INTEGER NOT NULL,
+    ip         TEXT DEFAULT '' NOT NULL,
+    user_agent TEXT DEFAULT '' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_session_user_id_index
+    ON user_session (user_id);
 `
 
 var postgresMigrations = []string{
@@ -677,4 +761,132 @@ ADD COLUMN download_url TEXT;
 	`,
 	`ALTER TABLE notification
 ADD COLUMN priority INTEGER DEFAULT 0;`,
+	`ALTER TABLE "release"
+ADD COLUMN magnet_uri TEXT;
+
+CREATE TABLE release_delay
+(
+    id         SERIAL PRIMARY KEY,
+    release_id INTEGER NOT NULL,
+    filter_id  INTEGER NOT NULL,
+    run_at     TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES "filter"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_delay_run_at_index
+    ON release_delay (run_at);
+`,
+	`ALTER TABLE "action"
+ADD COLUMN retry_attempts INTEGER DEFAULT 0;
+
+ALTER TABLE "action"
+ADD COLUMN retry_interval INTEGER DEFAULT 60;
+
+CREATE TABLE release_action_retry
+(
+    id               SERIAL PRIMARY KEY,
+    release_id       INTEGER NOT NULL,
+    action_status_id INTEGER NOT NULL,
+    filter_id        INTEGER NOT NULL,
+    action_id        INTEGER NOT NULL,
+    attempts         INTEGER DEFAULT 0,
+    next_run_at      TIMESTAMP NOT NULL,
+    last_error       TEXT,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (action_status_id) REFERENCES release_action_status(id) ON DELETE CASCADE,
+    FOREIGN KEY (filter_id) REFERENCES "filter"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_action_retry_next_run_at_index
+    ON release_action_retry (next_run_at);
+`,
+	`ALTER TABLE "action"
+ADD COLUMN cross_seed TEXT;
+`,
+	`ALTER TABLE "filter"
+	ADD COLUMN allow_dupe_other_indexers BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE "filter"
+	ADD COLUMN smart_episode_upgrade BOOLEAN DEFAULT false;
+`,
+	`ALTER TABLE "release"
+	ADD COLUMN episode_end INTEGER;
+
+ALTER TABLE "release"
+	ADD COLUMN month INTEGER;
+
+ALTER TABLE "release"
+	ADD COLUMN day INTEGER;
+`,
+	`ALTER TABLE "filter"
+	ADD COLUMN episode_type TEXT;
+`,
+	`ALTER TABLE "notification"
+	ADD COLUMN templates TEXT;
+`,
+	`ALTER TABLE "notification"
+	ADD COLUMN filters INTEGER [] DEFAULT '{}' NOT NULL;
+
+ALTER TABLE "notification"
+	ADD COLUMN indexers TEXT [] DEFAULT '{}' NOT NULL;
+`,
+	`ALTER TABLE users
+	ADD COLUMN role TEXT DEFAULT 'admin' NOT NULL;
+
+CREATE TABLE audit_log
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER,
+    username   TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    resource   TEXT NOT NULL,
+    path       TEXT NOT NULL,
+    timestamp  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_timestamp_index
+    ON audit_log (timestamp);
+`,
+	`ALTER TABLE users
+	ADD COLUMN two_factor_enabled BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE users
+	ADD COLUMN two_factor_secret TEXT DEFAULT '' NOT NULL;
+
+ALTER TABLE users
+	ADD COLUMN recovery_codes TEXT [] DEFAULT '{}' NOT NULL;
+`,
+	`CREATE TABLE user_session
+(
+    id         TEXT PRIMARY KEY,
+    user_id    INTEGER NOT NULL,
+    ip         TEXT DEFAULT '' NOT NULL,
+    user_agent TEXT DEFAULT '' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_session_user_id_index
+    ON user_session (user_id);
+`,
+	`ALTER TABLE users
+	ADD COLUMN oidc_subject TEXT DEFAULT '' NOT NULL;
+
+CREATE UNIQUE INDEX users_oidc_subject_index
+    ON users (oidc_subject)
+    WHERE oidc_subject != '';
+`,
+	`ALTER TABLE release
+	ADD COLUMN IF NOT EXISTS freeleech BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE release
+	ADD COLUMN IF NOT EXISTS freeleech_percent INTEGER DEFAULT 0;
+
+ALTER TABLE release
+	ADD COLUMN IF NOT EXISTS bonus TEXT [] DEFAULT '{}' NOT NULL;
+`,
 }
@@ -38,8 +38,8 @@ func (repo *ReleaseRepo) Store(ctx context.Context, r *domain.Release) (*domain.
 
 	queryBuilder := repo.db.squirrel.
 		Insert("release").
-		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "info_url", "download_url", "torrent_name", "size", "title", "category", "season", "episode", "year", "resolution", "source", "codec", "container", "hdr", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "filter_id").
-		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer, r.FilterName, r.Protocol, r.Implementation, r.Timestamp.Format(time.RFC3339), r.GroupID, r.TorrentID, r.InfoURL, r.TorrentURL, r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, r.Year, r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, r.FilterID).
+		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "info_url", "download_url", "magnet_uri", "torrent_name", "size", "title", "category", "season", "episode", "episode_end", "year", "month", "day", "resolution", "source", "codec", "container", "hdr", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "filter_id", "freeleech", "freeleech_percent", "bonus").
+		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer, r.FilterName, r.Protocol, r.Implementation, r.Timestamp.UTC().Format(time.RFC3339), r.GroupID, r.TorrentID, r.InfoURL, r.TorrentURL, toNullString(r.MagnetURI), r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, toNullInt32(int32(r.EpisodeEnd)), r.Year, toNullInt32(int32(r.Month)), toNullInt32(int32(r.Day)), r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, toNullInt32(int32(r.FilterID)), r.Freeleech, r.FreeleechPercent, pq.Array(r.Bonus)).
 		Suffix("RETURNING id").RunWith(repo.db.handler)
 
 	// return values
@@ -57,13 +57,89 @@ func (repo *ReleaseRepo) Store(ctx context.Context, r *domain.Release) (*domain.
 	return r, nil
 }
 
+func (repo *ReleaseRepo) FindByID(ctx context.Context, id int64) (*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "r.filter", "r.protocol", "r.implementation", "r.timestamp", "r.group_id", "r.torrent_id", "r.info_url", "r.download_url", "r.magnet_uri", "r.torrent_name", "r.size", "r.title", "r.category", "r.season", "r.episode", "r.episode_end", "r.year", "r.month", "r.day", "r.resolution", "r.source", "r.codec", "r.container", "r.hdr", "r.release_group", "r.proper", "r.repack", "r.website", "r.type", "r.origin", "r.tags", "r.uploader", "r.pre_time", "r.filter_id", "r.freeleech", "r.freeleech_percent", "r.bonus").
+		From("release r").
+		Where(sq.Eq{"r.id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := repo.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var rls domain.Release
+
+	var indexer, filter, protocol, implementation, groupID, torrentID, infoURL, downloadURL, magnetURI, title, category, resolution, source, codec, container, hdr, group, website, releaseType, origin, uploader, preTime sql.NullString
+	var season, episode, episodeEnd, year, month, day sql.NullInt32
+	var size sql.NullInt64
+	var proper, repack, freeleech sql.NullBool
+	var filterID, freeleechPercent sql.NullInt32
+
+	if err := row.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &indexer, &filter, &protocol, &implementation, &rls.Timestamp, &groupID, &torrentID, &infoURL, &downloadURL, &magnetURI, &rls.TorrentName, &size, &title, &category, &season, &episode, &episodeEnd, &year, &month, &day, &resolution, &source, &codec, &container, &hdr, &group, &proper, &repack, &website, &releaseType, &origin, pq.Array(&rls.Tags), &uploader, &preTime, &filterID, &freeleech, &freeleechPercent, pq.Array(&rls.Bonus)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(domain.ErrRecordNotFound, "release not found: %d", id)
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	rls.Indexer = indexer.String
+	rls.FilterName = filter.String
+	rls.Protocol = domain.ReleaseProtocol(protocol.String)
+	rls.Implementation = domain.ReleaseImplementation(implementation.String)
+	rls.GroupID = groupID.String
+	rls.TorrentID = torrentID.String
+	rls.InfoURL = infoURL.String
+	rls.TorrentURL = downloadURL.String
+	rls.MagnetURI = magnetURI.String
+	rls.Size = uint64(size.Int64)
+	rls.Title = title.String
+	rls.Category = category.String
+	rls.Season = int(season.Int32)
+	rls.Episode = int(episode.Int32)
+	rls.EpisodeEnd = int(episodeEnd.Int32)
+	rls.Year = int(year.Int32)
+	rls.Month = int(month.Int32)
+	rls.Day = int(day.Int32)
+	rls.Resolution = resolution.String
+	rls.Source = source.String
+	rls.Container = container.String
+	rls.Group = group.String
+	rls.Proper = proper.Bool
+	rls.Repack = repack.Bool
+	rls.Website = website.String
+	rls.Type = releaseType.String
+	rls.Origin = origin.String
+	rls.Uploader = uploader.String
+	rls.PreTime = preTime.String
+	rls.FilterID = int(filterID.Int32)
+	rls.Freeleech = freeleech.Bool
+	rls.FreeleechPercent = int(freeleechPercent.Int32)
+
+	if codec.String != "" {
+		rls.Codec = strings.Split(codec.String, ",")
+	}
+
+	if hdr.String != "" {
+		rls.HDR = strings.Split(hdr.String, ",")
+	}
+
+	return &rls, nil
+}
+
 func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
 	if status.ID != 0 {
 		queryBuilder := repo.db.squirrel.
 			Update("release_action_status").
 			Set("status", status.Status).
 			Set("rejections", pq.Array(status.Rejections)).
-			Set("timestamp", status.Timestamp.Format(time.RFC3339)).
+			Set("timestamp", status.Timestamp.UTC().Format(time.RFC3339)).
 			Where(sq.Eq{"id": status.ID}).
 			Where(sq.Eq{"release_id": status.ReleaseID})
 
@@ -80,7 +156,7 @@ func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *d
 		queryBuilder := repo.db.squirrel.
 			Insert("release_action_status").
 			Columns("status", "action", "type", "client", "filter", "filter_id", "rejections", "timestamp", "release_id").
-			Values(status.Status, status.Action, status.Type, status.Client, status.Filter, status.FilterID, pq.Array(status.Rejections), status.Timestamp.Format(time.RFC3339), status.ReleaseID).
+			Values(status.Status, status.Action, status.Type, status.Client, status.Filter, status.FilterID, pq.Array(status.Rejections), status.Timestamp.UTC().Format(time.RFC3339), status.ReleaseID).
 			Suffix("RETURNING id").RunWith(repo.db.handler)
 
 		// return values
@@ -492,35 +568,296 @@ func (repo *ReleaseRepo) Delete(ctx context.Context) error {
 	return nil
 }
 
-func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error) {
-	// TODO support non season episode shows
-	// if rls.Day > 0 {
-	//	// Maybe in the future
-	//	// SELECT '' FROM release WHERE Title LIKE %q AND ((Year == %d AND Month == %d AND Day > %d) OR (Year == %d AND Month > %d) OR (Year > %d))"
-	//	qs := sql.Query("SELECT torrent_name FROM release WHERE Title LIKE %q AND Year >= %d", rls.Title, rls.Year)
-	//
-	//	for q := range qs.Rows() {
-	//		r := rls.ParseTitle(q)
-	//		if r.Year > rls.Year {
-	//			return false, fmt.Errorf("stale release year")
-	//		}
-	//
-	//		if r.Month > rls.Month {
-	//			return false, fmt.Errorf("stale release month")
-	//		}
-	//
-	//		if r.Month == rls.Month && r.Day > rls.Day {
-	//			return false, fmt.Errorf("stale release day")
-	//		}
-	//	}
-	//}
+// Prune deletes the releases matching the params together with their action results.
+// Releases waiting for a filter delay or an action retry are kept until they have run.
+func (repo *ReleaseRepo) Prune(ctx context.Context, params domain.ReleasePruneParams) (int64, error) {
+	queryBuilder := repo.db.squirrel.
+		Delete("release").
+		Where(sq.Lt{"timestamp": params.Before.UTC().Format(time.RFC3339)}).
+		Where("id NOT IN (SELECT release_id FROM release_delay)").
+		Where("id NOT IN (SELECT release_id FROM release_action_retry)")
+
+	if len(params.Indexers) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"indexer": params.Indexers})
+	}
+
+	if len(params.Statuses) > 0 {
+		statuses := sq.Or{}
+		for _, status := range params.Statuses {
+			statuses = append(statuses, releasePruneStatusCondition(status))
+		}
+
+		queryBuilder = queryBuilder.Where(statuses)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	// sqlite does not enforce the foreign keys so clean up what belonged to the deleted releases
+	if _, err := tx.ExecContext(ctx, `DELETE FROM release_action_status WHERE release_id NOT IN (SELECT id FROM "release")`); err != nil {
+		return 0, errors.Wrap(err, "error deleting from release_action_status")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "error commit transaction prune")
+	}
+
+	repo.log.Debug().Msgf("release.prune: deleted %d releases older than %s", rows, params.Before)
+
+	return rows, nil
+}
+
+// releasePruneStatusCondition matches releases by status. FILTER_REJECTED is the filter status,
+// the push statuses match releases where it is the best action result.
+func releasePruneStatusCondition(status string) sq.Sqlizer {
+	if status == string(domain.ReleaseStatusFilterRejected) {
+		return sq.Eq{"filter_status": status}
+	}
+
+	const exists = `EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = "release".id AND ras.status = ?)`
+
+	condition := sq.And{sq.Expr(exists, status)}
+	for _, s := range domain.ReleasePruneStatusOrder {
+		if string(s) == status {
+			break
+		}
+
+		condition = append(condition, sq.Expr("NOT "+exists, s))
+	}
+
+	return condition
+}
+
+func (repo *ReleaseRepo) StoreDelay(ctx context.Context, delay *domain.ReleaseDelay) error {
+	queryBuilder := repo.db.squirrel.
+		Insert("release_delay").
+		Columns("release_id", "filter_id", "run_at").
+		Values(delay.ReleaseID, delay.FilterID, delay.RunAt.UTC().Format(time.RFC3339)).
+		Suffix("RETURNING id").RunWith(repo.db.handler)
+
+	var retID int64
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&retID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	delay.ID = retID
+
+	repo.log.Trace().Msgf("release.store_delay: %+v", delay)
+
+	return nil
+}
+
+func (repo *ReleaseRepo) FindDelays(ctx context.Context) ([]domain.ReleaseDelay, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("d.id", "d.release_id", "d.filter_id", "f.name", "r.indexer", "r.torrent_name", "d.run_at", "d.created_at").
+		From("release_delay d").
+		Join("release r ON r.id = d.release_id").
+		LeftJoin("filter f ON f.id = d.filter_id").
+		OrderBy("d.run_at ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	res := make([]domain.ReleaseDelay, 0)
+
+	for rows.Next() {
+		var d domain.ReleaseDelay
+
+		var filter, indexer, torrentName sql.NullString
 
+		if err := rows.Scan(&d.ID, &d.ReleaseID, &d.FilterID, &filter, &indexer, &torrentName, &d.RunAt, &d.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		d.Filter = filter.String
+		d.Indexer = indexer.String
+		d.TorrentName = torrentName.String
+
+		res = append(res, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return res, nil
+}
+
+// DeleteDelay deletes a delayed release and reports if it existed, which is used to claim it before running
+func (repo *ReleaseRepo) DeleteDelay(ctx context.Context, id int64) (bool, error) {
+	queryBuilder := repo.db.squirrel.
+		Delete("release_delay").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	res, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+func (repo *ReleaseRepo) StoreRetry(ctx context.Context, retry *domain.ReleaseActionRetry) error {
+	queryBuilder := repo.db.squirrel.
+		Insert("release_action_retry").
+		Columns("release_id", "action_status_id", "filter_id", "action_id", "attempts", "next_run_at", "last_error").
+		Values(retry.ReleaseID, retry.ActionStatusID, retry.FilterID, retry.ActionID, retry.Attempts, retry.NextRunAt.UTC().Format(time.RFC3339), toNullString(retry.LastError)).
+		Suffix("RETURNING id").RunWith(repo.db.handler)
+
+	var retID int64
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&retID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	retry.ID = retID
+
+	repo.log.Trace().Msgf("release.store_retry: %+v", retry)
+
+	return nil
+}
+
+func (repo *ReleaseRepo) FindRetries(ctx context.Context) ([]domain.ReleaseActionRetry, error) {
+	return repo.findRetries(ctx, nil)
+}
+
+func (repo *ReleaseRepo) FindRetryByActionStatusID(ctx context.Context, actionStatusID int64) (*domain.ReleaseActionRetry, error) {
+	retries, err := repo.findRetries(ctx, sq.Eq{"rr.action_status_id": actionStatusID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(retries) == 0 {
+		return nil, errors.Wrap(domain.ErrRecordNotFound, "no retry found for action status: %d", actionStatusID)
+	}
+
+	return &retries[0], nil
+}
+
+func (repo *ReleaseRepo) findRetries(ctx context.Context, where sq.Sqlizer) ([]domain.ReleaseActionRetry, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("rr.id", "rr.release_id", "rr.action_status_id", "rr.filter_id", "rr.action_id", "ras.action", "ras.filter", "r.indexer", "r.torrent_name", "rr.attempts", "rr.next_run_at", "rr.last_error", "rr.created_at").
+		From("release_action_retry rr").
+		Join("release r ON r.id = rr.release_id").
+		Join("release_action_status ras ON ras.id = rr.action_status_id").
+		OrderBy("rr.next_run_at ASC")
+
+	if where != nil {
+		queryBuilder = queryBuilder.Where(where)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	res := make([]domain.ReleaseActionRetry, 0)
+
+	for rows.Next() {
+		var rr domain.ReleaseActionRetry
+
+		var filter, indexer, torrentName, lastError sql.NullString
+
+		if err := rows.Scan(&rr.ID, &rr.ReleaseID, &rr.ActionStatusID, &rr.FilterID, &rr.ActionID, &rr.Action, &filter, &indexer, &torrentName, &rr.Attempts, &rr.NextRunAt, &lastError, &rr.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		rr.Filter = filter.String
+		rr.Indexer = indexer.String
+		rr.TorrentName = torrentName.String
+		rr.LastError = lastError.String
+
+		res = append(res, rr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return res, nil
+}
+
+// DeleteRetry deletes a queued retry and reports if it existed, which is used to claim it before running
+func (repo *ReleaseRepo) DeleteRetry(ctx context.Context, id int64) (bool, error) {
+	queryBuilder := repo.db.squirrel.
+		Delete("release_action_retry").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	res, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error) {
 	queryBuilder := repo.db.squirrel.
 		Select("COUNT(*)").
 		From("release").
-		Where(ILike("title", title+"%"))
+		Where(ILike("title", release.Title+"%")).
+		Where(sq.NotEq{"filter_status": domain.ReleaseStatusFilterRejected})
 
-	if season > 0 && episode > 0 {
+	season, episode := release.Season, release.Episode
+
+	switch {
+	case season > 0 && episode > 0:
 		queryBuilder = queryBuilder.Where(sq.Or{
 			sq.And{
 				sq.Eq{"season": season},
@@ -528,9 +865,30 @@ func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, seas
 			},
 			sq.Gt{"season": season},
 		})
-	} else if season > 0 && episode == 0 {
+
+	case season > 0 && episode == 0:
 		queryBuilder = queryBuilder.Where(sq.Gt{"season": season})
-	} else {
+
+	case release.IsAbsoluteEpisode():
+		queryBuilder = queryBuilder.Where(sq.Eq{"season": 0}).Where(sq.Gt{"episode": episode})
+
+	case release.IsDaily():
+		year, month, day := release.Year, release.Month, release.Day
+
+		queryBuilder = queryBuilder.Where(sq.Gt{"month": 0}).Where(sq.Or{
+			sq.Gt{"year": year},
+			sq.And{
+				sq.Eq{"year": year},
+				sq.Gt{"month": month},
+			},
+			sq.And{
+				sq.Eq{"year": year},
+				sq.Eq{"month": month},
+				sq.Gt{"day": day},
+			},
+		})
+
+	default:
 		/* No support for this scenario today. Specifically multi-part specials.
 		 * The Database presently does not have Subtitle as a field, but is coming at a future date. */
 		return true, nil
@@ -558,3 +916,214 @@ func (repo *ReleaseRepo) CanDownloadShow(ctx context.Context, title string, seas
 
 	return true, nil
 }
+
+// FindSameEpisode finds grabbed releases of the same show covering any of the episodes of the release, or the same
+// air date for daily shows, to check for upgrades. Episodes are also matched against packs of their season,
+// and season packs against other packs of the season.
+func (repo *ReleaseRepo) FindSameEpisode(ctx context.Context, release *domain.Release) ([]*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("id", "torrent_name", "resolution", "source", "proper", "repack").
+		From("release").
+		Where(ILike("title", release.Title)).
+		Where(sq.NotEq{"filter_status": domain.ReleaseStatusFilterRejected}).
+		// releases that were pushed, or are still waiting for their actions, but not those where every push was rejected or failed
+		Where(sq.Or{
+			sq.Expr(`EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = "release".id AND ras.status = ?)`, domain.ReleasePushStatusApproved),
+			sq.Expr(`NOT EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = "release".id AND ras.status != ?)`, domain.ReleasePushStatusPending),
+		}).
+		Where(sq.NotEq{"id": release.ID}).
+		OrderBy("id ASC")
+
+	switch {
+	case release.IsDaily():
+		queryBuilder = queryBuilder.Where(sq.Eq{"year": release.Year, "month": release.Month, "day": release.Day})
+
+	case release.IsSeasonPack():
+		queryBuilder = queryBuilder.Where(sq.Eq{"season": release.Season, "episode": 0})
+
+	default:
+		last := release.Episode
+		if release.EpisodeEnd > last {
+			last = release.EpisodeEnd
+		}
+
+		// multi-episode releases are stored with the first and last episode
+		overlaps := sq.And{
+			sq.LtOrEq{"episode": last},
+			sq.Expr("COALESCE(episode_end, episode) >= ?", release.Episode),
+		}
+
+		if release.Season > 0 {
+			queryBuilder = queryBuilder.Where(sq.Eq{"season": release.Season}).Where(sq.Or{overlaps, sq.Eq{"episode": 0}})
+		} else {
+			queryBuilder = queryBuilder.Where(sq.Eq{"season": 0}).Where(overlaps)
+		}
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	res := make([]*domain.Release, 0)
+
+	for rows.Next() {
+		var r domain.Release
+
+		var resolution, source sql.NullString
+		var proper, repack sql.NullBool
+
+		if err := rows.Scan(&r.ID, &r.TorrentName, &resolution, &source, &proper, &repack); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		r.Resolution = resolution.String
+		r.Source = source.String
+		r.Proper = proper.Bool
+		r.Repack = repack.Bool
+
+		res = append(res, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return res, nil
+}
+
+// FindDuplicates finds releases pushed to a download client with a similar name, or the same episode if enabled.
+// The candidates are compared with domain.Release.IsDuplicateOf by the caller.
+func (repo *ReleaseRepo) FindDuplicates(ctx context.Context, params domain.ReleaseDuplicateParams) ([]*domain.Release, error) {
+	rls := params.Release
+
+	match := sq.Or{ILike("torrent_name", dupeNamePattern(rls.TorrentName))}
+
+	if params.MatchEpisode && rls.Title != "" && (rls.Season > 0 || rls.Episode > 0) {
+		match = append(match, sq.And{
+			ILike("title", rls.Title),
+			sq.Eq{"season": rls.Season},
+			sq.Eq{"episode": rls.Episode},
+		})
+	}
+
+	queryBuilder := repo.db.squirrel.
+		Select("id", "torrent_name", "indexer", "title", "season", "episode", "resolution", "source", "timestamp").
+		From("release").
+		Where(sq.Eq{"filter_status": domain.ReleaseStatusFilterApproved}).
+		Where(sq.Expr(`EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = "release".id AND ras.status = ?)`, domain.ReleasePushStatusApproved)).
+		Where(sq.NotEq{"id": rls.ID}).
+		Where(match).
+		OrderBy("timestamp ASC")
+
+	if params.Indexer != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"indexer": params.Indexer})
+	}
+
+	if !params.Since.IsZero() {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"timestamp": params.Since.UTC().Format(time.RFC3339)})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	res := make([]*domain.Release, 0)
+
+	for rows.Next() {
+		var r domain.Release
+
+		var indexer, title, resolution, source sql.NullString
+		var season, episode sql.NullInt32
+
+		if err := rows.Scan(&r.ID, &r.TorrentName, &indexer, &title, &season, &episode, &resolution, &source, &r.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		r.Indexer = indexer.String
+		r.Title = title.String
+		r.Season = int(season.Int32)
+		r.Episode = int(episode.Int32)
+		r.Resolution = resolution.String
+		r.Source = source.String
+
+		res = append(res, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return res, nil
+}
+
+// FindGrabbedSince finds releases pushed to a download client since the given time
+func (repo *ReleaseRepo) FindGrabbedSince(ctx context.Context, since time.Time) ([]*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.torrent_name", "r.indexer", "r.filter", "r.size", "r.timestamp").
+		From("release r").
+		Where(sq.GtOrEq{"r.timestamp": since.UTC().Format(time.RFC3339)}).
+		Where(sq.Expr("EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = r.id AND ras.status = ?)", domain.ReleasePushStatusApproved)).
+		OrderBy("r.timestamp ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := repo.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	res := make([]*domain.Release, 0)
+
+	for rows.Next() {
+		var r domain.Release
+
+		var indexer, filter sql.NullString
+		var size sql.NullInt64
+
+		if err := rows.Scan(&r.ID, &r.TorrentName, &indexer, &filter, &size, &r.Timestamp); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		r.Indexer = indexer.String
+		r.FilterName = filter.String
+		r.Size = uint64(size.Int64)
+
+		res = append(res, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return res, nil
+}
+
+// dupeNamePattern builds a LIKE pattern matching the name with any separator between the words
+func dupeNamePattern(name string) string {
+	name = strings.NewReplacer("%", "", "_", " ").Replace(strings.TrimSpace(name))
+
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '.'
+	}), "_")
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseRepo_FindSameEpisode(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReleaseRepo(logger.Mock(), setupTestSQLite(t))
+
+	store := func(name string, season, episode, episodeEnd int) int64 {
+		r, err := repo.Store(ctx, &domain.Release{
+			FilterStatus: domain.ReleaseStatusFilterApproved,
+			TorrentName:  name,
+			Title:        "Show",
+			Season:       season,
+			Episode:      episode,
+			EpisodeEnd:   episodeEnd,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.ID
+	}
+
+	multi := store("Show.S01E01-E03.1080p.WEB-DL-GRP", 1, 1, 3)
+	single := store("Show.S01E05.1080p.WEB-DL-GRP", 1, 5, 0)
+	pack := store("Show.S02.1080p.WEB-DL-GRP", 2, 0, 0)
+	absolute := store("[GRP] Show - 1071 (1080p)", 0, 1071, 0)
+
+	tests := []struct {
+		name    string
+		release domain.Release
+		want    []int64
+	}{
+		{name: "episode_in_multi_episode", release: domain.Release{Title: "Show", Season: 1, Episode: 2}, want: []int64{multi}},
+		{name: "multi_episode_overlapping", release: domain.Release{Title: "Show", Season: 1, Episode: 3, EpisodeEnd: 5}, want: []int64{multi, single}},
+		{name: "episode_not_grabbed", release: domain.Release{Title: "Show", Season: 1, Episode: 4}, want: []int64{}},
+		{name: "episode_in_season_pack", release: domain.Release{Title: "Show", Season: 2, Episode: 4}, want: []int64{pack}},
+		{name: "season_pack", release: domain.Release{Title: "Show", Season: 2}, want: []int64{pack}},
+		{name: "season_pack_without_pack_grabbed", release: domain.Release{Title: "Show", Season: 1}, want: []int64{}},
+		{name: "absolute_episode", release: domain.Release{Title: "Show", Episode: 1071}, want: []int64{absolute}},
+		{name: "other_show", release: domain.Release{Title: "Other Show", Season: 1, Episode: 2}, want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.FindSameEpisode(ctx, &tt.release)
+			assert.NoError(t, err)
+
+			ids := make([]int64, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestReleaseRepo_Prune(t *testing.T) {
+	ctx := context.Background()
+	repo := NewReleaseRepo(logger.Mock(), setupTestSQLite(t))
+
+	store := func(name string) int64 {
+		r, err := repo.Store(ctx, &domain.Release{
+			FilterStatus: domain.ReleaseStatusFilterApproved,
+			TorrentName:  name,
+			Timestamp:    time.Now().Add(-48 * time.Hour),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status := &domain.ReleaseActionStatus{ReleaseID: r.ID, Status: domain.ReleasePushStatusErr, Action: "qbit", Type: domain.ActionTypeQbittorrent, Rejections: []string{}, Timestamp: r.Timestamp}
+		if err := repo.StoreReleaseActionStatus(ctx, status); err != nil {
+			t.Fatal(err)
+		}
+
+		return r.ID
+	}
+
+	old := store("That.Movie.2023.1080p.WEB.H264-GROUP")
+	delayed := store("That.Show.S01E01.1080p.WEB.H264-GROUP")
+	retried := store("That.Show.S01E02.1080p.WEB.H264-GROUP")
+
+	if err := repo.StoreDelay(ctx, &domain.ReleaseDelay{ReleaseID: delayed, FilterID: 1, RunAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := repo.GetActionStatusByReleaseID(ctx, retried)
+	if err != nil || len(statuses) != 1 {
+		t.Fatalf("could not find action status: %v", err)
+	}
+
+	if err := repo.StoreRetry(ctx, &domain.ReleaseActionRetry{ReleaseID: retried, ActionStatusID: statuses[0].ID, FilterID: 1, ActionID: 1, NextRunAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := repo.Prune(ctx, domain.ReleasePruneParams{Before: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = repo.FindByID(ctx, old)
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+	statuses, err = repo.GetActionStatusByReleaseID(ctx, old)
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	// the releases waiting for a delay or retry are kept together with their queue rows
+	for _, id := range []int64{delayed, retried} {
+		_, err := repo.FindByID(ctx, id)
+		assert.NoError(t, err)
+	}
+
+	delays, err := repo.FindDelays(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, delays, 1)
+
+	retries, err := repo.FindRetries(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, retries, 1)
+}
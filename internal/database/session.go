@@ -0,0 +1,192 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type SessionRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewSessionRepo(log logger.Logger, db *DB) domain.SessionRepo {
+	return &SessionRepo{
+		log: log.With().Str("repo", "session").Logger(),
+		db:  db,
+	}
+}
+
+func (r *SessionRepo) Store(ctx context.Context, session *domain.Session) error {
+	queryBuilder := r.db.squirrel.
+		Insert("user_session").
+		Columns("id", "user_id", "ip", "user_agent", "created_at", "last_seen").
+		Values(session.ID, session.UserID, session.IP, session.UserAgent, session.CreatedAt, session.LastSeen)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "user_id", "ip", "user_agent", "created_at", "last_seen").
+		From("user_session").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var s domain.Session
+
+	if err := row.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeen); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return &s, nil
+}
+
+func (r *SessionRepo) ListByUser(ctx context.Context, userID int) ([]domain.Session, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "user_id", "ip", "user_agent", "created_at", "last_seen").
+		From("user_session").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("last_seen DESC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	sessions := make([]domain.Session, 0)
+	for rows.Next() {
+		var s domain.Session
+
+		if err := rows.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeen); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepo) Touch(ctx context.Context, id string, lastSeen time.Time) error {
+	queryBuilder := r.db.squirrel.
+		Update("user_session").
+		Set("last_seen", lastSeen).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) Delete(ctx context.Context, id string) error {
+	queryBuilder := r.db.squirrel.
+		Delete("user_session").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// DeleteByUser deletes all sessions of the user, except the given session id if not empty
+func (r *SessionRepo) DeleteByUser(ctx context.Context, userID int, except string) error {
+	queryBuilder := r.db.squirrel.
+		Delete("user_session").
+		Where(sq.Eq{"user_id": userID})
+
+	if except != "" {
+		queryBuilder = queryBuilder.Where(sq.NotEq{"id": except})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	result, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, _ := result.RowsAffected()
+
+	r.log.Debug().Msgf("deleted %d sessions for user: %d", rows, userID)
+
+	return nil
+}
+
+func (r *SessionRepo) DeleteExpired(ctx context.Context, lastSeenBefore time.Time) error {
+	queryBuilder := r.db.squirrel.
+		Delete("user_session").
+		Where(sq.Lt{"last_seen": lastSeenBefore})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
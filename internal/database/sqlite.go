@@ -4,6 +4,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -78,6 +79,13 @@ func (db *DB) migrateSQLite() error {
 		return errors.New("autobrr (version %d) older than schema (version: %d)", len(sqliteMigrations), version)
 	}
 
+	// keep a copy of the database in case the upgrade goes wrong, there is nothing to keep for a new database
+	if version > 0 {
+		if _, err := db.backupSQLite(context.Background(), migrationBackupSuffix(version)); err != nil {
+			return errors.Wrap(err, "could not backup database before schema upgrade")
+		}
+	}
+
 	db.log.Info().Msgf("Beginning database schema upgrade from version %v to version: %v", version, len(sqliteMigrations))
 
 	tx, err := db.handler.Begin()
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	backupPrefix = "autobrr-"
+	backupExt    = ".db"
+)
+
+// Backup writes a copy of the sqlite database to the backup directory and removes backups above the retention count
+func (db *DB) Backup(ctx context.Context) (*domain.DatabaseBackup, error) {
+	if db.Driver != "sqlite" {
+		return nil, errors.New("database backups are only supported for sqlite")
+	}
+
+	return db.backupSQLite(ctx, "")
+}
+
+// backupSQLite uses VACUUM INTO which gives a consistent copy while the database is in use
+func (db *DB) backupSQLite(ctx context.Context, suffix string) (*domain.DatabaseBackup, error) {
+	if err := os.MkdirAll(db.backupDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create backup dir: %s", db.backupDir)
+	}
+
+	now := time.Now()
+	name := backupPrefix + now.UTC().Format("20060102-150405")
+	if suffix != "" {
+		name += "-" + suffix
+	}
+	name += backupExt
+
+	path := filepath.Join(db.backupDir, name)
+
+	if _, err := db.handler.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return nil, errors.Wrap(err, "could not backup database to: %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat backup: %s", path)
+	}
+
+	db.log.Info().Msgf("database backup written to: %s", path)
+
+	if err := db.pruneBackups(); err != nil {
+		db.log.Error().Err(err).Msg("could not remove old database backups")
+	}
+
+	return &domain.DatabaseBackup{
+		Name:      name,
+		Size:      info.Size(),
+		CreatedAt: now,
+	}, nil
+}
+
+// ListBackups returns the database backups, newest first
+func (db *DB) ListBackups() ([]domain.DatabaseBackup, error) {
+	backups := make([]domain.DatabaseBackup, 0)
+
+	entries, err := os.ReadDir(db.backupDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return backups, nil
+		}
+		return nil, errors.Wrap(err, "could not read backup dir: %s", db.backupDir)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !isBackupName(e.Name()) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, domain.DatabaseBackup{
+			Name:      e.Name(),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	// names start with the timestamp
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+
+	return backups, nil
+}
+
+// BackupPath returns the path of a backup by name, rejecting anything outside the backup directory
+func (db *DB) BackupPath(name string) (string, error) {
+	if !isBackupName(name) || filepath.Base(name) != name {
+		return "", errors.New("invalid backup name: %s", name)
+	}
+
+	path := filepath.Join(db.backupDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Wrap(err, "could not find backup: %s", name)
+	}
+
+	return path, nil
+}
+
+func (db *DB) pruneBackups() error {
+	if db.backupRetention <= 0 {
+		return nil
+	}
+
+	backups, err := db.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	if len(backups) <= db.backupRetention {
+		return nil
+	}
+
+	for _, b := range backups[db.backupRetention:] {
+		if err := os.Remove(filepath.Join(db.backupDir, b.Name)); err != nil {
+			return errors.Wrap(err, "could not remove backup: %s", b.Name)
+		}
+
+		db.log.Debug().Msgf("removed old database backup: %s", b.Name)
+	}
+
+	return nil
+}
+
+func isBackupName(name string) bool {
+	return strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupExt)
+}
+
+// migrationBackupSuffix marks backups taken before a schema upgrade
+func migrationBackupSuffix(from int) string {
+	return fmt.Sprintf("pre-migration-v%d", from)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestBackups(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("backup"), 0644))
+	}
+}
+
+func TestDB_ListBackups(t *testing.T) {
+	dir := t.TempDir()
+	db := &DB{log: zerolog.Nop(), backupDir: dir}
+
+	writeTestBackups(t, dir,
+		"autobrr-20230101-000000.db",
+		"autobrr-20230103-000000-pre-migration-v70.db",
+		"autobrr-20230102-000000.db",
+		"autobrr.db",
+		"notes.txt",
+	)
+
+	backups, err := db.ListBackups()
+	assert.NoError(t, err)
+	assert.Len(t, backups, 3)
+	assert.Equal(t, "autobrr-20230103-000000-pre-migration-v70.db", backups[0].Name)
+	assert.Equal(t, "autobrr-20230101-000000.db", backups[2].Name)
+	assert.Equal(t, int64(6), backups[0].Size)
+}
+
+func TestDB_ListBackups_missingDir(t *testing.T) {
+	db := &DB{log: zerolog.Nop(), backupDir: filepath.Join(t.TempDir(), "missing")}
+
+	backups, err := db.ListBackups()
+	assert.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestDB_pruneBackups(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention int
+		want      int
+	}{
+		{name: "keep_two", retention: 2, want: 2},
+		{name: "keep_more_than_present", retention: 10, want: 4},
+		{name: "keep_all", retention: 0, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			db := &DB{log: zerolog.Nop(), backupDir: dir, backupRetention: tt.retention}
+
+			writeTestBackups(t, dir,
+				"autobrr-20230101-000000.db",
+				"autobrr-20230102-000000.db",
+				"autobrr-20230103-000000.db",
+				"autobrr-20230104-000000.db",
+			)
+
+			assert.NoError(t, db.pruneBackups())
+
+			backups, err := db.ListBackups()
+			assert.NoError(t, err)
+			assert.Len(t, backups, tt.want)
+			assert.Equal(t, "autobrr-20230104-000000.db", backups[0].Name)
+		})
+	}
+}
+
+func TestDB_BackupPath(t *testing.T) {
+	dir := t.TempDir()
+	db := &DB{log: zerolog.Nop(), backupDir: dir}
+
+	writeTestBackups(t, dir, "autobrr-20230101-000000.db")
+
+	path, err := db.BackupPath("autobrr-20230101-000000.db")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "autobrr-20230101-000000.db"), path)
+
+	_, err = db.BackupPath("autobrr-20230102-000000.db")
+	assert.Error(t, err)
+
+	_, err = db.BackupPath("../autobrr-20230101-000000.db")
+	assert.Error(t, err)
+
+	_, err = db.BackupPath("config.toml")
+	assert.Error(t, err)
+}
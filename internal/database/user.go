@@ -12,6 +12,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -49,11 +50,27 @@ func (r *UserRepo) GetUserCount(ctx context.Context) (int, error) {
 }
 
 func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.find(ctx, sq.Eq{"username": username})
+}
+
+func (r *UserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	return r.find(ctx, sq.Eq{"id": id})
+}
+
+// FindByOIDCSubject finds the user linked to the subject of an OpenID Connect identity
+func (r *UserRepo) FindByOIDCSubject(ctx context.Context, subject string) (*domain.User, error) {
+	if subject == "" {
+		return nil, nil
+	}
+
+	return r.find(ctx, sq.Eq{"oidc_subject": subject})
+}
 
+func (r *UserRepo) find(ctx context.Context, where sq.Eq) (*domain.User, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "username", "password").
+		Select("id", "username", "password", "role", "two_factor_enabled", "two_factor_secret", "recovery_codes", "oidc_subject", "created_at").
 		From("users").
-		Where(sq.Eq{"username": username})
+		Where(where)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -67,7 +84,7 @@ func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain
 
 	var user domain.User
 
-	if err := row.Scan(&user.ID, &user.Username, &user.Password); err != nil {
+	if err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.TwoFactorEnabled, &user.TwoFactorSecret, pq.Array(&user.RecoveryCodes), &user.OIDCSubject, &user.CreatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -78,14 +95,50 @@ func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain
 	return &user, nil
 }
 
+func (r *UserRepo) List(ctx context.Context) ([]domain.User, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "username", "role", "two_factor_enabled", "created_at").
+		From("users").
+		OrderBy("id ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var user domain.User
+
+		if err := rows.Scan(&user.ID, &user.Username, &user.Role, &user.TwoFactorEnabled, &user.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return users, nil
+}
+
 func (r *UserRepo) Store(ctx context.Context, req domain.CreateUserRequest) error {
 
 	var err error
 
 	queryBuilder := r.db.squirrel.
 		Insert("users").
-		Columns("username", "password").
-		Values(req.Username, req.Password)
+		Columns("username", "password", "role", "oidc_subject").
+		Values(req.Username, req.Password, req.Role, req.OIDCSubject)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -104,11 +157,22 @@ func (r *UserRepo) Update(ctx context.Context, user domain.User) error {
 
 	var err error
 
+	// a nil slice is stored as NULL
+	if user.RecoveryCodes == nil {
+		user.RecoveryCodes = []string{}
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("users").
 		Set("username", user.Username).
 		Set("password", user.Password).
-		Where(sq.Eq{"username": user.Username})
+		Set("role", user.Role).
+		Set("two_factor_enabled", user.TwoFactorEnabled).
+		Set("two_factor_secret", user.TwoFactorSecret).
+		Set("recovery_codes", pq.Array(user.RecoveryCodes)).
+		Set("oidc_subject", user.OIDCSubject).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": user.ID})
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -122,3 +186,46 @@ func (r *UserRepo) Update(ctx context.Context, user domain.User) error {
 
 	return err
 }
+
+func (r *UserRepo) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// sqlite does not enforce the foreign key, so sessions are removed explicitly
+	sessionQuery, sessionArgs, err := r.db.squirrel.
+		Delete("user_session").
+		Where(sq.Eq{"user_id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, sessionQuery, sessionArgs...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Delete("users").
+		Where(sq.Eq{"id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	r.log.Debug().Msgf("user.delete: successfully deleted: %v", id)
+
+	return nil
+}
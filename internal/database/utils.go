@@ -6,6 +6,8 @@ package database
 import (
 	"database/sql"
 	"path"
+
+	"github.com/lib/pq"
 )
 
 func dataSourceName(configPath string, name string) string {
@@ -42,3 +44,21 @@ func toNullFloat64(s float64) sql.NullFloat64 {
 		Valid:   s != 0,
 	}
 }
+
+func toInt64Array(values []int) pq.Int64Array {
+	arr := make(pq.Int64Array, 0, len(values))
+	for _, v := range values {
+		arr = append(arr, int64(v))
+	}
+
+	return arr
+}
+
+func toIntSlice(arr pq.Int64Array) []int {
+	values := make([]int, 0, len(arr))
+	for _, v := range arr {
+		values = append(values, int(v))
+	}
+
+	return values
+}
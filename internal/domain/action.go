@@ -7,6 +7,7 @@ import (
 	"context"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/autobrr/autobrr/pkg/errors"
 )
@@ -16,6 +17,7 @@ type ActionRepo interface {
 	StoreFilterActions(ctx context.Context, actions []*Action, filterID int64) ([]*Action, error)
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	FindByFilterID(ctx context.Context, filterID int) ([]*Action, error)
+	FindByID(ctx context.Context, actionID int) (*Action, error)
 	List(ctx context.Context) ([]Action, error)
 	Delete(actionID int) error
 	ToggleEnabled(actionID int) error
@@ -50,19 +52,26 @@ type Action struct {
 	WebhookMethod         string              `json:"webhook_method,omitempty"`
 	WebhookData           string              `json:"webhook_data,omitempty"`
 	WebhookHeaders        []string            `json:"webhook_headers,omitempty"`
+	RetryAttempts         int                 `json:"retry_attempts,omitempty"`
+	RetryInterval         int                 `json:"retry_interval,omitempty"`
+	CrossSeed             ActionCrossSeed     `json:"cross_seed,omitempty"`
 	FilterID              int                 `json:"filter_id,omitempty"`
 	ClientID              int32               `json:"client_id,omitempty"`
 	Client                *DownloadClient     `json:"client,omitempty"`
 }
 
+// NeedsTorrentFile checks if the macros or the action type need the downloaded torrent file
+func (a *Action) NeedsTorrentFile() bool {
+	return strings.Contains(a.ExecArgs, "TorrentPathName") || strings.Contains(a.ExecArgs, "TorrentDataRawBytes") ||
+		strings.Contains(a.WebhookData, "TorrentPathName") || strings.Contains(a.WebhookData, "TorrentDataRawBytes") ||
+		strings.Contains(a.SavePath, "TorrentPathName") || a.Type == ActionTypeWatchFolder
+}
+
 // ParseMacros parse all macros on action
 func (a *Action) ParseMacros(release *Release) error {
 	var err error
 
-	if release.TorrentTmpFile == "" &&
-		(strings.Contains(a.ExecArgs, "TorrentPathName") || strings.Contains(a.ExecArgs, "TorrentDataRawBytes") ||
-			strings.Contains(a.WebhookData, "TorrentPathName") || strings.Contains(a.WebhookData, "TorrentDataRawBytes") ||
-			strings.Contains(a.SavePath, "TorrentPathName") || a.Type == ActionTypeWatchFolder) {
+	if release.TorrentTmpFile == "" && a.NeedsTorrentFile() {
 		if err := release.DownloadTorrentFile(); err != nil {
 			return errors.Wrap(err, "webhook: could not download torrent file for release: %v", release.TorrentName)
 		}
@@ -97,6 +106,27 @@ func (a *Action) ParseMacros(release *Release) error {
 	return nil
 }
 
+// RetryBackoff returns the wait before the given retry attempt. The retry interval in seconds doubles for every attempt.
+func (a *Action) RetryBackoff(attempt int) time.Duration {
+	interval := a.RetryInterval
+	if interval <= 0 {
+		interval = 60
+	}
+
+	backoff := time.Duration(interval) * time.Second
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+
+	return backoff
+}
+
+const maxRetryBackoff = 24 * time.Hour
+
 type ActionType string
 
 const (
@@ -118,6 +148,15 @@ const (
 	ActionTypeSabnzbd      ActionType = "SABNZBD"
 )
 
+type ActionCrossSeed string
+
+const (
+	// ActionCrossSeedSkip rejects the release if the content already exists in the client
+	ActionCrossSeedSkip ActionCrossSeed = "SKIP"
+	// ActionCrossSeedInject adds the torrent pointing at the existing content in the client
+	ActionCrossSeedInject ActionCrossSeed = "INJECT"
+)
+
 type ActionContentLayout string
 
 const (
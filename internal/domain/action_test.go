@@ -0,0 +1,32 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAction_RetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		attempt  int
+		want     time.Duration
+	}{
+		{name: "default_interval", interval: 0, attempt: 1, want: 60 * time.Second},
+		{name: "first_attempt", interval: 30, attempt: 1, want: 30 * time.Second},
+		{name: "second_attempt", interval: 30, attempt: 2, want: 60 * time.Second},
+		{name: "fourth_attempt", interval: 30, attempt: 4, want: 240 * time.Second},
+		{name: "capped", interval: 3600, attempt: 10, want: 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Action{RetryInterval: tt.interval}
+			assert.Equal(t, tt.want, a.RetryBackoff(tt.attempt))
+		})
+	}
+}
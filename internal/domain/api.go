@@ -5,7 +5,10 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
 )
 
 type APIRepo interface {
@@ -20,3 +23,99 @@ type APIKey struct {
 	Scopes    []string  `json:"scopes"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// APIKeyScopeAdmin grants access to everything, including managing api keys
+const APIKeyScopeAdmin = "admin"
+
+// APIKeyScopeResources are the resources a key can be scoped to, as resource:read or resource:write.
+// There is no webhook ingest endpoint yet, its scope should be added together with the route.
+var APIKeyScopeResources = []string{
+	"actions",
+	"config",
+	"download_clients",
+	"events",
+	"feeds",
+	"filters",
+	"indexers",
+	"irc",
+	"logs",
+	"metrics",
+	"notifications",
+	"releases",
+	"updates",
+}
+
+// APIKeySensitiveResources are the resources whose responses contain passwords, api keys, passkeys or tokens.
+// Reading them needs write access, so viewers and read only keys can't get at the credentials.
+var APIKeySensitiveResources = []string{
+	"actions",
+	"config",
+	"download_clients",
+	"feeds",
+	"indexers",
+	"irc",
+	"logs",
+	"notifications",
+}
+
+// IsSensitiveResource checks if reading the resource exposes credentials
+func IsSensitiveResource(resource string) bool {
+	for _, r := range APIKeySensitiveResources {
+		if r == resource {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateScopes checks that all scopes are known
+func (k APIKey) ValidateScopes() error {
+	for _, scope := range k.Scopes {
+		if scope == APIKeyScopeAdmin {
+			continue
+		}
+
+		resource, access, found := strings.Cut(scope, ":")
+		if !found || (access != "read" && access != "write") || !apiKeyScopeResource(resource) {
+			return errors.New("invalid api key scope: %q", scope)
+		}
+
+		if access == "read" && IsSensitiveResource(resource) {
+			return errors.New("api key scope %q would expose credentials, use %s:write", scope, resource)
+		}
+	}
+
+	return nil
+}
+
+// HasScope checks if the key grants access to the resource. A write scope implies read.
+// Read scopes don't grant access to sensitive resources. Keys without scopes are treated as admin keys.
+func (k APIKey) HasScope(resource string, write bool) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range k.Scopes {
+		switch scope {
+		case APIKeyScopeAdmin, resource + ":write":
+			return true
+		case resource + ":read":
+			if !write && !IsSensitiveResource(resource) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func apiKeyScopeResource(resource string) bool {
+	for _, r := range APIKeyScopeResources {
+		if r == resource {
+			return true
+		}
+	}
+
+	return false
+}
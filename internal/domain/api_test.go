@@ -0,0 +1,64 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_HasScope(t *testing.T) {
+	type args struct {
+		resource string
+		write    bool
+	}
+	tests := []struct {
+		name   string
+		scopes []string
+		args   args
+		want   bool
+	}{
+		{name: "no_scopes", scopes: []string{}, args: args{resource: "filters", write: true}, want: true},
+		{name: "admin", scopes: []string{"admin"}, args: args{resource: "config", write: true}, want: true},
+		{name: "read_get", scopes: []string{"releases:read"}, args: args{resource: "releases", write: false}, want: true},
+		{name: "read_write", scopes: []string{"releases:read"}, args: args{resource: "releases", write: true}, want: false},
+		{name: "write_read", scopes: []string{"filters:write"}, args: args{resource: "filters", write: false}, want: true},
+		{name: "write_write", scopes: []string{"filters:write"}, args: args{resource: "filters", write: true}, want: true},
+		{name: "other_resource", scopes: []string{"filters:write", "releases:read"}, args: args{resource: "config", write: false}, want: false},
+		{name: "read_sensitive", scopes: []string{"download_clients:read"}, args: args{resource: "download_clients", write: false}, want: false},
+		{name: "write_read_sensitive", scopes: []string{"download_clients:write"}, args: args{resource: "download_clients", write: false}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := APIKey{Scopes: tt.scopes}
+			assert.Equal(t, tt.want, k.HasScope(tt.args.resource, tt.args.write))
+		})
+	}
+}
+
+func TestAPIKey_ValidateScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr string
+	}{
+		{name: "empty", scopes: nil},
+		{name: "valid", scopes: []string{"admin", "filters:write", "releases:read"}},
+		{name: "unknown_resource", scopes: []string{"users:read"}, wantErr: `invalid api key scope: "users:read"`},
+		{name: "unknown_access", scopes: []string{"filters:delete"}, wantErr: `invalid api key scope: "filters:delete"`},
+		{name: "missing_access", scopes: []string{"filters"}, wantErr: `invalid api key scope: "filters"`},
+		{name: "read_sensitive", scopes: []string{"irc:read"}, wantErr: `api key scope "irc:read" would expose credentials, use irc:write`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := APIKey{Scopes: tt.scopes}.ValidateScopes()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type AuditRepo interface {
+	Store(ctx context.Context, event *AuditEvent) error
+	List(ctx context.Context, params AuditQueryParams) ([]AuditEvent, error)
+}
+
+// AuditEvent records a change made through the api
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	UserID    int       `json:"user_id,omitempty"`
+	Username  string    `json:"username"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource"`
+	Path      string    `json:"path"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type AuditQueryParams struct {
+	Resource string
+	Limit    uint64
+}
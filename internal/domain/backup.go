@@ -0,0 +1,44 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import "time"
+
+// BackupVersion is bumped when the backup format changes in an incompatible way
+const BackupVersion = 1
+
+// Backup holds the whole configuration. Ids are kept so references between
+// filters, actions, download clients, indexers and notifications can be restored.
+type Backup struct {
+	Version         int              `json:"version"`
+	AppVersion      string           `json:"app_version"`
+	CreatedAt       time.Time        `json:"created_at"`
+	Redacted        bool             `json:"redacted"`
+	DownloadClients []DownloadClient `json:"download_clients"`
+	Indexers        []Indexer        `json:"indexers"`
+	IrcNetworks     []IrcNetwork     `json:"irc_networks"`
+	Feeds           []Feed           `json:"feeds"`
+	Filters         []Filter         `json:"filters"`
+	Notifications   []Notification   `json:"notifications"`
+}
+
+type BackupOptions struct {
+	// Redact blanks passwords, api keys and other secrets
+	Redact bool
+}
+
+// BackupImportResult counts what was created and what was skipped because it already existed,
+// with warnings for references to things missing on this instance
+type BackupImportResult struct {
+	Created  map[string]int `json:"created"`
+	Skipped  map[string]int `json:"skipped"`
+	Warnings []string       `json:"warnings"`
+}
+
+// DatabaseBackup is a copy of the sqlite database in the backup directory
+type DatabaseBackup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
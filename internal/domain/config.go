@@ -4,24 +4,47 @@
 package domain
 
 type Config struct {
-	Version           string
-	ConfigPath        string
-	Host              string `toml:"host"`
-	Port              int    `toml:"port"`
-	LogLevel          string `toml:"logLevel"`
-	LogPath           string `toml:"logPath"`
-	LogMaxSize        int    `toml:"logMaxSize"`
-	LogMaxBackups     int    `toml:"logMaxBackups"`
-	BaseURL           string `toml:"baseUrl"`
-	SessionSecret     string `toml:"sessionSecret"`
-	CustomDefinitions string `toml:"customDefinitions"`
-	CheckForUpdates   bool   `toml:"checkForUpdates"`
-	DatabaseType      string `toml:"databaseType"`
-	PostgresHost      string `toml:"postgresHost"`
-	PostgresPort      int    `toml:"postgresPort"`
-	PostgresDatabase  string `toml:"postgresDatabase"`
-	PostgresUser      string `toml:"postgresUser"`
-	PostgresPass      string `toml:"postgresPass"`
+	Version                   string
+	ConfigPath                string
+	Host                      string             `toml:"host"`
+	Port                      int                `toml:"port"`
+	LogLevel                  string             `toml:"logLevel"`
+	LogPath                   string             `toml:"logPath"`
+	LogMaxSize                int                `toml:"logMaxSize"`
+	LogMaxBackups             int                `toml:"logMaxBackups"`
+	BaseURL                   string             `toml:"baseUrl"`
+	SessionSecret             string             `toml:"sessionSecret"`
+	CustomDefinitions         string             `toml:"customDefinitions"`
+	CheckForUpdates           bool               `toml:"checkForUpdates"`
+	DatabaseType              string             `toml:"databaseType"`
+	SaveRejectedReleases      bool               `toml:"saveRejectedReleases"`
+	RejectedReleasesRetention int                `toml:"rejectedReleasesRetention"`
+	DupeProtection            bool               `toml:"dupeProtection"`
+	DupeWindow                int                `toml:"dupeWindow"`
+	DupeMatchEpisode          bool               `toml:"dupeMatchEpisode"`
+	FeedFailureThreshold      int                `toml:"feedFailureThreshold"`
+	IRCQuietThreshold         int                `toml:"ircQuietThreshold"`
+	DigestHour                int                `toml:"digestHour"`
+	OIDCEnabled               bool               `toml:"oidcEnabled"`
+	OIDCIssuer                string             `toml:"oidcIssuer"`
+	OIDCClientID              string             `toml:"oidcClientId"`
+	OIDCClientSecret          string             `toml:"oidcClientSecret"`
+	OIDCRedirectURL           string             `toml:"oidcRedirectUrl"`
+	OIDCAllowedGroups         []string           `toml:"oidcAllowedGroups"`
+	OIDCAutoCreateUsers       bool               `toml:"oidcAutoCreateUsers"`
+	LoginMaxAttempts          int                `toml:"loginMaxAttempts"`
+	LoginLockoutMinutes       int                `toml:"loginLockoutMinutes"`
+	TrustedProxies            []string           `toml:"trustedProxies"`
+	DatabaseBackupInterval    int                `toml:"databaseBackupInterval"`
+	DatabaseBackupRetention   int                `toml:"databaseBackupRetention"`
+	DatabaseBackupDir         string             `toml:"databaseBackupDir"`
+	MetricsEnabled            bool               `toml:"metricsEnabled"`
+	ReleaseRetention          []ReleasePruneRule `toml:"releaseRetention"`
+	PostgresHost              string             `toml:"postgresHost"`
+	PostgresPort              int                `toml:"postgresPort"`
+	PostgresDatabase          string             `toml:"postgresDatabase"`
+	PostgresUser              string             `toml:"postgresUser"`
+	PostgresPass              string             `toml:"postgresPass"`
 }
 
 type ConfigUpdate struct {
@@ -54,6 +54,13 @@ const (
 	FilterMaxDownloadsEver  FilterMaxDownloadsUnit = "EVER"
 )
 
+type FilterEpisodeType string
+
+const (
+	FilterEpisodeTypeSeasonPack FilterEpisodeType = "SEASON_PACK"
+	FilterEpisodeTypeEpisode    FilterEpisodeType = "EPISODE"
+)
+
 type FilterQueryParams struct {
 	Sort    map[string]string
 	Filters struct {
@@ -86,9 +93,12 @@ type Filter struct {
 	Freeleech                   bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent            string                 `json:"freeleech_percent,omitempty"`
 	SmartEpisode                bool                   `json:"smart_episode"`
+	SmartEpisodeUpgrade         bool                   `json:"smart_episode_upgrade"`
+	AllowDupeOtherIndexers      bool                   `json:"allow_dupe_other_indexers"`
 	Shows                       string                 `json:"shows,omitempty"`
 	Seasons                     string                 `json:"seasons,omitempty"`
 	Episodes                    string                 `json:"episodes,omitempty"`
+	EpisodeType                 FilterEpisodeType      `json:"episode_type,omitempty"`
 	Resolutions                 []string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
 	Codecs                      []string               `json:"codecs,omitempty"`      // XviD, DivX, x264, h.264 (or h264), mpeg2 (or mpeg-2), VC-1 (or VC1), WMV, Remux, h.264 Remux (or h264 Remux), VC-1 Remux (or VC1 Remux).
 	Sources                     []string               `json:"sources,omitempty"`     // DSR, PDTV, HDTV, HR.PDTV, HR.HDTV, DVDRip, DVDScr, BDr, BD5, BD9, BDRip, BRRip, DVDR, MDVDR, HDDVD, HDDVDRip, BluRay, WEB-DL, TVRip, CAM, R5, TELESYNC, TS, TELECINE, TC. TELESYNC and TS are synonyms (you don't need both). Same for TELECINE and TC
@@ -163,9 +173,12 @@ type FilterUpdate struct {
 	Freeleech                   *bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent            *string                 `json:"freeleech_percent,omitempty"`
 	SmartEpisode                *bool                   `json:"smart_episode,omitempty"`
+	SmartEpisodeUpgrade         *bool                   `json:"smart_episode_upgrade,omitempty"`
+	AllowDupeOtherIndexers      *bool                   `json:"allow_dupe_other_indexers,omitempty"`
 	Shows                       *string                 `json:"shows,omitempty"`
 	Seasons                     *string                 `json:"seasons,omitempty"`
 	Episodes                    *string                 `json:"episodes,omitempty"`
+	EpisodeType                 *FilterEpisodeType      `json:"episode_type,omitempty"`
 	Resolutions                 *[]string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
 	Codecs                      *[]string               `json:"codecs,omitempty"`      // XviD, DivX, x264, h.264 (or h264), mpeg2 (or mpeg-2), VC-1 (or VC1), WMV, Remux, h.264 Remux (or h264 Remux), VC-1 Remux (or VC1 Remux).
 	Sources                     *[]string               `json:"sources,omitempty"`     // DSR, PDTV, HDTV, HR.PDTV, HR.HDTV, DVDRip, DVDScr, BDr, BD5, BD9, BDRip, BRRip, DVDR, MDVDR, HDDVD, HDDVDRip, BluRay, WEB-DL, TVRip, CAM, R5, TELESYNC, TS, TELECINE, TC. TELESYNC and TS are synonyms (you don't need both). Same for TELECINE and TC
@@ -210,6 +223,74 @@ type FilterUpdate struct {
 	Indexers                    []Indexer               `json:"indexers,omitempty"`
 }
 
+// FilterDryRunRequest replays a release name against filters without running any actions
+type FilterDryRunRequest struct {
+	TorrentName      string `json:"torrent_name"`
+	Indexer          string `json:"indexer,omitempty"`
+	Size             string `json:"size,omitempty"`
+	Category         string `json:"category,omitempty"`
+	Freeleech        bool   `json:"freeleech,omitempty"`
+	FreeleechPercent int    `json:"freeleech_percent,omitempty"`
+	Uploader         string `json:"uploader,omitempty"`
+	ReleaseTags      string `json:"release_tags,omitempty"`
+	RunExternal      bool   `json:"run_external,omitempty"`
+}
+
+type FilterDryRunResult struct {
+	FilterID   int      `json:"filter_id"`
+	FilterName string   `json:"filter_name"`
+	Priority   int32    `json:"priority"`
+	Match      bool     `json:"match"`
+	Rejections []string `json:"rejections"`
+	Skipped    []string `json:"skipped"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// FilterExportVersion is bumped when the filter export format changes in an incompatible way
+const FilterExportVersion = 1
+
+// FilterExport is the portable format used to share filters between instances.
+// Indexers are referenced by identifier and download clients by name since ids differ per instance.
+type FilterExport struct {
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Filters    []FilterExportItem `json:"filters"`
+}
+
+type FilterExportItem struct {
+	Filter
+	Indexers []string             `json:"indexers"`
+	Actions  []FilterExportAction `json:"actions,omitempty"`
+}
+
+type FilterExportAction struct {
+	Action
+	Client string `json:"client,omitempty"`
+}
+
+// Redact removes the actions and the external script and webhook settings, which can hold credentials
+func (f *Filter) Redact() {
+	f.Actions = nil
+	f.ExternalScriptCmd = ""
+	f.ExternalScriptArgs = ""
+	f.ExternalWebhookHost = ""
+	f.ExternalWebhookData = ""
+}
+
+// Redact removes the actions and credentials of all exported filters
+func (e *FilterExport) Redact() {
+	for i := range e.Filters {
+		e.Filters[i].Filter.Redact()
+		e.Filters[i].Actions = nil
+	}
+}
+
+// FilterImportResult lists the created filters and everything that could not be mapped on this instance
+type FilterImportResult struct {
+	Filters  []Filter `json:"filters"`
+	Warnings []string `json:"warnings"`
+}
+
 func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 	// reset rejections first to clean previous checks
 	r.resetRejections()
@@ -244,14 +325,33 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("shows not matching. got: %v want: %v", r.Title, f.Shows)
 	}
 
-	if f.Seasons != "" && !containsIntStrings(r.Season, f.Seasons) {
-		r.addRejectionF("season not matching. got: %d want: %v", r.Season, f.Seasons)
+	// daily shows are numbered by air date, so seasons match the year
+	if f.Seasons != "" {
+		season := r.Season
+		if r.IsDaily() {
+			season = r.Year
+		}
+
+		if !containsIntStrings(season, f.Seasons) {
+			r.addRejectionF("season not matching. got: %d want: %v", season, f.Seasons)
+		}
 	}
 
-	if f.Episodes != "" && !containsIntStrings(r.Episode, f.Episodes) {
+	if f.Episodes != "" && !containsAnyIntStrings(r.Episodes(), f.Episodes) {
 		r.addRejectionF("episodes not matching. got: %d want: %v", r.Episode, f.Episodes)
 	}
 
+	switch f.EpisodeType {
+	case FilterEpisodeTypeSeasonPack:
+		if !r.IsSeasonPack() {
+			r.addRejection("wanted: season pack")
+		}
+	case FilterEpisodeTypeEpisode:
+		if r.Episode == 0 && !r.IsDaily() {
+			r.addRejection("wanted: single episode")
+		}
+	}
+
 	// matchRelease
 	// match against regex
 	if f.UseRegex {
@@ -575,6 +675,21 @@ func matchRegex(tag string, filterList string) bool {
 }
 
 // checkFilterIntStrings "1,2,3-20"
+// containsAnyIntStrings checks if any of the values is in the filter list. An empty list is checked as 0.
+func containsAnyIntStrings(values []int, filterList string) bool {
+	if len(values) == 0 {
+		return containsIntStrings(0, filterList)
+	}
+
+	for _, value := range values {
+		if containsIntStrings(value, filterList) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func containsIntStrings(value int, filterList string) bool {
 	filters := strings.Split(filterList, ",")
 
@@ -1806,6 +1806,55 @@ func TestFilter_CheckFilter1(t *testing.T) {
 			wantRejections: []string{"match release tags regex not matching. got:  want: foreign - 17"},
 			wantMatch:      false,
 		},
+		{
+			name: "test_43",
+			fields: fields{
+				Shows:       "Show Name",
+				EpisodeType: FilterEpisodeTypeSeasonPack,
+			},
+			args:      args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
+			wantMatch: true,
+		},
+		{
+			name: "test_44",
+			fields: fields{
+				Shows:       "Show Name",
+				EpisodeType: FilterEpisodeTypeSeasonPack,
+			},
+			args:           args{&Release{TorrentName: "Show.Name.S01E02.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
+			wantRejections: []string{"wanted: season pack"},
+			wantMatch:      false,
+		},
+		{
+			name: "test_45",
+			fields: fields{
+				Shows:       "Show Name",
+				EpisodeType: FilterEpisodeTypeEpisode,
+			},
+			args:           args{&Release{TorrentName: "Show.Name.S01.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
+			wantRejections: []string{"wanted: single episode"},
+			wantMatch:      false,
+		},
+		{
+			name: "test_46",
+			fields: fields{
+				Shows:    "Show Name",
+				Seasons:  "1",
+				Episodes: "3",
+			},
+			args:      args{&Release{TorrentName: "Show.Name.S01E02-E04.DV.2160p.ATVP.WEB-DL.DDPA5.1.x265-GROUP2"}},
+			wantMatch: true,
+		},
+		{
+			name: "test_47",
+			fields: fields{
+				Shows:       "Show Name",
+				Seasons:     "2023",
+				EpisodeType: FilterEpisodeTypeEpisode,
+			},
+			args:      args{&Release{TorrentName: "Show.Name.2023.05.12.1080p.WEB.h264-GROUP2"}},
+			wantMatch: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1837,6 +1886,7 @@ func TestFilter_CheckFilter1(t *testing.T) {
 				Shows:                tt.fields.Shows,
 				Seasons:              tt.fields.Seasons,
 				Episodes:             tt.fields.Episodes,
+				EpisodeType:          tt.fields.EpisodeType,
 				Resolutions:          tt.fields.Resolutions,
 				Codecs:               tt.fields.Codecs,
 				Sources:              tt.fields.Sources,
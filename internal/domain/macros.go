@@ -34,7 +34,11 @@ type Macro struct {
 	Size                uint64
 	Season              int
 	Episode             int
+	EpisodeEnd          int
 	Year                int
+	Month               int
+	Day                 int
+	SeasonPack          bool
 	CurrentYear         int
 	CurrentMonth        int
 	CurrentDay          int
@@ -66,7 +70,11 @@ func NewMacro(release Release) Macro {
 		Size:                release.Size,
 		Season:              release.Season,
 		Episode:             release.Episode,
+		EpisodeEnd:          release.EpisodeEnd,
 		Year:                release.Year,
+		Month:               release.Month,
+		Day:                 release.Day,
+		SeasonPack:          release.IsSeasonPack(),
 		CurrentYear:         currentTime.Year(),
 		CurrentMonth:        int(currentTime.Month()),
 		CurrentDay:          currentTime.Day(),
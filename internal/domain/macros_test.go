@@ -169,6 +169,28 @@ func TestMacros_Parse(t *testing.T) {
 			want:    "movies-2021",
 			wantErr: false,
 		},
+		{
+			name: "test_daily_air_date",
+			release: Release{
+				TorrentName: "Late.Show.2023.05.12.1080p.WEB.h264-GROUP",
+				Year:        2023,
+				Month:       5,
+				Day:         12,
+			},
+			args:    args{text: "daily/{{.Year}}-{{printf \"%02d\" .Month}}-{{printf \"%02d\" .Day}}"},
+			want:    "daily/2023-05-12",
+			wantErr: false,
+		},
+		{
+			name: "test_season_pack",
+			release: Release{
+				TorrentName: "That.Show.S02.1080p.WEB.h264-GROUP",
+				Season:      2,
+			},
+			args:    args{text: "{{if .SeasonPack}}packs{{else}}episodes{{end}}/S{{printf \"%02d\" .Season}}"},
+			want:    "packs/S02",
+			wantErr: false,
+		},
 		{
 			name: "test_size_formating",
 			release: Release{
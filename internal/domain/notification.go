@@ -4,8 +4,16 @@
 package domain
 
 import (
+	"bytes"
 	"context"
+	"strings"
+	"text/template"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/dustin/go-humanize"
 )
 
 type NotificationRepo interface {
@@ -23,26 +31,29 @@ type NotificationSender interface {
 }
 
 type Notification struct {
-	ID        int              `json:"id"`
-	Name      string           `json:"name"`
-	Type      NotificationType `json:"type"`
-	Enabled   bool             `json:"enabled"`
-	Events    []string         `json:"events"`
-	Token     string           `json:"token"`
-	APIKey    string           `json:"api_key"`
-	Webhook   string           `json:"webhook"`
-	Title     string           `json:"title"`
-	Icon      string           `json:"icon"`
-	Username  string           `json:"username"`
-	Host      string           `json:"host"`
-	Password  string           `json:"password"`
-	Channel   string           `json:"channel"`
-	Rooms     string           `json:"rooms"`
-	Targets   string           `json:"targets"`
-	Devices   string           `json:"devices"`
-	Priority  int32            `json:"priority"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID        int                   `json:"id"`
+	Name      string                `json:"name"`
+	Type      NotificationType      `json:"type"`
+	Enabled   bool                  `json:"enabled"`
+	Events    []string              `json:"events"`
+	Filters   []int                 `json:"filters"`
+	Indexers  []string              `json:"indexers"`
+	Token     string                `json:"token"`
+	APIKey    string                `json:"api_key"`
+	Webhook   string                `json:"webhook"`
+	Title     string                `json:"title"`
+	Icon      string                `json:"icon"`
+	Username  string                `json:"username"`
+	Host      string                `json:"host"`
+	Password  string                `json:"password"`
+	Channel   string                `json:"channel"`
+	Rooms     string                `json:"rooms"`
+	Targets   string                `json:"targets"`
+	Devices   string                `json:"devices"`
+	Priority  int32                 `json:"priority"`
+	Templates NotificationTemplates `json:"templates"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
 }
 
 type NotificationPayload struct {
@@ -51,6 +62,7 @@ type NotificationPayload struct {
 	Event          NotificationEvent
 	ReleaseName    string
 	Filter         string
+	FilterID       int
 	Indexer        string
 	InfoHash       string
 	Size           uint64
@@ -70,9 +82,11 @@ const (
 	NotificationTypeDiscord    NotificationType = "DISCORD"
 	NotificationTypeNotifiarr  NotificationType = "NOTIFIARR"
 	NotificationTypeIFTTT      NotificationType = "IFTTT"
+	NotificationTypeGotify     NotificationType = "GOTIFY"
 	NotificationTypeJoin       NotificationType = "JOIN"
 	NotificationTypeMattermost NotificationType = "MATTERMOST"
 	NotificationTypeMatrix     NotificationType = "MATRIX"
+	NotificationTypeNtfy       NotificationType = "NTFY"
 	NotificationTypePushBullet NotificationType = "PUSH_BULLET"
 	NotificationTypePushover   NotificationType = "PUSHOVER"
 	NotificationTypeRocketChat NotificationType = "ROCKETCHAT"
@@ -87,8 +101,13 @@ const (
 	NotificationEventPushApproved       NotificationEvent = "PUSH_APPROVED"
 	NotificationEventPushRejected       NotificationEvent = "PUSH_REJECTED"
 	NotificationEventPushError          NotificationEvent = "PUSH_ERROR"
+	NotificationEventPushRetryFailed    NotificationEvent = "PUSH_RETRY_FAILED"
 	NotificationEventIRCDisconnected    NotificationEvent = "IRC_DISCONNECTED"
 	NotificationEventIRCReconnected     NotificationEvent = "IRC_RECONNECTED"
+	NotificationEventIRCChannelQuiet    NotificationEvent = "IRC_CHANNEL_QUIET"
+	NotificationEventFeedFailed         NotificationEvent = "FEED_FAILED"
+	NotificationEventClientError        NotificationEvent = "DOWNLOAD_CLIENT_ERROR"
+	NotificationEventDailyDigest        NotificationEvent = "DAILY_DIGEST"
 	NotificationEventTest               NotificationEvent = "TEST"
 )
 
@@ -104,3 +123,128 @@ type NotificationQueryParams struct {
 	}
 	Search string
 }
+
+// InScope returns true if the payload is for one of the filters and indexers the notification is limited to.
+// The filter scope applies to release events and the indexer scope to every event with an indexer, like failed feeds.
+// Other events are always in scope.
+func (n Notification) InScope(payload NotificationPayload) bool {
+	if len(n.Filters) > 0 && payload.ReleaseName != "" {
+		found := false
+		for _, id := range n.Filters {
+			if id == payload.FilterID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(n.Indexers) > 0 && payload.Indexer != "" {
+		found := false
+		for _, indexer := range n.Indexers {
+			if strings.EqualFold(indexer, payload.Indexer) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NotificationTemplates holds the user defined templates by event
+type NotificationTemplates map[NotificationEvent]NotificationTemplate
+
+// Validate checks that all templates parse
+func (t NotificationTemplates) Validate() error {
+	for event, tmpl := range t {
+		if _, err := parseNotificationTemplate("title", tmpl.Title); err != nil {
+			return errors.Wrap(err, "invalid template for event: %v", event)
+		}
+		if _, err := parseNotificationTemplate("body", tmpl.Body); err != nil {
+			return errors.Wrap(err, "invalid template for event: %v", event)
+		}
+	}
+
+	return nil
+}
+
+// NotificationTemplate is a text/template for the title and body of an event.
+// Templates are executed with the NotificationPayload and have the sprig functions available.
+type NotificationTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// IsEmpty returns true if neither title nor body is set
+func (t NotificationTemplate) IsEmpty() bool {
+	return t.Title == "" && t.Body == ""
+}
+
+// notificationTemplateData is the payload with some preformatted values
+type notificationTemplateData struct {
+	NotificationPayload
+	SizeString string
+}
+
+// Render executes the title and body templates with the payload
+func (t NotificationTemplate) Render(payload NotificationPayload) (string, string, error) {
+	data := notificationTemplateData{
+		NotificationPayload: payload,
+		SizeString:          humanize.Bytes(payload.Size),
+	}
+
+	title, err := executeNotificationTemplate("title", t.Title, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	body, err := executeNotificationTemplate("body", t.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return title, body, nil
+}
+
+func executeNotificationTemplate(name string, text string, data notificationTemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := parseNotificationTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var tpl bytes.Buffer
+	if err := tmpl.Execute(&tpl, data); err != nil {
+		return "", errors.Wrap(err, "could not execute %v template", name)
+	}
+
+	return tpl.String(), nil
+}
+
+func parseNotificationTemplate(name string, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse %v template", name)
+	}
+
+	return tmpl, nil
+}
+
+// NotificationPreview is a rendered message for a sample payload
+type NotificationPreview struct {
+	Event NotificationEvent `json:"event"`
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Error string            `json:"error,omitempty"`
+}
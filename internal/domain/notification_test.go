@@ -0,0 +1,57 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_InScope(t *testing.T) {
+	release := NotificationPayload{
+		ReleaseName: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+		Filter:      "tv",
+		FilterID:    2,
+		Indexer:     "mock",
+	}
+
+	tests := []struct {
+		name         string
+		notification Notification
+		payload      NotificationPayload
+		want         bool
+	}{
+		{name: "no_scope", notification: Notification{}, payload: release, want: true},
+		{name: "filter_match", notification: Notification{Filters: []int{1, 2}}, payload: release, want: true},
+		{name: "filter_no_match", notification: Notification{Filters: []int{1, 3}}, payload: release, want: false},
+		{name: "indexer_match", notification: Notification{Indexers: []string{"MOCK"}}, payload: release, want: true},
+		{name: "indexer_no_match", notification: Notification{Indexers: []string{"other"}}, payload: release, want: false},
+		{name: "filter_and_indexer_match", notification: Notification{Filters: []int{2}, Indexers: []string{"mock"}}, payload: release, want: true},
+		{name: "filter_match_indexer_no_match", notification: Notification{Filters: []int{2}, Indexers: []string{"other"}}, payload: release, want: false},
+		{
+			name:         "no_release",
+			notification: Notification{Filters: []int{1}, Indexers: []string{"other"}},
+			payload:      NotificationPayload{Subject: "IRC Disconnected unexpectedly", Message: "Network: P2P-Network"},
+			want:         true,
+		},
+		{
+			name:         "no_release_indexer_match",
+			notification: Notification{Filters: []int{1}, Indexers: []string{"mock"}},
+			payload:      NotificationPayload{Subject: "Feed failed", Indexer: "mock"},
+			want:         true,
+		},
+		{
+			name:         "no_release_indexer_no_match",
+			notification: Notification{Indexers: []string{"other"}},
+			payload:      NotificationPayload{Subject: "Feed failed", Indexer: "mock"},
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.notification.InScope(tt.payload))
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 type ReleaseRepo interface {
 	Store(ctx context.Context, release *Release) (*Release, error)
+	FindByID(ctx context.Context, id int64) (*Release, error)
 	Find(ctx context.Context, params ReleaseQueryParams) (res []*Release, nextCursor int64, count int64, err error)
 	FindRecent(ctx context.Context) ([]*Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
@@ -35,7 +36,18 @@ type ReleaseRepo interface {
 	Stats(ctx context.Context) (*ReleaseStats, error)
 	StoreReleaseActionStatus(ctx context.Context, status *ReleaseActionStatus) error
 	Delete(ctx context.Context) error
-	CanDownloadShow(ctx context.Context, title string, season int, episode int) (bool, error)
+	Prune(ctx context.Context, params ReleasePruneParams) (int64, error)
+	CanDownloadShow(ctx context.Context, release *Release) (bool, error)
+	FindSameEpisode(ctx context.Context, release *Release) ([]*Release, error)
+	StoreDelay(ctx context.Context, delay *ReleaseDelay) error
+	FindDelays(ctx context.Context) ([]ReleaseDelay, error)
+	DeleteDelay(ctx context.Context, id int64) (bool, error)
+	FindDuplicates(ctx context.Context, params ReleaseDuplicateParams) ([]*Release, error)
+	FindGrabbedSince(ctx context.Context, since time.Time) ([]*Release, error)
+	StoreRetry(ctx context.Context, retry *ReleaseActionRetry) error
+	FindRetries(ctx context.Context) ([]ReleaseActionRetry, error)
+	FindRetryByActionStatusID(ctx context.Context, actionStatusID int64) (*ReleaseActionRetry, error)
+	DeleteRetry(ctx context.Context, id int64) (bool, error)
 }
 
 type Release struct {
@@ -62,7 +74,10 @@ type Release struct {
 	Categories                  []string              `json:"categories,omitempty"`
 	Season                      int                   `json:"season"`
 	Episode                     int                   `json:"episode"`
+	EpisodeEnd                  int                   `json:"episode_end,omitempty"` // last episode of multi-episode releases
 	Year                        int                   `json:"year"`
+	Month                       int                   `json:"month,omitempty"` // air date of daily shows
+	Day                         int                   `json:"day,omitempty"`
 	Resolution                  string                `json:"resolution"`
 	Source                      string                `json:"source"`
 	Codec                       []string              `json:"codec"`
@@ -108,6 +123,35 @@ type ReleaseActionStatus struct {
 	ReleaseID  int64             `json:"-"`
 }
 
+// ReleaseDelay is a release waiting for the filter delay to pass before its actions are run
+type ReleaseDelay struct {
+	ID          int64     `json:"id"`
+	ReleaseID   int64     `json:"release_id"`
+	FilterID    int       `json:"filter_id"`
+	Filter      string    `json:"filter"`
+	Indexer     string    `json:"indexer"`
+	TorrentName string    `json:"torrent_name"`
+	RunAt       time.Time `json:"run_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReleaseActionRetry is a failed action push waiting to be retried
+type ReleaseActionRetry struct {
+	ID             int64     `json:"id"`
+	ReleaseID      int64     `json:"release_id"`
+	ActionStatusID int64     `json:"action_status_id"`
+	FilterID       int       `json:"filter_id"`
+	ActionID       int       `json:"action_id"`
+	Action         string    `json:"action"`
+	Filter         string    `json:"filter"`
+	Indexer        string    `json:"indexer"`
+	TorrentName    string    `json:"torrent_name"`
+	Attempts       int       `json:"attempts"`
+	NextRunAt      time.Time `json:"next_run_at"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 func NewReleaseActionStatus(action *Action, release *Release) *ReleaseActionStatus {
 	s := &ReleaseActionStatus{
 		ID:         0,
@@ -168,8 +212,7 @@ type ReleaseFilterStatus string
 const (
 	ReleaseStatusFilterApproved ReleaseFilterStatus = "FILTER_APPROVED"
 	ReleaseStatusFilterPending  ReleaseFilterStatus = "PENDING"
-
-	//ReleaseStatusFilterRejected ReleaseFilterStatus = "FILTER_REJECTED"
+	ReleaseStatusFilterRejected ReleaseFilterStatus = "FILTER_REJECTED"
 )
 
 type ReleaseProtocol string
@@ -226,6 +269,90 @@ type ReleaseQueryParams struct {
 	Search string
 }
 
+// ReleaseDuplicateParams finds already grabbed releases that might be duplicates of the release
+type ReleaseDuplicateParams struct {
+	Release      *Release
+	MatchEpisode bool
+	// Indexer limits duplicates to releases from this indexer
+	Indexer string
+	// Since limits duplicates to releases grabbed after, zero for no limit
+	Since time.Time
+}
+
+// ReleasePruneStatusOrder ranks action results, a release has the status of its best result
+// so a release approved by one action and rejected by another is kept as approved.
+var ReleasePruneStatusOrder = []ReleasePushStatus{
+	ReleasePushStatusApproved,
+	ReleasePushStatusErr,
+	ReleasePushStatusRejected,
+	ReleasePushStatusPending,
+}
+
+// ReleasePruneRule deletes releases older than Days. Statuses and indexers limit the rule when set.
+// Statuses are the push statuses of ReleasePruneStatusOrder and FILTER_REJECTED.
+type ReleasePruneRule struct {
+	Days     int      `json:"days" toml:"days"`
+	Statuses []string `json:"statuses,omitempty" toml:"statuses"`
+	Indexers []string `json:"indexers,omitempty" toml:"indexers"`
+	// All confirms a manual prune without days, which deletes the matching releases of any age
+	All bool `json:"all,omitempty" toml:"-"`
+}
+
+func (r ReleasePruneRule) Validate() error {
+	if r.Days < 0 {
+		return errors.New("validation error: days can not be negative")
+	}
+
+	for _, s := range r.Statuses {
+		if !isReleasePruneStatus(s) {
+			return errors.New("validation error: invalid status: %q", s)
+		}
+	}
+
+	return nil
+}
+
+// Params turns the rule into repo params relative to now
+func (r ReleasePruneRule) Params(now time.Time) ReleasePruneParams {
+	return ReleasePruneParams{
+		Before:   now.AddDate(0, 0, -r.Days),
+		Statuses: r.Statuses,
+		Indexers: r.Indexers,
+	}
+}
+
+func (r ReleasePruneRule) String() string {
+	s := fmt.Sprintf("older than %d days", r.Days)
+	if len(r.Statuses) > 0 {
+		s += fmt.Sprintf(" with status %s", strings.Join(r.Statuses, ", "))
+	}
+	if len(r.Indexers) > 0 {
+		s += fmt.Sprintf(" from %s", strings.Join(r.Indexers, ", "))
+	}
+
+	return s
+}
+
+type ReleasePruneParams struct {
+	Before   time.Time
+	Statuses []string
+	Indexers []string
+}
+
+func isReleasePruneStatus(status string) bool {
+	if status == string(ReleaseStatusFilterRejected) {
+		return true
+	}
+
+	for _, s := range ReleasePruneStatusOrder {
+		if status == string(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func NewRelease(indexer string) *Release {
 	r := &Release{
 		Indexer:        indexer,
@@ -272,15 +399,82 @@ func (r *Release) ParseString(title string) {
 		r.Year = rel.Year
 	}
 
+	if r.Month == 0 && r.Day == 0 {
+		r.Month = rel.Month
+		r.Day = rel.Day
+	}
+
 	if r.Group == "" {
 		r.Group = rel.Group
 	}
 
+	r.parseEpisodeNumbering(title)
+
 	r.ParseReleaseTagsString(r.ReleaseTags)
 }
 
+var (
+	multiEpisodeRegex    = regexp.MustCompile(`(?i)\bS\d{1,4}E\d{1,4}((?:-?E\d{1,4}|-\d{1,4})+)\b`)
+	absoluteEpisodeRegex = regexp.MustCompile(`^\[[^\]]+\]\s*.+?\s-\s(\d{1,4})(?:v\d)?(?:[\s.]|$)`)
+	episodeNumberRegex   = regexp.MustCompile(`\d+`)
+)
+
+// parseEpisodeNumbering parses the last episode of multi-episode releases like S01E01-E03,
+// and absolute episode numbers of anime releases like [Group] Show - 1071 (1080p)
+func (r *Release) parseEpisodeNumbering(title string) {
+	if r.EpisodeEnd == 0 && r.Episode > 0 {
+		if m := multiEpisodeRegex.FindStringSubmatch(title); m != nil {
+			numbers := episodeNumberRegex.FindAllString(m[1], -1)
+
+			if end, err := strconv.Atoi(numbers[len(numbers)-1]); err == nil && end > r.Episode {
+				r.EpisodeEnd = end
+			}
+		}
+	}
+
+	if r.Season == 0 && r.Episode == 0 {
+		if m := absoluteEpisodeRegex.FindStringSubmatch(title); m != nil {
+			if episode, err := strconv.Atoi(m[1]); err == nil {
+				r.Episode = episode
+			}
+		}
+	}
+}
+
+// IsSeasonPack checks if the release is a full season without an episode
+func (r *Release) IsSeasonPack() bool {
+	return r.Season > 0 && r.Episode == 0
+}
+
+// IsDaily checks if the release is an episode of a daily show numbered by air date
+func (r *Release) IsDaily() bool {
+	return r.Season == 0 && r.Episode == 0 && r.Year > 0 && r.Month > 0 && r.Day > 0
+}
+
+// IsAbsoluteEpisode checks if the release is numbered by absolute episode without a season, like most anime
+func (r *Release) IsAbsoluteEpisode() bool {
+	return r.Season == 0 && r.Episode > 0
+}
+
+// Episodes returns all episodes of the release, or nil for season packs and daily shows
+func (r *Release) Episodes() []int {
+	if r.Episode == 0 {
+		return nil
+	}
+
+	episodes := []int{r.Episode}
+	for e := r.Episode + 1; e <= r.EpisodeEnd; e++ {
+		episodes = append(episodes, e)
+	}
+
+	return episodes
+}
+
 var ErrUnrecoverableError = errors.New("unrecoverable error")
 
+// ErrRecordNotFound is wrapped by repos when the requested record does not exist
+var ErrRecordNotFound = errors.Sentinel("record not found")
+
 func (r *Release) ParseReleaseTagsString(tags string) {
 	// trim delimiters and closest space
 	re := regexp.MustCompile(`\| |/ |, `)
@@ -549,6 +743,104 @@ func (r *Release) resetRejections() {
 	r.Rejections = []string{}
 }
 
+// IsDuplicateOf checks if the release has the same normalized name as the other release, or when matchEpisode is set,
+// the same title, season, episode, resolution and source
+func (r *Release) IsDuplicateOf(other *Release, matchEpisode bool) bool {
+	if NormalizeReleaseName(r.TorrentName) == NormalizeReleaseName(other.TorrentName) {
+		return true
+	}
+
+	if !matchEpisode || r.Title == "" || (r.Season == 0 && r.Episode == 0) {
+		return false
+	}
+
+	return strings.EqualFold(r.Title, other.Title) &&
+		r.Season == other.Season &&
+		r.Episode == other.Episode &&
+		strings.EqualFold(r.Resolution, other.Resolution) &&
+		strings.EqualFold(r.Source, other.Source)
+}
+
+// DupeKey returns a key shared by releases that can be duplicates of each other, to serialize their duplicate checks.
+// Releases with the same normalized name always parse to the same episode, so both cases share the key.
+func (r *Release) DupeKey(matchEpisode bool) string {
+	if !matchEpisode || r.Title == "" || (r.Season == 0 && r.Episode == 0) {
+		return NormalizeReleaseName(r.TorrentName)
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s|%d|%d|%s|%s", r.Title, r.Season, r.Episode, r.Resolution, r.Source))
+}
+
+// NormalizeReleaseName lowercases the name and unifies separators so the same release announced differently compares equal
+func NormalizeReleaseName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer(" ", ".", "_", ".").Replace(name)
+
+	for strings.Contains(name, "..") {
+		name = strings.ReplaceAll(name, "..", ".")
+	}
+
+	return name
+}
+
+// releaseResolutionRank ranks resolutions from worst to best for smart episode upgrades
+var releaseResolutionRank = map[string]int{
+	"480i":  1,
+	"480p":  2,
+	"576p":  3,
+	"720p":  4,
+	"810p":  5,
+	"1080i": 6,
+	"1080p": 7,
+	"2160p": 8,
+}
+
+// releaseSourceRank ranks sources from worst to best for smart episode upgrades
+var releaseSourceRank = map[string]int{
+	"cam":        1,
+	"hdcam":      1,
+	"hdts":       1,
+	"dvdscr":     2,
+	"dvdr":       3,
+	"dvdrip":     3,
+	"hdtv":       4,
+	"siterip":    4,
+	"webrip":     5,
+	"web":        6,
+	"web-dl":     6,
+	"hddvd":      7,
+	"hddvdrip":   7,
+	"bdrip":      7,
+	"brrip":      7,
+	"bluray":     8,
+	"uhd.bluray": 9,
+}
+
+// QualityRank ranks the release by resolution and then source. Unknown values rank lowest.
+func (r *Release) QualityRank() int {
+	return releaseResolutionRank[strings.ToLower(r.Resolution)]*10 + releaseSourceRank[strings.ToLower(r.Source)]
+}
+
+// IsSameQuality checks if the release has the same resolution and source as the other release
+func (r *Release) IsSameQuality(other *Release) bool {
+	return strings.EqualFold(r.Resolution, other.Resolution) && strings.EqualFold(r.Source, other.Source)
+}
+
+// IsUpgradeOf checks if the release is a proper or repack of the same quality as the other release,
+// or when rankQuality is set, a better resolution or source
+func (r *Release) IsUpgradeOf(other *Release, rankQuality bool) bool {
+	if rankQuality {
+		rank, otherRank := r.QualityRank(), other.QualityRank()
+		if rank != otherRank {
+			return rank > otherRank
+		}
+	} else if !r.IsSameQuality(other) {
+		return false
+	}
+
+	return (r.Proper || r.Repack) && !(other.Proper || other.Repack)
+}
+
 func (r *Release) RejectionsString() string {
 	if len(r.Rejections) > 0 {
 		return strings.Join(r.Rejections, ", ")
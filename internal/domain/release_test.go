@@ -911,3 +911,287 @@ func Test_getUniqueTags(t *testing.T) {
 		})
 	}
 }
+
+func TestRelease_IsDuplicateOf(t *testing.T) {
+	type args struct {
+		other        *Release
+		matchEpisode bool
+	}
+	tests := []struct {
+		name    string
+		release *Release
+		args    args
+		want    bool
+	}{
+		{
+			name:    "same_name",
+			release: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP"},
+			args:    args{other: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP"}},
+			want:    true,
+		},
+		{
+			name:    "same_name_different_separators",
+			release: &Release{TorrentName: "That Show S01E01 1080p WEB H264-GROUP"},
+			args:    args{other: &Release{TorrentName: "that.show.s01e01.1080p.web.h264-group"}},
+			want:    true,
+		},
+		{
+			name:    "different_group",
+			release: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"},
+			args:    args{other: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-OTHER", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"}},
+			want:    false,
+		},
+		{
+			name:    "match_episode",
+			release: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"},
+			args:    args{other: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-OTHER", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"}, matchEpisode: true},
+			want:    true,
+		},
+		{
+			name:    "match_episode_different_resolution",
+			release: &Release{TorrentName: "That.Show.S01E01.2160p.WEB.H265-GROUP", Title: "That Show", Season: 1, Episode: 1, Resolution: "2160p", Source: "WEB"},
+			args:    args{other: &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-OTHER", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"}, matchEpisode: true},
+			want:    false,
+		},
+		{
+			name:    "match_episode_movie",
+			release: &Release{TorrentName: "That.Movie.2020.1080p.BluRay.x264-GROUP", Title: "That Movie", Year: 2020, Resolution: "1080p", Source: "BluRay"},
+			args:    args{other: &Release{TorrentName: "That.Movie.2020.1080p.BluRay.x264-OTHER", Title: "That Movie", Year: 2020, Resolution: "1080p", Source: "BluRay"}, matchEpisode: true},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.release.IsDuplicateOf(tt.args.other, tt.args.matchEpisode))
+		})
+	}
+}
+
+func TestRelease_DupeKey(t *testing.T) {
+	a := &Release{TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"}
+	b := &Release{TorrentName: "that show s01e01 1080p web h264-other", Title: "that show", Season: 1, Episode: 1, Resolution: "1080p", Source: "web"}
+	c := &Release{TorrentName: "That Show S01E01 1080p WEB H264-GROUP", Title: "That Show", Season: 1, Episode: 1, Resolution: "1080p", Source: "WEB"}
+
+	assert.NotEqual(t, a.DupeKey(false), b.DupeKey(false))
+	assert.Equal(t, a.DupeKey(false), c.DupeKey(false))
+	assert.Equal(t, a.DupeKey(true), b.DupeKey(true))
+
+	movie := &Release{TorrentName: "That.Movie.2020.1080p.BluRay.x264-GROUP", Title: "That Movie", Year: 2020}
+	assert.Equal(t, NormalizeReleaseName(movie.TorrentName), movie.DupeKey(true))
+}
+
+func TestRelease_IsSameQuality(t *testing.T) {
+	release := &Release{Resolution: "1080p", Source: "WEB"}
+
+	assert.True(t, release.IsSameQuality(&Release{Resolution: "1080P", Source: "web"}))
+	assert.False(t, release.IsSameQuality(&Release{Resolution: "2160p", Source: "WEB"}))
+	assert.False(t, release.IsSameQuality(&Release{Resolution: "1080p", Source: "BluRay"}))
+}
+
+func TestRelease_IsUpgradeOf(t *testing.T) {
+	type args struct {
+		other       *Release
+		rankQuality bool
+	}
+	tests := []struct {
+		name    string
+		release *Release
+		args    args
+		want    bool
+	}{
+		{
+			name:    "same_quality",
+			release: &Release{Resolution: "1080p", Source: "WEB"},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}},
+			want:    false,
+		},
+		{
+			name:    "proper",
+			release: &Release{Resolution: "1080p", Source: "WEB", Proper: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}},
+			want:    true,
+		},
+		{
+			name:    "repack",
+			release: &Release{Resolution: "1080p", Source: "WEB", Repack: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}},
+			want:    true,
+		},
+		{
+			name:    "repack_of_proper",
+			release: &Release{Resolution: "1080p", Source: "WEB", Repack: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB", Proper: true}},
+			want:    false,
+		},
+		{
+			name:    "proper_different_quality",
+			release: &Release{Resolution: "720p", Source: "HDTV", Proper: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}},
+			want:    false,
+		},
+		{
+			name:    "better_resolution_without_ranking",
+			release: &Release{Resolution: "2160p", Source: "WEB"},
+			args:    args{other: &Release{Resolution: "720p", Source: "WEB"}},
+			want:    false,
+		},
+		{
+			name:    "better_resolution",
+			release: &Release{Resolution: "2160p", Source: "WEB"},
+			args:    args{other: &Release{Resolution: "720p", Source: "WEB"}, rankQuality: true},
+			want:    true,
+		},
+		{
+			name:    "better_source",
+			release: &Release{Resolution: "1080p", Source: "BluRay"},
+			args:    args{other: &Release{Resolution: "1080p", Source: "HDTV"}, rankQuality: true},
+			want:    true,
+		},
+		{
+			name:    "worse_resolution_proper",
+			release: &Release{Resolution: "720p", Source: "WEB", Proper: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}, rankQuality: true},
+			want:    false,
+		},
+		{
+			name:    "same_rank_proper",
+			release: &Release{Resolution: "1080p", Source: "WEB-DL", Proper: true},
+			args:    args{other: &Release{Resolution: "1080p", Source: "WEB"}, rankQuality: true},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.release.IsUpgradeOf(tt.args.other, tt.args.rankQuality))
+		})
+	}
+}
+
+func TestRelease_parseEpisodeNumbering(t *testing.T) {
+	tests := []struct {
+		name           string
+		release        Release
+		title          string
+		wantEpisode    int
+		wantEpisodeEnd int
+	}{
+		{
+			name:           "single_episode",
+			release:        Release{Season: 1, Episode: 1},
+			title:          "That.Show.S01E01.1080p.WEB.h264-GROUP",
+			wantEpisode:    1,
+			wantEpisodeEnd: 0,
+		},
+		{
+			name:           "multi_episode_dash",
+			release:        Release{Season: 1, Episode: 1},
+			title:          "That.Show.S01E01-E03.1080p.WEB.h264-GROUP",
+			wantEpisode:    1,
+			wantEpisodeEnd: 3,
+		},
+		{
+			name:           "multi_episode_joined",
+			release:        Release{Season: 1, Episode: 1},
+			title:          "That.Show.S01E01E02.1080p.WEB.h264-GROUP",
+			wantEpisode:    1,
+			wantEpisodeEnd: 2,
+		},
+		{
+			name:           "multi_episode_short",
+			release:        Release{Season: 1, Episode: 5},
+			title:          "That Show S01E05-06 1080p WEB h264-GROUP",
+			wantEpisode:    5,
+			wantEpisodeEnd: 6,
+		},
+		{
+			name:           "episode_before_resolution",
+			release:        Release{Season: 1, Episode: 1},
+			title:          "That.Show.S01E01-1080p.WEB.h264-GROUP",
+			wantEpisode:    1,
+			wantEpisodeEnd: 0,
+		},
+		{
+			name:           "absolute_episode",
+			release:        Release{},
+			title:          "[SubsPlease] One Piece - 1071 (1080p) [ABCD1234]",
+			wantEpisode:    1071,
+			wantEpisodeEnd: 0,
+		},
+		{
+			name:           "absolute_episode_version",
+			release:        Release{},
+			title:          "[Group] That Anime - 04v2 [1080p]",
+			wantEpisode:    4,
+			wantEpisodeEnd: 0,
+		},
+		{
+			name:           "music",
+			release:        Release{},
+			title:          "Artist - 1999 (FLAC)",
+			wantEpisode:    0,
+			wantEpisodeEnd: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.release
+			r.parseEpisodeNumbering(tt.title)
+
+			assert.Equal(t, tt.wantEpisode, r.Episode)
+			assert.Equal(t, tt.wantEpisodeEnd, r.EpisodeEnd)
+		})
+	}
+}
+
+func TestRelease_Episodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		release Release
+		want    []int
+	}{
+		{name: "season_pack", release: Release{Season: 1}, want: nil},
+		{name: "daily", release: Release{Year: 2023, Month: 5, Day: 12}, want: nil},
+		{name: "single", release: Release{Season: 1, Episode: 4}, want: []int{4}},
+		{name: "multi", release: Release{Season: 1, Episode: 4, EpisodeEnd: 6}, want: []int{4, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.release.Episodes())
+		})
+	}
+}
+
+func TestReleasePruneRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ReleasePruneRule
+		wantErr bool
+	}{
+		{name: "days", rule: ReleasePruneRule{Days: 30}},
+		{name: "everything", rule: ReleasePruneRule{}},
+		{name: "statuses", rule: ReleasePruneRule{Days: 7, Statuses: []string{"PUSH_REJECTED", "FILTER_REJECTED", "PENDING"}}},
+		{name: "negative_days", rule: ReleasePruneRule{Days: -1}, wantErr: true},
+		{name: "invalid_status", rule: ReleasePruneRule{Days: 7, Statuses: []string{"FILTER_APPROVED"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReleasePruneRule_Params(t *testing.T) {
+	now := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	rule := ReleasePruneRule{Days: 7, Statuses: []string{"PUSH_REJECTED"}, Indexers: []string{"mock"}}
+
+	params := rule.Params(now)
+	assert.Equal(t, time.Date(2023, 6, 8, 12, 0, 0, 0, time.UTC), params.Before)
+	assert.Equal(t, []string{"PUSH_REJECTED"}, params.Statuses)
+	assert.Equal(t, []string{"mock"}, params.Indexers)
+	assert.Equal(t, "older than 7 days with status PUSH_REJECTED from mock", rule.String())
+}
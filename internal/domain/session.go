@@ -0,0 +1,30 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type SessionRepo interface {
+	Store(ctx context.Context, session *Session) error
+	FindByID(ctx context.Context, id string) (*Session, error)
+	ListByUser(ctx context.Context, userID int) ([]Session, error)
+	Touch(ctx context.Context, id string, lastSeen time.Time) error
+	Delete(ctx context.Context, id string) error
+	DeleteByUser(ctx context.Context, userID int, except string) error
+	DeleteExpired(ctx context.Context, lastSeenBefore time.Time) error
+}
+
+// Session is a logged in web ui session. The id is stored in the signed session cookie.
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    int       `json:"user_id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
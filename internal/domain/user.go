@@ -3,22 +3,100 @@
 
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type UserRepo interface {
 	GetUserCount(ctx context.Context) (int, error)
 	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindByID(ctx context.Context, id int) (*User, error)
+	FindByOIDCSubject(ctx context.Context, subject string) (*User, error)
+	List(ctx context.Context) ([]User, error)
 	Store(ctx context.Context, req CreateUserRequest) error
 	Update(ctx context.Context, user User) error
+	Delete(ctx context.Context, id int) error
 }
 
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	ID               int       `json:"id"`
+	Username         string    `json:"username"`
+	Password         string    `json:"password,omitempty"`
+	Role             UserRole  `json:"role"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	TwoFactorSecret  string    `json:"-"`
+	RecoveryCodes    []string  `json:"-"`
+	OIDCSubject      string    `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type UserRole string
+
+const (
+	// UserRoleAdmin has full access, including users, api keys and config
+	UserRoleAdmin UserRole = "admin"
+	// UserRoleEditor can change everything except users, api keys and config
+	UserRoleEditor UserRole = "editor"
+	// UserRoleViewer has read only access, except to resources exposing credentials
+	UserRoleViewer UserRole = "viewer"
+)
+
+func (r UserRole) IsValid() bool {
+	switch r {
+	case UserRoleAdmin, UserRoleEditor, UserRoleViewer:
+		return true
+	}
+	return false
+}
+
+// Rank orders the roles by how much access they grant
+func (r UserRole) Rank() int {
+	switch r {
+	case UserRoleAdmin:
+		return 3
+	case UserRoleEditor:
+		return 2
+	case UserRoleViewer:
+		return 1
+	}
+	return 0
+}
+
+// CanAccess checks if the role grants access to the resource, using the same resources as the api key scopes
+func (r UserRole) CanAccess(resource string, write bool) bool {
+	switch r {
+	case UserRoleAdmin:
+		return true
+	case UserRoleEditor:
+		if resource == APIKeyScopeAdmin {
+			return false
+		}
+		return !write || resource != "config"
+	case UserRoleViewer:
+		return resource != APIKeyScopeAdmin && !write && !IsSensitiveResource(resource)
+	}
+	return false
 }
 
 type CreateUserRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	Role        UserRole `json:"role"`
+	OIDCSubject string   `json:"-"`
+}
+
+type UpdateUserRequest struct {
+	ID       int      `json:"id"`
+	Password string   `json:"password"`
+	Role     UserRole `json:"role"`
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+type TwoFactorRequest struct {
+	Code string `json:"code"`
 }
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRole_CanAccess(t *testing.T) {
+	tests := []struct {
+		role     UserRole
+		resource string
+		write    bool
+		want     bool
+	}{
+		{role: UserRoleAdmin, resource: APIKeyScopeAdmin, write: true, want: true},
+		{role: UserRoleEditor, resource: "filters", write: true, want: true},
+		{role: UserRoleEditor, resource: "config", write: false, want: true},
+		{role: UserRoleEditor, resource: "config", write: true, want: false},
+		{role: UserRoleEditor, resource: APIKeyScopeAdmin, write: false, want: false},
+		{role: UserRoleViewer, resource: "releases", write: false, want: true},
+		{role: UserRoleViewer, resource: "releases", write: true, want: false},
+		{role: UserRoleViewer, resource: APIKeyScopeAdmin, write: false, want: false},
+		{role: UserRoleViewer, resource: "filters", write: false, want: true},
+		{role: UserRoleViewer, resource: "download_clients", write: false, want: false},
+		{role: UserRoleViewer, resource: "notifications", write: false, want: false},
+		{role: UserRoleViewer, resource: "config", write: false, want: false},
+		{role: UserRoleEditor, resource: "download_clients", write: false, want: true},
+		{role: "unknown", resource: "releases", write: false, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"_"+tt.resource, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.CanAccess(tt.resource, tt.write))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
+
+	"github.com/asaskevich/EventBus"
+)
+
+// feedHealth counts consecutive failed runs of a feed job
+type feedHealth struct {
+	bus       EventBus.Bus
+	threshold int
+	failures  int
+}
+
+func newFeedHealth(bus EventBus.Bus, threshold int) *feedHealth {
+	return &feedHealth{
+		bus:       bus,
+		threshold: threshold,
+	}
+}
+
+// failed counts a failed run, and sends the feed failed notification when the failures reach the threshold
+func (h *feedHealth) failed(feed *domain.Feed, err error) {
+	if h == nil {
+		return
+	}
+
+	h.failures++
+
+	// only notify once until the feed recovers
+	if h.bus == nil || h.threshold <= 0 || h.failures != h.threshold {
+		return
+	}
+
+	payload := &domain.NotificationPayload{
+		Subject:   "Feed failed",
+		Message:   fmt.Sprintf("Feed: %v failed %d times in a row: %v", feed.Name, h.failures, err),
+		Event:     domain.NotificationEventFeedFailed,
+		Indexer:   feed.Indexer,
+		Timestamp: time.Now(),
+	}
+
+	h.bus.Publish("events:notification", &payload.Event, payload)
+}
+
+// succeeded resets the failures after a successful run
+func (h *feedHealth) succeeded() {
+	if h == nil {
+		return
+	}
+
+	h.failures = 0
+}
+
+// observeFeedRun records the duration of a feed job run, and counts it when it failed
+func observeFeedRun(feed *domain.Feed, start time.Time, err error) {
+	if feed == nil {
+		return
+	}
+
+	metrics.FeedRunDuration.WithLabelValues(feed.Name, feed.Type).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.FeedRunErrorsTotal.WithLabelValues(feed.Name, feed.Type).Inc()
+	}
+}
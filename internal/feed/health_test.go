@@ -0,0 +1,54 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package feed
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_feedHealth(t *testing.T) {
+	bus := EventBus.New()
+
+	var sent []domain.NotificationPayload
+	err := bus.Subscribe("events:notification", func(event *domain.NotificationEvent, payload *domain.NotificationPayload) {
+		sent = append(sent, *payload)
+	})
+	assert.NoError(t, err)
+
+	feed := &domain.Feed{Name: "Mock Feed", Indexer: "mock-feed"}
+	h := newFeedHealth(bus, 3)
+
+	h.failed(feed, errors.New("timeout"))
+	h.failed(feed, errors.New("timeout"))
+	assert.Len(t, sent, 0)
+
+	h.failed(feed, errors.New("timeout"))
+	assert.Len(t, sent, 1)
+	assert.Equal(t, domain.NotificationEventFeedFailed, sent[0].Event)
+	assert.Equal(t, "mock-feed", sent[0].Indexer)
+
+	// keeps failing, only notify once
+	h.failed(feed, errors.New("timeout"))
+	assert.Len(t, sent, 1)
+
+	// recovers and fails again
+	h.succeeded()
+	for i := 0; i < 3; i++ {
+		h.failed(feed, errors.New("timeout"))
+	}
+	assert.Len(t, sent, 2)
+}
+
+func Test_feedHealth_nil(t *testing.T) {
+	var h *feedHealth
+
+	h.failed(&domain.Feed{}, errors.New("timeout"))
+	h.succeeded()
+}
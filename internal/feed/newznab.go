@@ -32,6 +32,7 @@ type NewznabJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
@@ -52,11 +53,18 @@ func NewNewznabJob(feed *domain.Feed, name string, indexerIdentifier string, log
 
 func (j *NewznabJob) Run() {
 	ctx := context.Background()
+	start := time.Now()
 
-	if err := j.process(ctx); err != nil {
+	err := j.process(ctx)
+	observeFeedRun(j.Feed, start, err)
+
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("newznab process error")
 
 		j.errors = append(j.errors, err)
+		j.health.failed(j.Feed, err)
+	} else {
+		j.health.succeeded()
 	}
 
 	j.attempts = 0
@@ -32,6 +32,7 @@ type RSSJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
@@ -52,16 +53,22 @@ func NewRSSJob(feed *domain.Feed, name string, indexerIdentifier string, log zer
 
 func (j *RSSJob) Run() {
 	ctx := context.Background()
+	start := time.Now()
 
-	if err := j.process(ctx); err != nil {
+	err := j.process(ctx)
+	observeFeedRun(j.Feed, start, err)
+
+	if err != nil {
 		j.Log.Error().Err(err).Int("attempts", j.attempts).Msg("rss feed process error")
 
 		j.errors = append(j.errors, err)
+		j.health.failed(j.Feed, err)
 		return
 	}
 
 	j.attempts = 0
 	j.errors = []error{}
+	j.health.succeeded()
 }
 
 func (j *RSSJob) process(ctx context.Context) error {
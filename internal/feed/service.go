@@ -18,6 +18,7 @@ import (
 	"github.com/autobrr/autobrr/pkg/newznab"
 	"github.com/autobrr/autobrr/pkg/torznab"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/dcarbone/zadapters/zstdlog"
 	"github.com/mmcdole/gofeed"
 	"github.com/rs/zerolog"
@@ -60,23 +61,27 @@ func (k feedKey) ToString() string {
 }
 
 type service struct {
-	log  zerolog.Logger
-	jobs map[string]int
+	log    zerolog.Logger
+	config *domain.Config
+	jobs   map[string]int
 
 	repo       domain.FeedRepo
 	cacheRepo  domain.FeedCacheRepo
 	releaseSvc release.Service
 	scheduler  scheduler.Service
+	bus        EventBus.Bus
 }
 
-func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service, bus EventBus.Bus) Service {
 	return &service{
 		log:        log.With().Str("module", "feed").Logger(),
+		config:     config,
 		jobs:       map[string]int{},
 		repo:       repo,
 		cacheRepo:  cacheRepo,
 		releaseSvc: releaseSvc,
 		scheduler:  scheduler,
+		bus:        bus,
 	}
 }
 
@@ -398,6 +403,7 @@ func (s *service) addTorznabJob(f feedInstance) error {
 
 	// create job
 	job := NewTorznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, c, s.repo, s.cacheRepo, s.releaseSvc)
+	job.health = newFeedHealth(s.bus, s.config.FeedFailureThreshold)
 
 	identifierKey := feedKey{f.Feed.ID, f.Feed.Indexer, f.Feed.Name}.ToString()
 
@@ -429,6 +435,7 @@ func (s *service) addNewznabJob(f feedInstance) error {
 
 	// create job
 	job := NewNewznabJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, c, s.repo, s.cacheRepo, s.releaseSvc)
+	job.health = newFeedHealth(s.bus, s.config.FeedFailureThreshold)
 
 	identifierKey := feedKey{f.Feed.ID, f.Feed.Indexer, f.Feed.Name}.ToString()
 
@@ -461,6 +468,7 @@ func (s *service) addRSSJob(f feedInstance) error {
 
 	// create job
 	job := NewRSSJob(f.Feed, f.Name, f.IndexerIdentifier, l, f.URL, s.repo, s.cacheRepo, s.releaseSvc, f.Timeout)
+	job.health = newFeedHealth(s.bus, s.config.FeedFailureThreshold)
 
 	identifierKey := feedKey{f.Feed.ID, f.Feed.Indexer, f.Feed.Name}.ToString()
 
@@ -33,6 +33,7 @@ type TorznabJob struct {
 
 	attempts int
 	errors   []error
+	health   *feedHealth
 
 	JobID int
 }
@@ -53,11 +54,18 @@ func NewTorznabJob(feed *domain.Feed, name string, indexerIdentifier string, log
 
 func (j *TorznabJob) Run() {
 	ctx := context.Background()
+	start := time.Now()
 
-	if err := j.process(ctx); err != nil {
+	err := j.process(ctx)
+	observeFeedRun(j.Feed, start, err)
+
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("torznab process error")
 
 		j.errors = append(j.errors, err)
+		j.health.failed(j.Feed, err)
+	} else {
+		j.health.succeeded()
 	}
 
 	j.attempts = 0
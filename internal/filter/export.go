@@ -0,0 +1,204 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// Export returns the filters with their actions in the portable format. All filters are exported if no ids are given.
+func (s *service) Export(ctx context.Context, filterIDs []int) (*domain.FilterExport, error) {
+	if len(filterIDs) == 0 {
+		filters, err := s.repo.ListFilters(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list filters")
+		}
+
+		for _, f := range filters {
+			filterIDs = append(filterIDs, f.ID)
+		}
+	}
+
+	clients, err := s.downloadClientSvc.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	clientNames := make(map[int32]string, len(clients))
+	for _, c := range clients {
+		clientNames[int32(c.ID)] = c.Name
+	}
+
+	export := &domain.FilterExport{
+		Version:    domain.FilterExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Filters:    make([]domain.FilterExportItem, 0, len(filterIDs)),
+	}
+
+	for _, id := range filterIDs {
+		f, err := s.FindByID(ctx, id)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find filter: %d", id)
+		}
+
+		export.Filters = append(export.Filters, newFilterExportItem(*f, clientNames))
+	}
+
+	return export, nil
+}
+
+// Import creates the filters in the export. Indexers and download clients are mapped by identifier and name,
+// anything missing on this instance is reported as a warning. Filters are imported disabled so they can be reviewed first.
+func (s *service) Import(ctx context.Context, export domain.FilterExport) (*domain.FilterImportResult, error) {
+	if export.Version == 0 {
+		return nil, errors.New("validation: not a filter export")
+	} else if export.Version > domain.FilterExportVersion {
+		return nil, errors.New("validation: unsupported filter export version: %d", export.Version)
+	}
+
+	indexers, err := s.indexerSvc.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list indexers")
+	}
+
+	indexersByIdentifier := make(map[string]domain.Indexer, len(indexers))
+	for _, i := range indexers {
+		indexersByIdentifier[i.Identifier] = i
+	}
+
+	clients, err := s.downloadClientSvc.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list download clients")
+	}
+
+	clientIDs := make(map[string]int32, len(clients))
+	for _, c := range clients {
+		clientIDs[strings.ToLower(c.Name)] = int32(c.ID)
+	}
+
+	result := &domain.FilterImportResult{
+		Filters:  make([]domain.Filter, 0, len(export.Filters)),
+		Warnings: make([]string, 0),
+	}
+
+	for _, item := range export.Filters {
+		filter, warnings := newImportedFilter(item, indexersByIdentifier, clientIDs)
+		result.Warnings = append(result.Warnings, warnings...)
+
+		f, err := s.repo.Store(ctx, filter)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not store imported filter: %v", filter.Name)
+			return nil, err
+		}
+
+		if err := s.repo.StoreIndexerConnections(ctx, f.ID, filter.Indexers); err != nil {
+			s.log.Error().Err(err).Msgf("could not store filter indexer connections: %v", filter.Name)
+			return nil, err
+		}
+		f.Indexers = filter.Indexers
+
+		actions, err := s.actionRepo.StoreFilterActions(ctx, filter.Actions, int64(f.ID))
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not store filter actions: %v", filter.Name)
+			return nil, err
+		}
+		f.Actions = actions
+
+		result.Filters = append(result.Filters, *f)
+	}
+
+	s.log.Info().Msgf("imported %d filters with %d warnings", len(result.Filters), len(result.Warnings))
+
+	return result, nil
+}
+
+// newFilterExportItem strips ids and replaces indexers and download clients with their identifier and name
+func newFilterExportItem(f domain.Filter, clientNames map[int32]string) domain.FilterExportItem {
+	item := domain.FilterExportItem{
+		Indexers: make([]string, 0, len(f.Indexers)),
+		Actions:  make([]domain.FilterExportAction, 0, len(f.Actions)),
+	}
+
+	for _, i := range f.Indexers {
+		item.Indexers = append(item.Indexers, i.Identifier)
+	}
+
+	for _, a := range f.Actions {
+		action := domain.FilterExportAction{
+			Action: *a,
+			Client: clientNames[a.ClientID],
+		}
+
+		action.Action.ID = 0
+		action.Action.FilterID = 0
+		action.Action.ClientID = 0
+		// never include the client with its credentials
+		action.Action.Client = nil
+		// webhook headers usually hold auth tokens
+		action.Action.WebhookHeaders = nil
+
+		item.Actions = append(item.Actions, action)
+	}
+
+	f.ID = 0
+	f.CreatedAt = time.Time{}
+	f.UpdatedAt = time.Time{}
+	f.ActionsCount = 0
+	f.Actions = nil
+	f.Indexers = nil
+	f.Downloads = nil
+
+	item.Filter = f
+
+	return item
+}
+
+// newImportedFilter maps an exported filter onto the indexers and download clients of this instance
+func newImportedFilter(item domain.FilterExportItem, indexers map[string]domain.Indexer, clientIDs map[string]int32) (domain.Filter, []string) {
+	var warnings []string
+
+	f := item.Filter
+	f.ID = 0
+	f.Enabled = false
+	f.Indexers = make([]domain.Indexer, 0, len(item.Indexers))
+	f.Actions = make([]*domain.Action, 0, len(item.Actions))
+
+	for _, identifier := range item.Indexers {
+		indexer, ok := indexers[identifier]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("filter %q: indexer %q not found", f.Name, identifier))
+			continue
+		}
+
+		f.Indexers = append(f.Indexers, indexer)
+	}
+
+	for _, a := range item.Actions {
+		action := a.Action
+		action.ID = 0
+		action.FilterID = 0
+		action.ClientID = 0
+		action.Client = nil
+
+		if a.Client != "" {
+			clientID, ok := clientIDs[strings.ToLower(a.Client)]
+			if ok {
+				action.ClientID = clientID
+			} else {
+				warnings = append(warnings, fmt.Sprintf("filter %q: download client %q for action %q not found, action disabled", f.Name, a.Client, action.Name))
+				action.Enabled = false
+			}
+		}
+
+		f.Actions = append(f.Actions, &action)
+	}
+
+	return f, warnings
+}
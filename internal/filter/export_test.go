@@ -0,0 +1,113 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testExportFilter() domain.Filter {
+	return domain.Filter{
+		ID:           10,
+		Name:         "tv",
+		Enabled:      true,
+		Resolutions:  []string{"1080p"},
+		ActionsCount: 2,
+		Indexers: []domain.Indexer{
+			{ID: 3, Identifier: "mock", Name: "Mock"},
+			{ID: 4, Identifier: "other", Name: "Other"},
+		},
+		Actions: []*domain.Action{
+			{ID: 7, Name: "qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, FilterID: 10, ClientID: 2, Client: &domain.DownloadClient{ID: 2, Name: "qbit", Password: "secret"}},
+			{ID: 8, Name: "notify", Type: domain.ActionTypeWebhook, Enabled: true, FilterID: 10, WebhookHost: "http://localhost", WebhookHeaders: []string{"Authorization=Bearer token"}},
+		},
+	}
+}
+
+func Test_newFilterExportItem(t *testing.T) {
+	item := newFilterExportItem(testExportFilter(), map[int32]string{2: "qBittorrent"})
+
+	assert.Equal(t, 0, item.ID)
+	assert.Equal(t, "tv", item.Name)
+	assert.Equal(t, []string{"mock", "other"}, item.Indexers)
+	assert.Len(t, item.Actions, 2)
+	assert.Equal(t, "qBittorrent", item.Actions[0].Client)
+	assert.Nil(t, item.Actions[0].Action.Client)
+	assert.Equal(t, int32(0), item.Actions[0].ClientID)
+	assert.Equal(t, "", item.Actions[1].Client)
+	assert.Nil(t, item.Actions[1].WebhookHeaders)
+	assert.Equal(t, "http://localhost", item.Actions[1].WebhookHost)
+
+	// the portable fields must replace the ones from the embedded filter and action
+	data, err := json.Marshal(item)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.NotContains(t, string(data), "Bearer token")
+
+	var decoded domain.FilterExportItem
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []string{"mock", "other"}, decoded.Indexers)
+	assert.Equal(t, "qBittorrent", decoded.Actions[0].Client)
+}
+
+func Test_newImportedFilter(t *testing.T) {
+	item := newFilterExportItem(testExportFilter(), map[int32]string{2: "qBittorrent"})
+
+	tests := []struct {
+		name         string
+		indexers     map[string]domain.Indexer
+		clients      map[string]int32
+		wantIndexers int
+		wantClientID int32
+		wantEnabled  bool
+		wantWarnings int
+	}{
+		{
+			name:         "all_mapped",
+			indexers:     map[string]domain.Indexer{"mock": {ID: 30, Identifier: "mock"}, "other": {ID: 40, Identifier: "other"}},
+			clients:      map[string]int32{"qbittorrent": 5},
+			wantIndexers: 2,
+			wantClientID: 5,
+			wantEnabled:  true,
+			wantWarnings: 0,
+		},
+		{
+			name:         "missing_indexer",
+			indexers:     map[string]domain.Indexer{"mock": {ID: 30, Identifier: "mock"}},
+			clients:      map[string]int32{"qbittorrent": 5},
+			wantIndexers: 1,
+			wantClientID: 5,
+			wantEnabled:  true,
+			wantWarnings: 1,
+		},
+		{
+			name:         "missing_client",
+			indexers:     map[string]domain.Indexer{"mock": {ID: 30, Identifier: "mock"}, "other": {ID: 40, Identifier: "other"}},
+			clients:      map[string]int32{},
+			wantIndexers: 2,
+			wantClientID: 0,
+			wantEnabled:  false,
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, warnings := newImportedFilter(item, tt.indexers, tt.clients)
+
+			assert.False(t, f.Enabled)
+			assert.Len(t, f.Indexers, tt.wantIndexers)
+			assert.Len(t, warnings, tt.wantWarnings)
+			assert.Len(t, f.Actions, 2)
+			assert.Equal(t, tt.wantClientID, f.Actions[0].ClientID)
+			assert.Equal(t, tt.wantEnabled, f.Actions[0].Enabled)
+			// actions without a download client are left as is
+			assert.True(t, f.Actions[1].Enabled)
+		})
+	}
+}
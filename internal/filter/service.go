@@ -15,8 +15,10 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/dustin/go-humanize"
@@ -37,8 +39,11 @@ type Service interface {
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
 	Delete(ctx context.Context, filterID int) error
 	AdditionalSizeCheck(ctx context.Context, f domain.Filter, release *domain.Release) (bool, error)
-	CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error)
+	CanDownloadShow(ctx context.Context, release *domain.Release, rankQuality bool) (bool, error)
 	GetDownloadsByFilterId(ctx context.Context, filterID int) (*domain.FilterDownloads, error)
+	DryRun(ctx context.Context, req domain.FilterDryRunRequest) ([]domain.FilterDryRunResult, error)
+	Export(ctx context.Context, filterIDs []int) (*domain.FilterExport, error)
+	Import(ctx context.Context, export domain.FilterExport) (*domain.FilterImportResult, error)
 }
 
 type service struct {
@@ -48,16 +53,19 @@ type service struct {
 	releaseRepo domain.ReleaseRepo
 	indexerSvc  indexer.Service
 	apiService  indexer.APIService
+
+	downloadClientSvc download_client.Service
 }
 
-func NewService(log logger.Logger, repo domain.FilterRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service) Service {
+func NewService(log logger.Logger, repo domain.FilterRepo, actionRepo domain.ActionRepo, releaseRepo domain.ReleaseRepo, apiService indexer.APIService, indexerSvc indexer.Service, downloadClientSvc download_client.Service) Service {
 	return &service{
-		log:         log.With().Str("module", "filter").Logger(),
-		repo:        repo,
-		actionRepo:  actionRepo,
-		releaseRepo: releaseRepo,
-		apiService:  apiService,
-		indexerSvc:  indexerSvc,
+		log:               log.With().Str("module", "filter").Logger(),
+		repo:              repo,
+		actionRepo:        actionRepo,
+		releaseRepo:       releaseRepo,
+		apiService:        apiService,
+		indexerSvc:        indexerSvc,
+		downloadClientSvc: downloadClientSvc,
 	}
 }
 
@@ -329,7 +337,7 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 	if matchedFilter {
 		// smartEpisode check
 		if f.SmartEpisode {
-			canDownloadShow, err := s.CanDownloadShow(ctx, release)
+			canDownloadShow, err := s.CanDownloadShow(ctx, release, f.SmartEpisodeUpgrade)
 			if err != nil {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: failed smart episode check: %s", f.Name)
 				return false, nil
@@ -337,7 +345,6 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 
 			if !canDownloadShow {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: failed smart episode check: %s", f.Name)
-				release.AddRejectionF("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
 				return false, nil
 			}
 		}
@@ -362,6 +369,7 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 
 			if !ok {
 				s.log.Trace().Msgf("filter.Service.CheckFilter: (%v) additional size check not matching what filter wanted", f.Name)
+				release.AddRejectionF("additional size check not matching. got: %v want min: %v max: %v", release.Size, f.MinSize, f.MaxSize)
 				return false, nil
 			}
 		}
@@ -407,6 +415,7 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 		// if no actions, continue to next filter
 		if len(actions) == 0 {
 			s.log.Trace().Msgf("filter.Service.CheckFilter: no actions found for filter '%v', trying next one..", f.Name)
+			release.AddRejectionF("no actions found for filter: %v", f.Name)
 			return false, nil
 		}
 		release.Filter.Actions = actions
@@ -418,6 +427,137 @@ func (s *service) CheckFilter(ctx context.Context, f domain.Filter, release *dom
 	return false, nil
 }
 
+// DryRun checks a release name against every enabled filter, or only those of the indexer if set,
+// and returns the match and rejections per filter. Actions are never run and external scripts
+// and webhooks are skipped unless RunExternal is set.
+func (s *service) DryRun(ctx context.Context, req domain.FilterDryRunRequest) ([]domain.FilterDryRunResult, error) {
+	if req.TorrentName == "" {
+		return nil, errors.New("validation: torrent_name can't be empty")
+	}
+
+	var filters []domain.Filter
+
+	if req.Indexer != "" {
+		indexerFilters, err := s.repo.FindByIndexerIdentifier(ctx, req.Indexer)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("filter.Service.DryRun: could not find filters for indexer: %v", req.Indexer)
+			return nil, err
+		}
+
+		filters = indexerFilters
+	} else {
+		list, err := s.repo.ListFilters(ctx)
+		if err != nil {
+			s.log.Error().Err(err).Msg("filter.Service.DryRun: could not list filters")
+			return nil, err
+		}
+
+		for _, item := range list {
+			if !item.Enabled {
+				continue
+			}
+
+			f, err := s.repo.FindByID(ctx, item.ID)
+			if err != nil {
+				s.log.Error().Err(err).Msgf("filter.Service.DryRun: could not find filter: %v", item.ID)
+				return nil, err
+			}
+
+			filters = append(filters, *f)
+		}
+	}
+
+	results := make([]domain.FilterDryRunResult, 0, len(filters))
+
+	for _, f := range filters {
+		f := f
+
+		result := domain.FilterDryRunResult{
+			FilterID:   f.ID,
+			FilterName: f.Name,
+			Priority:   f.Priority,
+			Rejections: []string{},
+			Skipped:    []string{},
+		}
+
+		release := newDryRunRelease(req)
+
+		// without a size the additional size check would call the indexer api or download the torrent
+		if release.Size == 0 && (f.MinSize != "" || f.MaxSize != "") {
+			f.MinSize = ""
+			f.MaxSize = ""
+			result.Skipped = append(result.Skipped, "size check: no size given")
+		}
+
+		if !req.RunExternal {
+			if f.ExternalScriptEnabled {
+				f.ExternalScriptEnabled = false
+				result.Skipped = append(result.Skipped, "external script")
+			}
+
+			if f.ExternalWebhookEnabled {
+				f.ExternalWebhookEnabled = false
+				result.Skipped = append(result.Skipped, "external webhook")
+			}
+		}
+
+		release.Filter = &f
+		release.FilterName = f.Name
+		release.FilterID = f.ID
+
+		match, err := s.CheckFilter(ctx, f, release)
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		result.Match = match
+		result.Rejections = append(result.Rejections, release.Rejections...)
+
+		release.CleanupTemporaryFiles()
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// newDryRunRelease builds a release the same way as an announce would from the dry run request
+func newDryRunRelease(req domain.FilterDryRunRequest) *domain.Release {
+	release := domain.NewRelease(req.Indexer)
+
+	release.Category = req.Category
+	release.Uploader = req.Uploader
+	release.ReleaseTags = req.ReleaseTags
+
+	if req.Freeleech {
+		release.Freeleech = true
+		release.FreeleechPercent = 100
+		release.Bonus = append(release.Bonus, "Freeleech")
+	}
+
+	if req.FreeleechPercent > 0 {
+		if !release.Freeleech {
+			release.Bonus = append(release.Bonus, "Freeleech")
+		}
+
+		release.Freeleech = true
+		release.FreeleechPercent = req.FreeleechPercent
+
+		switch req.FreeleechPercent {
+		case 25, 50, 75, 100:
+			release.Bonus = append(release.Bonus, fmt.Sprintf("Freeleech%d", req.FreeleechPercent))
+		}
+	}
+
+	release.ParseString(req.TorrentName)
+
+	if req.Size != "" {
+		release.ParseSizeBytesString(req.Size)
+	}
+
+	return release
+}
+
 // AdditionalSizeCheck
 // Some indexers do not announce the size and if size (min,max) is set in a filter then it will need
 // additional size check. Some indexers have api implemented to fetch this data and for the others
@@ -451,7 +591,7 @@ func (s *service) AdditionalSizeCheck(ctx context.Context, f domain.Filter, rele
 		s.log.Trace().Msgf("filter.Service.AdditionalSizeCheck: (%s) preparing to download torrent metafile", f.Name)
 
 		// if indexer doesn't have api, download torrent and add to tmpPath
-		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		if err := s.downloadTorrentFile(ctx, release); err != nil {
 			s.log.Error().Stack().Err(err).Msgf("filter.Service.AdditionalSizeCheck: (%s) could not download torrent file with id: '%s' from: %s", f.Name, release.TorrentID, release.Indexer)
 			return false, err
 		}
@@ -504,15 +644,65 @@ func checkSizeFilter(minSize string, maxSize string, releaseSize uint64) (bool,
 	return true, nil
 }
 
-func (s *service) CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error) {
-	return s.releaseRepo.CanDownloadShow(ctx, release.Title, release.Season, release.Episode)
+// CanDownloadShow checks that no newer episode, season or air date has been grabbed, and that the release is an upgrade of any grabbed
+// release of the same episode. A proper or repack of the same quality is an upgrade, and when rankQuality is set so is
+// a better resolution or source. Without rankQuality grabs of another resolution or source are ignored, so separate filters
+// per quality keep working. The reason is added to the release rejections.
+func (s *service) CanDownloadShow(ctx context.Context, release *domain.Release, rankQuality bool) (bool, error) {
+	canDownload, err := s.releaseRepo.CanDownloadShow(ctx, release)
+	if err != nil {
+		return false, err
+	}
+
+	if !canDownload {
+		if release.IsDaily() {
+			release.AddRejectionF("smart episode check: not new: (%s) air date: %04d-%02d-%02d", release.Title, release.Year, release.Month, release.Day)
+		} else {
+			release.AddRejectionF("smart episode check: not new: (%s) season: %d ep: %d", release.Title, release.Season, release.Episode)
+		}
+		return false, nil
+	}
+
+	// nothing to compare for releases without season, episode or air date
+	if release.Episode == 0 && !release.IsDaily() && !release.IsSeasonPack() {
+		return true, nil
+	}
+
+	existing, err := s.releaseRepo.FindSameEpisode(ctx, release)
+	if err != nil {
+		return false, err
+	}
+
+	for _, other := range existing {
+		if !rankQuality && !release.IsSameQuality(other) {
+			continue
+		}
+
+		if !release.IsUpgradeOf(other, rankQuality) {
+			release.AddRejectionF("smart episode check: not an upgrade of %s: (%s) season: %d ep: %d", other.TorrentName, release.Title, release.Season, release.Episode)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// downloadTorrentFile downloads the torrent file of the release and observes how long it took
+func (s *service) downloadTorrentFile(ctx context.Context, release *domain.Release) error {
+	start := time.Now()
+
+	err := release.DownloadTorrentFileCtx(ctx)
+
+	metrics.ObserveTorrentDownload(release.Indexer, start, err)
+
+	return err
 }
 
 func (s *service) execCmd(ctx context.Context, release *domain.Release, cmd string, args string) (int, error) {
 	s.log.Debug().Msgf("filter exec release: %v", release.TorrentName)
 
 	if release.TorrentTmpFile == "" && strings.Contains(args, "TorrentPathName") {
-		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		if err := s.downloadTorrentFile(ctx, release); err != nil {
 			return 0, errors.Wrap(err, "error downloading torrent file for release: %v", release.TorrentName)
 		}
 	}
@@ -574,7 +764,7 @@ func (s *service) webhook(ctx context.Context, release *domain.Release, url stri
 
 	// if webhook data contains TorrentPathName or TorrentDataRawBytes, lets download the torrent file
 	if release.TorrentTmpFile == "" && (strings.Contains(data, "TorrentPathName") || strings.Contains(data, "TorrentDataRawBytes")) {
-		if err := release.DownloadTorrentFileCtx(ctx); err != nil {
+		if err := s.downloadTorrentFile(ctx, release); err != nil {
 			return 0, errors.Wrap(err, "webhook: could not download torrent file for release: %s", release.TorrentName)
 		}
 	}
@@ -3,7 +3,18 @@
 
 package filter
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
 
 func Test_checkSizeFilter(t *testing.T) {
 	type args struct {
@@ -39,3 +50,201 @@ func Test_checkSizeFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_newDryRunRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		req  domain.FilterDryRunRequest
+		want func(t *testing.T, r *domain.Release)
+	}{
+		{
+			name: "parse_name_and_size",
+			req:  domain.FilterDryRunRequest{TorrentName: "That Show S01E02 1080p WEB-DL DDP5.1 H.264-GROUP", Indexer: "mock", Size: "2 GB", Category: "TV", Uploader: "Anon"},
+			want: func(t *testing.T, r *domain.Release) {
+				assert.Equal(t, "mock", r.Indexer)
+				assert.Equal(t, "That Show", r.Title)
+				assert.Equal(t, 1, r.Season)
+				assert.Equal(t, 2, r.Episode)
+				assert.Equal(t, "1080p", r.Resolution)
+				assert.Equal(t, uint64(2000000000), r.Size)
+				assert.Equal(t, "TV", r.Category)
+				assert.Equal(t, "Anon", r.Uploader)
+				assert.False(t, r.Freeleech)
+			},
+		},
+		{
+			name: "freeleech",
+			req:  domain.FilterDryRunRequest{TorrentName: "That Show S01E02 1080p WEB-DL DDP5.1 H.264-GROUP", Freeleech: true},
+			want: func(t *testing.T, r *domain.Release) {
+				assert.True(t, r.Freeleech)
+				assert.Equal(t, 100, r.FreeleechPercent)
+				assert.Equal(t, []string{"Freeleech"}, r.Bonus)
+			},
+		},
+		{
+			name: "freeleech_percent",
+			req:  domain.FilterDryRunRequest{TorrentName: "That Show S01E02 1080p WEB-DL DDP5.1 H.264-GROUP", FreeleechPercent: 50},
+			want: func(t *testing.T, r *domain.Release) {
+				assert.True(t, r.Freeleech)
+				assert.Equal(t, 50, r.FreeleechPercent)
+				assert.Equal(t, []string{"Freeleech", "Freeleech50"}, r.Bonus)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want(t, newDryRunRelease(tt.req))
+		})
+	}
+}
+
+type mockFilterRepo struct {
+	domain.FilterRepo
+
+	filters []domain.Filter
+}
+
+func (r *mockFilterRepo) ListFilters(ctx context.Context) ([]domain.Filter, error) {
+	return r.filters, nil
+}
+
+func (r *mockFilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter, error) {
+	for _, f := range r.filters {
+		if f.ID == filterID {
+			f := f
+			return &f, nil
+		}
+	}
+
+	return nil, errors.New("filter not found: %d", filterID)
+}
+
+func (r *mockFilterRepo) FindByIndexerIdentifier(ctx context.Context, indexer string) ([]domain.Filter, error) {
+	var filters []domain.Filter
+	for _, f := range r.filters {
+		for _, i := range f.Indexers {
+			if i.Identifier == indexer && f.Enabled {
+				filters = append(filters, f)
+			}
+		}
+	}
+
+	return filters, nil
+}
+
+type mockActionRepo struct {
+	domain.ActionRepo
+}
+
+func (r *mockActionRepo) FindByFilterID(ctx context.Context, filterID int) ([]*domain.Action, error) {
+	return []*domain.Action{{ID: 1, Name: "test", Type: domain.ActionTypeTest, Enabled: true, FilterID: filterID}}, nil
+}
+
+func TestService_DryRun(t *testing.T) {
+	var webhookCalls int
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	mockIndexer := domain.Indexer{ID: 1, Identifier: "mock"}
+
+	repo := &mockFilterRepo{filters: []domain.Filter{
+		{
+			ID:                          1,
+			Name:                        "external",
+			Enabled:                     true,
+			Priority:                    10,
+			Resolutions:                 []string{"1080p"},
+			Indexers:                    []domain.Indexer{mockIndexer},
+			ExternalScriptEnabled:       true,
+			ExternalWebhookEnabled:      true,
+			ExternalWebhookHost:         webhook.URL,
+			ExternalWebhookData:         `{"name": "{{ .TorrentName }}"}`,
+			ExternalWebhookExpectStatus: http.StatusOK,
+		},
+		{
+			ID:       2,
+			Name:     "size",
+			Enabled:  true,
+			MinSize:  "10 GB",
+			Indexers: []domain.Indexer{mockIndexer},
+		},
+		{
+			ID:          3,
+			Name:        "2160p",
+			Enabled:     true,
+			Resolutions: []string{"2160p"},
+			Indexers:    []domain.Indexer{mockIndexer},
+		},
+		{
+			ID:      4,
+			Name:    "disabled",
+			Enabled: false,
+		},
+	}}
+
+	s := &service{log: zerolog.Nop(), repo: repo, actionRepo: &mockActionRepo{}}
+
+	results := func(t *testing.T, req domain.FilterDryRunRequest) map[string]domain.FilterDryRunResult {
+		t.Helper()
+
+		res, err := s.DryRun(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		byName := map[string]domain.FilterDryRunResult{}
+		for _, r := range res {
+			byName[r.FilterName] = r
+		}
+
+		return byName
+	}
+
+	t.Run("without_external", func(t *testing.T) {
+		webhookCalls = 0
+
+		got := results(t, domain.FilterDryRunRequest{TorrentName: "That Show S01E02 1080p WEB-DL DDP5.1 H.264-GROUP"})
+
+		assert.Len(t, got, 3, "disabled filters are not checked")
+		assert.NotContains(t, got, "disabled")
+
+		assert.True(t, got["external"].Match)
+		assert.Equal(t, []string{"external script", "external webhook"}, got["external"].Skipped)
+		assert.Equal(t, int32(10), got["external"].Priority)
+		assert.Zero(t, webhookCalls)
+
+		// without a size the size check is skipped instead of looking it up
+		assert.True(t, got["size"].Match)
+		assert.Equal(t, []string{"size check: no size given"}, got["size"].Skipped)
+
+		assert.False(t, got["2160p"].Match)
+		assert.NotEmpty(t, got["2160p"].Rejections)
+		assert.Empty(t, got["2160p"].Skipped)
+	})
+
+	t.Run("with_external_and_size", func(t *testing.T) {
+		webhookCalls = 0
+
+		got := results(t, domain.FilterDryRunRequest{TorrentName: "That Show S01E02 1080p WEB-DL DDP5.1 H.264-GROUP", Indexer: "mock", Size: "2 GB", RunExternal: true})
+
+		assert.Len(t, got, 3)
+
+		assert.True(t, got["external"].Match)
+		assert.Empty(t, got["external"].Skipped)
+		assert.Equal(t, 1, webhookCalls)
+
+		assert.False(t, got["size"].Match)
+		assert.Empty(t, got["size"].Skipped)
+		if assert.Len(t, got["size"].Rejections, 1) {
+			assert.Contains(t, got["size"].Rejections[0], "size not matching")
+		}
+	})
+
+	t.Run("empty_name", func(t *testing.T) {
+		_, err := s.DryRun(context.Background(), domain.FilterDryRunRequest{})
+		assert.Error(t, err)
+	})
+}
@@ -20,6 +20,7 @@ type apikeyService interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	GetKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type apikeyHandler struct {
@@ -63,6 +64,11 @@ func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := data.ValidateScopes(); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	if err := h.service.Store(ctx, &data); err != nil {
 		// encode error
 		h.encoder.StatusInternalError(w)
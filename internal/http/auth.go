@@ -5,8 +5,13 @@ package http
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -20,8 +25,29 @@ type authService interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	FindUserByID(ctx context.Context, id int) (*domain.User, error)
+	OIDCEnabled() bool
+	OIDCAuthURL(ctx context.Context, state, nonce string) (string, error)
+	LoginOIDC(ctx context.Context, code, nonce string) (*domain.User, error)
+	LinkOIDC(ctx context.Context, userID int, code, nonce string) error
+	UnlinkOIDC(ctx context.Context, userID int) error
+	SetupTwoFactor(ctx context.Context, userID int) (*domain.TwoFactorSetup, error)
+	EnableTwoFactor(ctx context.Context, userID int, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID int, code string) error
+	VerifyTwoFactor(ctx context.Context, userID int, code string) error
 }
 
+type sessionService interface {
+	Create(ctx context.Context, userID int, ip, userAgent string) (*domain.Session, error)
+	Validate(ctx context.Context, id string, userID int) bool
+	List(ctx context.Context, userID int) ([]domain.Session, error)
+	Revoke(ctx context.Context, userID int, id string) error
+	RevokeOthers(ctx context.Context, userID int, current string) error
+}
+
+// twoFactorTimeout is how long a password login waits for the two-factor code
+const twoFactorTimeout = 5 * time.Minute
+
 type authHandler struct {
 	log     zerolog.Logger
 	encoder encoder
@@ -29,37 +55,51 @@ type authHandler struct {
 	service authService
 
 	cookieStore *sessions.CookieStore
+	sessions    sessionService
+	limiter     *loginLimiter
 }
 
-func newAuthHandler(encoder encoder, log zerolog.Logger, config *domain.Config, cookieStore *sessions.CookieStore, service authService) *authHandler {
+func newAuthHandler(encoder encoder, log zerolog.Logger, config *domain.Config, cookieStore *sessions.CookieStore, service authService, sessionSvc sessionService, limiter *loginLimiter) *authHandler {
 	return &authHandler{
 		log:         log,
 		encoder:     encoder,
 		config:      config,
 		service:     service,
 		cookieStore: cookieStore,
+		sessions:    sessionSvc,
+		limiter:     limiter,
 	}
 }
 
 func (h authHandler) Routes(r chi.Router) {
 	r.Post("/login", h.login)
+	r.Post("/login/2fa", h.loginTwoFactor)
 	r.Post("/logout", h.logout)
 	r.Post("/onboard", h.onboard)
 	r.Get("/onboard", h.canOnboard)
 	r.Get("/validate", h.validate)
+	r.Get("/oidc", h.oidcConfig)
+	r.Get("/oidc/login", h.oidcLogin)
+	r.Get("/oidc/callback", h.oidcCallback)
+	r.Get("/oidc/link", h.oidcLinkStatus)
+	r.Delete("/oidc/link", h.oidcUnlink)
+
+	r.Route("/sessions", func(r chi.Router) {
+		r.Get("/", h.listSessions)
+		r.Delete("/", h.revokeOtherSessions)
+		r.Delete("/{sessionID}", h.revokeSession)
+	})
+
+	r.Route("/2fa", func(r chi.Router) {
+		r.Get("/", h.twoFactorStatus)
+		r.Post("/setup", h.twoFactorSetup)
+		r.Post("/enable", h.twoFactorEnable)
+		r.Post("/disable", h.twoFactorDisable)
+	})
 }
 
-func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
-	var (
-		ctx  = r.Context()
-		data domain.User
-	)
-
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
-		return
-	}
-
+// setCookieOptions sets the session cookie options for the request
+func (h authHandler) setCookieOptions(r *http.Request) {
 	h.cookieStore.Options.HttpOnly = true
 	h.cookieStore.Options.SameSite = http.SameSiteLaxMode
 	h.cookieStore.Options.Path = h.config.BaseURL
@@ -73,6 +113,29 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 		h.cookieStore.Options.Secure = true
 		h.cookieStore.Options.SameSite = http.SameSiteStrictMode
 	}
+}
+
+func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.User
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	ip := ReadUserIP(r)
+	limitKeys := []string{loginIPKey(ip), loginUserKey(ip, data.Username)}
+
+	if remaining, locked := h.limiter.Locked(limitKeys...); locked {
+		h.log.Warn().Msgf("Auth: Login locked out username: [%s] ip: %s", data.Username, ip)
+		h.tooManyAttempts(w, remaining)
+		return
+	}
+
+	h.setCookieOptions(r)
 
 	session, err := h.cookieStore.Get(r, "user_session")
 	if err != nil {
@@ -80,16 +143,95 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err = h.service.Login(ctx, data.Username, data.Password); err != nil {
-		h.log.Error().Err(err).Msgf("Auth: Failed login attempt username: [%s] ip: %s", data.Username, ReadUserIP(r))
+	user, err := h.service.Login(ctx, data.Username, data.Password)
+	if err != nil {
+		h.limiter.Fail(limitKeys...)
+		h.log.Error().Err(err).Msgf("Auth: Failed login attempt username: [%s] ip: %s", data.Username, ip)
 		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: bad credentials"))
 		return
 	}
 
-	// Set user as authenticated
-	session.Values["authenticated"] = true
-	if err := session.Save(r, w); err != nil {
-		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
+	// the session stays unauthenticated until the two-factor code is verified
+	if user.TwoFactorEnabled {
+		session.Values["authenticated"] = false
+		delete(session.Values, "session_id")
+		delete(session.Values, "user_id")
+		delete(session.Values, "username")
+		session.Values["two_factor_user_id"] = user.ID
+		session.Values["two_factor_expires"] = time.Now().Add(twoFactorTimeout).Unix()
+		if err := session.Save(r, w); err != nil {
+			h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
+			return
+		}
+
+		h.encoder.StatusResponse(w, http.StatusOK, map[string]bool{"two_factor_required": true})
+		return
+	}
+
+	h.limiter.Reset(limitKeys...)
+
+	if err := h.startSession(w, r, session, user); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusNoContent, nil)
+}
+
+func (h authHandler) loginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.TwoFactorRequest
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	h.setCookieOptions(r)
+
+	session, err := h.cookieStore.Get(r, "user_session")
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, errors.New("could not get session"))
+		return
+	}
+
+	userID, ok := session.Values["two_factor_user_id"].(int)
+	expires, _ := session.Values["two_factor_expires"].(int64)
+	if !ok || time.Now().Unix() > expires {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("no pending login, log in with your password again"))
+		return
+	}
+
+	user, err := h.service.FindUserByID(ctx, userID)
+	if err != nil || user == nil {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: bad credentials"))
+		return
+	}
+
+	ip := ReadUserIP(r)
+	limitKeys := []string{loginIPKey(ip), loginUserKey(ip, user.Username)}
+
+	if remaining, locked := h.limiter.Locked(limitKeys...); locked {
+		h.tooManyAttempts(w, remaining)
+		return
+	}
+
+	if err := h.service.VerifyTwoFactor(ctx, user.ID, data.Code); err != nil {
+		h.limiter.Fail(limitKeys...)
+		h.log.Error().Err(err).Msgf("Auth: Failed two-factor attempt username: [%s] ip: %s", user.Username, ip)
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login: invalid two-factor code"))
+		return
+	}
+
+	h.limiter.Reset(limitKeys...)
+
+	delete(session.Values, "two_factor_user_id")
+	delete(session.Values, "two_factor_expires")
+
+	if err := h.startSession(w, r, session, user); err != nil {
+		h.encoder.Error(w, err)
 		return
 	}
 
@@ -110,7 +252,17 @@ func (h authHandler) logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Revoke users authentication
+	userID, _ := session.Values["user_id"].(int)
+	if sessionID, ok := session.Values["session_id"].(string); ok {
+		if err := h.sessions.Revoke(r.Context(), userID, sessionID); err != nil {
+			h.log.Debug().Err(err).Msg("could not revoke session")
+		}
+	}
+
 	session.Values["authenticated"] = false
+	delete(session.Values, "session_id")
+	delete(session.Values, "user_id")
+	delete(session.Values, "username")
 	session.Options.MaxAge = -1
 	if err := session.Save(r, w); err != nil {
 		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
@@ -187,17 +339,358 @@ func (h authHandler) validate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// send empty response as ok
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, user)
+}
+
+// startSession registers a new session for the user and marks the cookie as authenticated
+func (h authHandler) startSession(w http.ResponseWriter, r *http.Request, session *sessions.Session, user *domain.User) error {
+	s, err := h.sessions.Create(r.Context(), user.ID, ReadUserIP(r), r.UserAgent())
+	if err != nil {
+		return errors.Wrap(err, "could not create session")
+	}
+
+	session.Values["authenticated"] = true
+	session.Values["session_id"] = s.ID
+	session.Values["user_id"] = user.ID
+	session.Values["username"] = user.Username
+	if err := session.Save(r, w); err != nil {
+		return errors.Wrap(err, "could not save session")
+	}
+
+	return nil
+}
+
+// sessionUser returns the user and session id of an authenticated session, or writes 401 and returns false
+func (h authHandler) sessionUser(w http.ResponseWriter, r *http.Request) (*domain.User, string, bool) {
+	session, err := h.cookieStore.Get(r, "user_session")
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, errors.New("could not get session"))
+		return nil, "", false
+	}
+
+	if auth, ok := session.Values["authenticated"].(bool); !ok || !auth {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("forbidden: invalid session"))
+		return nil, "", false
+	}
+
+	userID, ok := session.Values["user_id"].(int)
+	if !ok {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("forbidden: invalid session"))
+		return nil, "", false
+	}
+
+	sessionID, _ := session.Values["session_id"].(string)
+	if !h.sessions.Validate(r.Context(), sessionID, userID) {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("forbidden: invalid session"))
+		return nil, "", false
+	}
+
+	user, err := h.service.FindUserByID(r.Context(), userID)
+	if err != nil || user == nil {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("forbidden: invalid session"))
+		return nil, "", false
+	}
+
+	user.Password = ""
+
+	return user, sessionID, true
+}
+
+func (h authHandler) tooManyAttempts(w http.ResponseWriter, remaining time.Duration) {
+	w.Header().Set("Retry-After", fmt.Sprint(int(remaining.Seconds())+1))
+	h.encoder.StatusError(w, http.StatusTooManyRequests, errors.New("too many failed login attempts, try again in %v", remaining.Round(time.Second)))
+}
+
+func (h authHandler) listSessions(w http.ResponseWriter, r *http.Request) {
+	user, current, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.sessions.List(r.Context(), user.ID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	for i := range list {
+		list[i].Current = list[i].ID == current
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, list)
+}
+
+func (h authHandler) revokeSession(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.sessions.Revoke(r.Context(), user.ID, chi.URLParam(r, "sessionID")); err != nil {
+		h.encoder.StatusError(w, http.StatusNotFound, err)
+		return
+	}
+
 	h.encoder.NoContent(w)
 }
 
-func ReadUserIP(r *http.Request) string {
-	IPAddress := r.Header.Get("X-Real-Ip")
-	if IPAddress == "" {
-		IPAddress = r.Header.Get("X-Forwarded-For")
+func (h authHandler) revokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	user, current, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.sessions.RevokeOthers(r.Context(), user.ID, current); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h authHandler) twoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
 	}
-	if IPAddress == "" {
-		IPAddress = r.RemoteAddr
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]bool{
+		"enabled": user.TwoFactorEnabled,
+	})
+}
+
+func (h authHandler) twoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
 	}
-	return IPAddress
+
+	setup, err := h.service.SetupTwoFactor(r.Context(), user.ID)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, setup)
+}
+
+func (h authHandler) twoFactorEnable(w http.ResponseWriter, r *http.Request) {
+	var data domain.TwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.service.EnableTwoFactor(r.Context(), user.ID, data.Code)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string][]string{
+		"recovery_codes": codes,
+	})
+}
+
+func (h authHandler) twoFactorDisable(w http.ResponseWriter, r *http.Request) {
+	var data domain.TwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DisableTwoFactor(r.Context(), user.ID, data.Code); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h authHandler) oidcConfig(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]bool{
+		"enabled": h.service.OIDCEnabled(),
+	})
+}
+
+// oidcLogin starts the login with the identity provider. With ?link=true it instead links the identity
+// to the logged in user, which is the only way to connect an existing local user to an identity.
+func (h authHandler) oidcLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OIDCEnabled() {
+		h.encoder.StatusError(w, http.StatusNotFound, errors.New("oidc login is not enabled"))
+		return
+	}
+
+	linkUserID := 0
+	if r.URL.Query().Get("link") == "true" {
+		user, _, ok := h.sessionUser(w, r)
+		if !ok {
+			return
+		}
+
+		linkUserID = user.ID
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	nonce, err := randomToken()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	authURL, err := h.service.OIDCAuthURL(r.Context(), state, nonce)
+	if err != nil {
+		h.log.Error().Err(err).Msg("could not build oidc auth url")
+		h.encoder.StatusError(w, http.StatusBadGateway, errors.New("could not reach identity provider"))
+		return
+	}
+
+	// the state cookie has to survive the redirect back from the provider, so it can not be SameSite Strict
+	flow, _ := h.cookieStore.Get(r, "oidc_flow")
+	flow.Options = &sessions.Options{
+		Path:     h.config.BaseURL,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	flow.Values["state"] = state
+	flow.Values["nonce"] = nonce
+	flow.Values["link_user_id"] = linkUserID
+
+	if err := flow.Save(r, w); err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, errors.Wrap(err, "could not save session"))
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h authHandler) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	flow, err := h.cookieStore.Get(r, "oidc_flow")
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("could not get oidc session"))
+		return
+	}
+
+	state, _ := flow.Values["state"].(string)
+	nonce, _ := flow.Values["nonce"].(string)
+	linkUserID, _ := flow.Values["link_user_id"].(int)
+
+	// the flow is single use
+	flow.Options.MaxAge = -1
+	flow.Save(r, w)
+
+	if state == "" || r.URL.Query().Get("state") != state {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("invalid oidc state"))
+		return
+	}
+
+	if e := r.URL.Query().Get("error"); e != "" {
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("identity provider error: %s", e))
+		return
+	}
+
+	if linkUserID > 0 {
+		h.oidcLink(w, r, linkUserID, nonce)
+		return
+	}
+
+	user, err := h.service.LoginOIDC(r.Context(), r.URL.Query().Get("code"), nonce)
+	if err != nil {
+		h.log.Error().Err(err).Msgf("Auth: Failed oidc login attempt ip: %s", ReadUserIP(r))
+		h.encoder.StatusError(w, http.StatusUnauthorized, errors.New("could not login with oidc"))
+		return
+	}
+
+	h.setCookieOptions(r)
+
+	session, err := h.cookieStore.Get(r, "user_session")
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusInternalServerError, errors.New("could not get session"))
+		return
+	}
+
+	if err := h.startSession(w, r, session, user); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	// back to the web ui, which picks up the session
+	http.Redirect(w, r, h.config.BaseURL+"login?oidc=success", http.StatusFound)
+}
+
+// oidcLink finishes linking the identity to the user that started the flow. The user session cookie may be
+// SameSite Strict and is not sent on the redirect from the provider, so the user comes from the signed flow cookie.
+func (h authHandler) oidcLink(w http.ResponseWriter, r *http.Request, linkUserID int, nonce string) {
+	if err := h.service.LinkOIDC(r.Context(), linkUserID, r.URL.Query().Get("code"), nonce); err != nil {
+		h.log.Error().Err(err).Msgf("Auth: could not link oidc identity to user id: %d", linkUserID)
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("could not link oidc identity"))
+		return
+	}
+
+	http.Redirect(w, r, h.config.BaseURL+"settings/account?oidc=linked", http.StatusFound)
+}
+
+func (h authHandler) oidcLinkStatus(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]bool{
+		"linked": user.OIDCSubject != "",
+	})
+}
+
+func (h authHandler) oidcUnlink(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlinkOIDC(r.Context(), user.ID); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "could not generate token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ReadUserIP returns the client address, which RealIP has taken from the headers if the request came through a trusted proxy
+func ReadUserIP(r *http.Request) string {
+	// strip the port so every connection from the same client gets the same address
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+
+	return r.RemoteAddr
 }
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/backup"
+	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxBackupSize limits the size of uploaded backups
+const maxBackupSize = 32 << 20
+
+type backupService interface {
+	Export(ctx context.Context, opts domain.BackupOptions) (*domain.Backup, error)
+	Import(ctx context.Context, backup *domain.Backup) (*domain.BackupImportResult, error)
+}
+
+type backupHandler struct {
+	encoder encoder
+	service backupService
+	db      *database.DB
+}
+
+func newBackupHandler(encoder encoder, service backupService, db *database.DB) *backupHandler {
+	return &backupHandler{
+		encoder: encoder,
+		service: service,
+		db:      db,
+	}
+}
+
+func (h backupHandler) Routes(r chi.Router) {
+	r.Get("/", h.export)
+	r.Post("/import", h.importBackup)
+
+	r.Route("/database", func(r chi.Router) {
+		r.Get("/", h.listDatabaseBackups)
+		r.Post("/", h.createDatabaseBackup)
+		r.Get("/{name}", h.downloadDatabaseBackup)
+	})
+}
+
+func (h backupHandler) export(w http.ResponseWriter, r *http.Request) {
+	format := backup.Format(r.URL.Query().Get("format"))
+	if format == "" {
+		format = backup.FormatJSON
+	}
+
+	if format != backup.FormatJSON && format != backup.FormatZip {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("unsupported backup format: %s", format))
+		return
+	}
+
+	redact, _ := strconv.ParseBool(r.URL.Query().Get("redact"))
+
+	b, err := h.service.Export(r.Context(), domain.BackupOptions{Redact: redact})
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	contentType := "application/json"
+	if format == backup.FormatZip {
+		contentType = "application/zip"
+	}
+
+	filename := fmt.Sprintf("autobrr-backup-%s.%s", b.CreatedAt.Format("20060102-150405"), format)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	if err := backup.Encode(w, b, format); err != nil {
+		// headers are already sent, nothing more to do than stop
+		return
+	}
+}
+
+// importBackup takes the raw json or zip file as the request body
+func (h backupHandler) importBackup(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBackupSize+1))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not read backup"))
+		return
+	}
+
+	if len(data) > maxBackupSize {
+		h.encoder.StatusError(w, http.StatusRequestEntityTooLarge, errors.New("backup is larger than %d bytes", maxBackupSize))
+		return
+	}
+
+	b, err := backup.Decode(data)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), b)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, result)
+}
+
+func (h backupHandler) listDatabaseBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.db.ListBackups()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, backups)
+}
+
+func (h backupHandler) createDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	if h.db.Driver != "sqlite" {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("database backups are only supported for sqlite"))
+		return
+	}
+
+	b, err := h.db.Backup(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, b)
+}
+
+func (h backupHandler) downloadDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	path, err := h.db.BackupPath(name)
+	if err != nil {
+		h.encoder.StatusNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	http.ServeFile(w, r, path)
+}
@@ -6,6 +6,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -26,6 +27,9 @@ type filterService interface {
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	DryRun(ctx context.Context, req domain.FilterDryRunRequest) ([]domain.FilterDryRunResult, error)
+	Export(ctx context.Context, filterIDs []int) (*domain.FilterExport, error)
+	Import(ctx context.Context, export domain.FilterExport) (*domain.FilterImportResult, error)
 }
 
 type filterHandler struct {
@@ -42,9 +46,14 @@ func newFilterHandler(encoder encoder, service filterService) *filterHandler {
 
 func (h filterHandler) Routes(r chi.Router) {
 	r.Get("/", h.getFilters)
+	r.Get("/export", h.export)
+	r.Post("/import", h.importFilters)
 	r.Get("/{filterID}", h.getByID)
+	r.Post("/{filterID}/duplicate", h.duplicate)
+	// Deprecated: use POST
 	r.Get("/{filterID}/duplicate", h.duplicate)
 	r.Post("/", h.store)
+	r.Post("/dryrun", h.dryRun)
 	r.Put("/{filterID}", h.update)
 	r.Patch("/{filterID}", h.updatePartial)
 	r.Put("/{filterID}/enabled", h.toggleEnabled)
@@ -118,6 +127,11 @@ func (h filterHandler) getByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// actions and external webhooks can hold credentials
+	if !canAccess(r, "actions", false) {
+		filter.Redact()
+	}
+
 	h.encoder.StatusResponse(w, http.StatusOK, filter)
 }
 
@@ -164,6 +178,91 @@ func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusCreatedData(w, filter)
 }
 
+func (h filterHandler) export(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		ids []int
+	)
+
+	for _, v := range r.URL.Query()["id"] {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		ids = append(ids, id)
+	}
+
+	export, err := h.service.Export(ctx, ids)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if !canAccess(r, "actions", false) {
+		export.Redact()
+	}
+
+	filename := fmt.Sprintf("autobrr-filters-%s.json", export.ExportedAt.Format("20060102-150405"))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(export)
+}
+
+func (h filterHandler) importFilters(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.FilterExport
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.Import(ctx, data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusCreated, result)
+}
+
+func (h filterHandler) dryRun(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.FilterDryRunRequest
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if data.TorrentName == "" {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "torrent_name is required",
+		})
+		return
+	}
+
+	results, err := h.service.DryRun(ctx, data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, results)
+}
+
 func (h filterHandler) update(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
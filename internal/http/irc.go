@@ -42,6 +42,8 @@ func (h ircHandler) Routes(r chi.Router) {
 	r.Post("/", h.storeNetwork)
 	r.Put("/network/{networkID}", h.updateNetwork)
 	r.Post("/network/{networkID}/channel", h.storeChannel)
+	r.Post("/network/{networkID}/restart", h.restartNetwork)
+	// Deprecated: use POST
 	r.Get("/network/{networkID}/restart", h.restartNetwork)
 	r.Get("/network/{networkID}", h.getNetworkByID)
 	r.Delete("/network/{networkID}", h.deleteNetwork)
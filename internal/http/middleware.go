@@ -4,30 +4,44 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"runtime/debug"
 	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 )
 
+type ctxKey string
+
+const (
+	ctxKeyAPIKey ctxKey = "api_key"
+	ctxKeyUser   ctxKey = "user"
+)
+
 func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if token := r.Header.Get("X-API-Token"); token != "" {
-			// check header
-			if !s.apiService.ValidateAPIKey(r.Context(), token) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-				return
-			}
+		// check header, or query param lke ?apikey=TOKEN
+		token := r.Header.Get("X-API-Token")
+		if token == "" {
+			token = r.URL.Query().Get("apikey")
+		}
 
-		} else if key := r.URL.Query().Get("apikey"); key != "" {
-			// check query param lke ?apikey=TOKEN
-			if !s.apiService.ValidateAPIKey(r.Context(), key) {
+		if token != "" {
+			key, err := s.apiService.GetKey(r.Context(), token)
+			if err != nil {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
+
+			// store key for the scope checks
+			r = r.WithContext(context.WithValue(r.Context(), ctxKeyAPIKey, key))
+
 		} else {
 			// check session
 			session, _ := s.cookieStore.Get(r, "user_session")
@@ -37,12 +51,150 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
+
+			// sessions from before multi-user support have no user id and need to login again
+			userID, ok := session.Values["user_id"].(int)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			// sessions can be revoked, so the id must still be known
+			sessionID, _ := session.Values["session_id"].(string)
+			if !s.sessionService.Validate(r.Context(), sessionID, userID) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			// look up the user on every request so role changes and deletes apply right away
+			user, err := s.userService.FindByID(r.Context(), userID)
+			if err != nil || user == nil {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), ctxKeyUser, user))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
 
+// RequireScope checks that requests made with an api key, or by a user, have access to the resource.
+// GET and HEAD requests need read access, everything else write access.
+func (s Server) RequireScope(resource string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			write := isWriteRequest(r)
+
+			if user, ok := r.Context().Value(ctxKeyUser).(*domain.User); ok && !user.Role.CanAccess(resource, write) {
+				encoder{}.StatusError(w, http.StatusForbidden, errors.New("user %q with role %v is not allowed to do this", user.Username, user.Role))
+				return
+			}
+
+			if key, ok := r.Context().Value(ctxKeyAPIKey).(*domain.APIKey); ok {
+				if !key.HasScope(resource, write) {
+					scope := resource
+					if resource != domain.APIKeyScopeAdmin {
+						scope = resource + ":read"
+						if write {
+							scope = resource + ":write"
+						}
+					}
+
+					encoder{}.StatusError(w, http.StatusForbidden, errors.New("api key %q is missing scope %v", key.Name, scope))
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// deprecatedWriteRoutes are GET routes kept for older api clients that change something
+var deprecatedWriteRoutes = []string{"/duplicate", "/restart"}
+
+// isWriteRequest reports if the request changes something and needs write access
+func isWriteRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return true
+	}
+
+	for _, suffix := range deprecatedWriteRoutes {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canAccess checks if the user or api key of the request may access the resource, for handlers
+// returning data from more than one resource
+func canAccess(r *http.Request, resource string, write bool) bool {
+	if user, ok := r.Context().Value(ctxKeyUser).(*domain.User); ok && !user.Role.CanAccess(resource, write) {
+		return false
+	}
+
+	if key, ok := r.Context().Value(ctxKeyAPIKey).(*domain.APIKey); ok && !key.HasScope(resource, write) {
+		return false
+	}
+
+	return true
+}
+
+// Audit records who made every successful change to the resource
+func (s Server) Audit(resource string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAuditedRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() >= http.StatusBadRequest {
+				return
+			}
+
+			event := &domain.AuditEvent{
+				Action:   r.Method,
+				Resource: resource,
+				Path:     r.URL.Path,
+			}
+
+			if user, ok := r.Context().Value(ctxKeyUser).(*domain.User); ok {
+				event.UserID = user.ID
+				event.Username = user.Username
+			} else if key, ok := r.Context().Value(ctxKeyAPIKey).(*domain.APIKey); ok {
+				event.Username = "api key: " + key.Name
+			} else {
+				return
+			}
+
+			s.auditService.Record(r.Context(), event)
+		})
+	}
+}
+
+// isAuditedRequest skips reads and the endpoints that only test or preview settings
+func isAuditedRequest(r *http.Request) bool {
+	if r.Method == http.MethodOptions || !isWriteRequest(r) {
+		return false
+	}
+
+	for _, suffix := range []string{"/test", "/dryrun", "/preview"} {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -65,7 +217,7 @@ func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handl
 					http.Error(ww, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 				}
 
-				if !strings.Contains("/api/healthz/liveness|/api/healthz/readiness", r.URL.Path) {
+				if !strings.Contains("/api/healthz/liveness|/api/healthz/readiness|/metrics", r.URL.Path) {
 					// log end request
 					log.Trace().
 						Str("type", "access").
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RequireScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		resource   string
+		method     string
+		path       string
+		key        *domain.APIKey
+		user       *domain.User
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "session",
+			resource:   "filters",
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "key_without_scopes",
+			resource:   "filters",
+			method:     http.MethodDelete,
+			key:        &domain.APIKey{Name: "legacy"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "read_scope_get",
+			resource:   "releases",
+			method:     http.MethodGet,
+			key:        &domain.APIKey{Name: "dashboard", Scopes: []string{"releases:read"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "read_scope_delete",
+			resource:   "releases",
+			method:     http.MethodDelete,
+			key:        &domain.APIKey{Name: "dashboard", Scopes: []string{"releases:read"}},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `api key \"dashboard\" is missing scope releases:write`,
+		},
+		{
+			name:       "other_resource",
+			resource:   "config",
+			method:     http.MethodGet,
+			key:        &domain.APIKey{Name: "dashboard", Scopes: []string{"releases:read"}},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `api key \"dashboard\" is missing scope config:read`,
+		},
+		{
+			name:       "admin_only",
+			resource:   domain.APIKeyScopeAdmin,
+			method:     http.MethodGet,
+			key:        &domain.APIKey{Name: "filters", Scopes: []string{"filters:write"}},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `api key \"filters\" is missing scope admin`,
+		},
+		{
+			name:       "viewer_get",
+			resource:   "filters",
+			method:     http.MethodGet,
+			user:       &domain.User{Username: "viewer", Role: domain.UserRoleViewer},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "viewer_update",
+			resource:   "filters",
+			method:     http.MethodPut,
+			user:       &domain.User{Username: "viewer", Role: domain.UserRoleViewer},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `user \"viewer\" with role viewer is not allowed to do this`,
+		},
+		{
+			name:       "viewer_deprecated_get_duplicate",
+			resource:   "filters",
+			method:     http.MethodGet,
+			path:       "/api/filters/1/duplicate",
+			user:       &domain.User{Username: "viewer", Role: domain.UserRoleViewer},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "read_scope_deprecated_get_restart",
+			resource:   "irc",
+			method:     http.MethodGet,
+			path:       "/api/irc/network/1/restart",
+			key:        &domain.APIKey{Name: "dashboard", Scopes: []string{"irc:read"}},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `api key \"dashboard\" is missing scope irc:write`,
+		},
+		{
+			name:       "editor_users",
+			resource:   domain.APIKeyScopeAdmin,
+			method:     http.MethodGet,
+			user:       &domain.User{Username: "editor", Role: domain.UserRoleEditor},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Server{}.RequireScope(tt.resource)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			path := tt.path
+			if path == "" {
+				path = "/api/" + tt.resource
+			}
+
+			req := httptest.NewRequest(tt.method, path, nil)
+			if tt.key != nil {
+				req = req.WithContext(context.WithValue(req.Context(), ctxKeyAPIKey, tt.key))
+			}
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), ctxKeyUser, tt.user))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				assert.Contains(t, rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ type notificationService interface {
 	Update(ctx context.Context, n domain.Notification) (*domain.Notification, error)
 	Delete(ctx context.Context, id int) error
 	Test(ctx context.Context, notification domain.Notification) error
+	Preview(ctx context.Context, notification domain.Notification) ([]domain.NotificationPreview, error)
 }
 
 type notificationHandler struct {
@@ -39,6 +40,7 @@ func (h notificationHandler) Routes(r chi.Router) {
 	r.Get("/", h.list)
 	r.Post("/", h.store)
 	r.Post("/test", h.test)
+	r.Post("/preview", h.preview)
 	r.Put("/{notificationID}", h.update)
 	r.Delete("/{notificationID}", h.delete)
 }
@@ -68,7 +70,7 @@ func (h notificationHandler) store(w http.ResponseWriter, r *http.Request) {
 
 	filter, err := h.service.Store(ctx, data)
 	if err != nil {
-		// encode error
+		h.encoder.Error(w, err)
 		return
 	}
 
@@ -88,7 +90,7 @@ func (h notificationHandler) update(w http.ResponseWriter, r *http.Request) {
 
 	filter, err := h.service.Update(ctx, data)
 	if err != nil {
-		// encode error
+		h.encoder.Error(w, err)
 		return
 	}
 
@@ -128,3 +130,23 @@ func (h notificationHandler) test(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+func (h notificationHandler) preview(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.Notification
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	previews, err := h.service.Preview(ctx, data)
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, previews)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// loginLimiter counts failed logins per key, like an ip or an ip and username,
+// and locks the key out for a while after too many failures.
+type loginLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	lockout     time.Duration
+	attempts    map[string]*loginAttempts
+
+	now func() time.Time
+}
+
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+func newLoginLimiter(maxAttempts int, lockout time.Duration) *loginLimiter {
+	return &loginLimiter{
+		maxAttempts: maxAttempts,
+		lockout:     lockout,
+		attempts:    make(map[string]*loginAttempts),
+		now:         time.Now,
+	}
+}
+
+func loginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginUserKey counts the failures for a username per ip, so guessing the password of a user
+// from one address does not lock that user out everywhere else
+func loginUserKey(ip string, username string) string {
+	return "user:" + ip + ":" + strings.ToLower(username)
+}
+
+// Locked returns how long the longest lockout of the keys has left
+func (l *loginLimiter) Locked(keys ...string) (time.Duration, bool) {
+	if l.maxAttempts <= 0 {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	var remaining time.Duration
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if !ok {
+			continue
+		}
+
+		if left := a.lockedUntil.Sub(now); left > remaining {
+			remaining = left
+		}
+	}
+
+	return remaining, remaining > 0
+}
+
+// Fail records a failed attempt for the keys and locks them out once they reach max attempts.
+// Failures older than the lockout duration are forgotten.
+func (l *loginLimiter) Fail(keys ...string) {
+	if l.maxAttempts <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if !ok || now.Sub(a.lastFailure) > l.lockout {
+			a = &loginAttempts{}
+			l.attempts[key] = a
+		}
+
+		a.failures++
+		a.lastFailure = now
+
+		if a.failures >= l.maxAttempts {
+			a.failures = 0
+			a.lockedUntil = now.Add(l.lockout)
+		}
+	}
+
+	l.cleanup(now)
+}
+
+// Reset forgets the failures of the keys after a successful login
+func (l *loginLimiter) Reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.attempts, key)
+	}
+}
+
+// cleanup drops keys that are neither locked nor have recent failures so the map does not grow forever
+func (l *loginLimiter) cleanup(now time.Time) {
+	if len(l.attempts) < 1000 {
+		return
+	}
+
+	for key, a := range l.attempts {
+		if now.After(a.lockedUntil) && now.Sub(a.lastFailure) > l.lockout {
+			delete(l.attempts, key)
+		}
+	}
+}
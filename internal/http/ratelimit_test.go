@@ -0,0 +1,157 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loginLimiter(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	l := newLoginLimiter(3, 15*time.Minute)
+	l.now = func() time.Time { return now }
+
+	ip := loginIPKey("10.0.0.1")
+	user := loginUserKey("10.0.0.1", "Alice")
+
+	l.Fail(ip, user)
+	l.Fail(ip, user)
+
+	_, locked := l.Locked(ip, user)
+	assert.False(t, locked)
+
+	l.Fail(ip, user)
+
+	remaining, locked := l.Locked(ip, user)
+	assert.True(t, locked)
+	assert.Equal(t, 15*time.Minute, remaining)
+
+	// the username is matched case insensitive
+	_, locked = l.Locked(loginUserKey("10.0.0.1", "alice"))
+	assert.True(t, locked)
+
+	// other ips and users are not affected
+	_, locked = l.Locked(loginIPKey("10.0.0.2"), loginUserKey("10.0.0.2", "bob"))
+	assert.False(t, locked)
+
+	// the user can still log in from another ip
+	_, locked = l.Locked(loginIPKey("10.0.0.2"), loginUserKey("10.0.0.2", "alice"))
+	assert.False(t, locked)
+
+	now = now.Add(16 * time.Minute)
+
+	_, locked = l.Locked(ip, user)
+	assert.False(t, locked)
+}
+
+func Test_loginLimiter_ForgetsOldFailures(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	l := newLoginLimiter(3, 15*time.Minute)
+	l.now = func() time.Time { return now }
+
+	key := loginUserKey("10.0.0.1", "alice")
+
+	l.Fail(key)
+	l.Fail(key)
+
+	now = now.Add(20 * time.Minute)
+
+	l.Fail(key)
+
+	_, locked := l.Locked(key)
+	assert.False(t, locked)
+}
+
+func Test_loginLimiter_Reset(t *testing.T) {
+	l := newLoginLimiter(2, 15*time.Minute)
+
+	key := loginUserKey("10.0.0.1", "alice")
+
+	l.Fail(key)
+	l.Reset(key)
+	l.Fail(key)
+
+	_, locked := l.Locked(key)
+	assert.False(t, locked)
+}
+
+func Test_loginLimiter_Disabled(t *testing.T) {
+	l := newLoginLimiter(0, 15*time.Minute)
+
+	key := loginUserKey("10.0.0.1", "alice")
+	for i := 0; i < 10; i++ {
+		l.Fail(key)
+	}
+
+	_, locked := l.Locked(key)
+	assert.False(t, locked)
+}
+
+func TestRealIP(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		realIP     string
+		forwarded  string
+		remoteAddr string
+		want       string
+	}{
+		{name: "real_ip", realIP: "1.2.3.4", forwarded: "5.6.7.8", remoteAddr: "127.0.0.1:5555", want: "1.2.3.4"},
+		{name: "forwarded_for", forwarded: "5.6.7.8, 10.0.0.1", remoteAddr: "127.0.0.1:5555", want: "5.6.7.8"},
+		{name: "forwarded_for_spoofed", forwarded: "1.1.1.1, 5.6.7.8", remoteAddr: "127.0.0.1:5555", want: "5.6.7.8"},
+		{name: "untrusted_real_ip", realIP: "1.2.3.4", remoteAddr: "192.168.1.2:5555", want: "192.168.1.2"},
+		{name: "untrusted_forwarded_for", forwarded: "5.6.7.8", remoteAddr: "192.168.1.2:5555", want: "192.168.1.2"},
+		{name: "remote_addr", remoteAddr: "127.0.0.1:5555", want: "127.0.0.1"},
+		{name: "remote_addr_ipv6", remoteAddr: "[::1]:5555", want: "::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-Ip", tt.realIP)
+			}
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			var got string
+			RealIP(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ReadUserIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"::1", " 172.16.0.0/12 "})
+	assert.NoError(t, err)
+	assert.True(t, isTrustedProxy(proxies, "::1"))
+	assert.True(t, isTrustedProxy(proxies, "172.20.0.5"))
+	assert.False(t, isTrustedProxy(proxies, "172.32.0.1"))
+
+	_, err = parseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
+
+func TestParseTrustedProxies_Default(t *testing.T) {
+	proxies, err := parseTrustedProxies(nil)
+	assert.NoError(t, err)
+	assert.True(t, isTrustedProxy(proxies, "127.0.0.1"))
+	assert.True(t, isTrustedProxy(proxies, "::1"))
+	assert.True(t, isTrustedProxy(proxies, "172.18.0.2"))
+	assert.True(t, isTrustedProxy(proxies, "192.168.1.10"))
+	assert.False(t, isTrustedProxy(proxies, "1.2.3.4"))
+}
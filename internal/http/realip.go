@@ -0,0 +1,106 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// defaultTrustedProxies are used when trustedProxies is not set, so a reverse proxy on the same host,
+// in docker or on the local network keeps working without extra config
+var defaultTrustedProxies = []string{"127.0.0.0/8", "::1/128", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"}
+
+// parseTrustedProxies parses the addresses and networks of the reverse proxies allowed to set the client address
+func parseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	if len(values) == 0 {
+		values = defaultTrustedProxies
+	}
+
+	proxies := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, errors.New("invalid trusted proxy address: %s", value)
+			}
+
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid trusted proxy network: %s", value)
+		}
+
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
+
+func isTrustedProxy(proxies []*net.IPNet, addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RealIP sets the remote address to the client address from X-Real-Ip or X-Forwarded-For, but only for requests
+// from a trusted proxy. Anyone else could pick the address that is logged and rate limited by setting the headers.
+func RealIP(proxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isTrustedProxy(proxies, ReadUserIP(r)) {
+				if ip := forwardedIP(proxies, r); ip != "" {
+					r.RemoteAddr = ip
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP returns the client address set by the trusted proxies. X-Forwarded-For is read from the right,
+// skipping the proxies, since the client can put anything at the start of it.
+func forwardedIP(proxies []*net.IPNet, r *http.Request) string {
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-Ip")); net.ParseIP(ip) != nil {
+		return ip
+	}
+
+	addrs := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(addrs[i])
+		if net.ParseIP(ip) == nil {
+			return ""
+		}
+
+		if i == 0 || !isTrustedProxy(proxies, ip) {
+			return ip
+		}
+	}
+
+	return ""
+}
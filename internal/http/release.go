@@ -5,11 +5,14 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
 	"github.com/go-chi/chi/v5"
 )
 
@@ -19,6 +22,13 @@ type releaseService interface {
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
 	Delete(ctx context.Context) error
+	Prune(ctx context.Context, rule domain.ReleasePruneRule) (int64, error)
+	FindDelayed(ctx context.Context) ([]domain.ReleaseDelay, error)
+	CancelDelayed(ctx context.Context, id int64) error
+	FindRetries(ctx context.Context) ([]domain.ReleaseActionRetry, error)
+	RetryNow(ctx context.Context, actionStatusID int64) error
+	RetryAction(ctx context.Context, releaseID int64, actionID int) (*domain.ReleaseActionStatus, error)
+	RetryActions(ctx context.Context, releaseID int64) ([]*domain.ReleaseActionStatus, error)
 }
 
 type releaseHandler struct {
@@ -39,6 +49,13 @@ func (h releaseHandler) Routes(r chi.Router) {
 	r.Get("/stats", h.getStats)
 	r.Get("/indexers", h.getIndexerOptions)
 	r.Delete("/all", h.deleteReleases)
+	r.Post("/prune", h.pruneReleases)
+	r.Get("/delayed", h.findDelayed)
+	r.Delete("/delayed/{delayID}", h.cancelDelayed)
+	r.Get("/retries", h.findRetries)
+	r.Post("/retries/{actionStatusID}/run", h.retryNow)
+	r.Post("/{releaseID}/actions/retry", h.retryActions)
+	r.Post("/{releaseID}/actions/{actionID}/retry", h.retryAction)
 }
 
 func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
@@ -186,3 +203,163 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+func (h releaseHandler) pruneReleases(w http.ResponseWriter, r *http.Request) {
+	var data domain.ReleasePruneRule
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// an empty body would delete every release, so pruning without days has to be asked for
+	if data.Days == 0 && !data.All {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("validation error: days is required, set all to prune releases of any age"))
+		return
+	}
+
+	deleted, err := h.service.Prune(r.Context(), data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, map[string]int64{"deleted": deleted})
+}
+
+func (h releaseHandler) findDelayed(w http.ResponseWriter, r *http.Request) {
+	delays, err := h.service.FindDelayed(r.Context())
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, delays)
+}
+
+func (h releaseHandler) cancelDelayed(w http.ResponseWriter, r *http.Request) {
+	delayID, err := strconv.ParseInt(chi.URLParam(r, "delayID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "delayID parameter is invalid",
+		})
+		return
+	}
+
+	if err := h.service.CancelDelayed(r.Context(), delayID); err != nil {
+		h.encoder.StatusNotFound(w)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) findRetries(w http.ResponseWriter, r *http.Request) {
+	retries, err := h.service.FindRetries(r.Context())
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, retries)
+}
+
+func (h releaseHandler) retryNow(w http.ResponseWriter, r *http.Request) {
+	actionStatusID, err := strconv.ParseInt(chi.URLParam(r, "actionStatusID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "actionStatusID parameter is invalid",
+		})
+		return
+	}
+
+	if err := h.service.RetryNow(r.Context(), actionStatusID); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) retryAction(w http.ResponseWriter, r *http.Request) {
+	releaseID, err := strconv.ParseInt(chi.URLParam(r, "releaseID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "releaseID parameter is invalid",
+		})
+		return
+	}
+
+	actionID, err := strconv.Atoi(chi.URLParam(r, "actionID"))
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "actionID parameter is invalid",
+		})
+		return
+	}
+
+	status, err := h.service.RetryAction(r.Context(), releaseID, actionID)
+	if err != nil && status == nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, status)
+}
+
+func (h releaseHandler) retryActions(w http.ResponseWriter, r *http.Request) {
+	releaseID, err := strconv.ParseInt(chi.URLParam(r, "releaseID"), 10, 64)
+	if err != nil {
+		h.encoder.StatusResponse(w, http.StatusBadRequest, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "releaseID parameter is invalid",
+		})
+		return
+	}
+
+	statuses, err := h.service.RetryActions(r.Context(), releaseID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			h.encoder.StatusNotFound(w)
+			return
+		}
+
+		h.encoder.StatusResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, statuses)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleaseService struct {
+	releaseService
+
+	retried []int64
+	err     error
+}
+
+func (s *mockReleaseService) RetryNow(ctx context.Context, actionStatusID int64) error {
+	s.retried = append(s.retried, actionStatusID)
+	return s.err
+}
+
+func TestReleaseHandler_RetryNow(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		err         error
+		wantStatus  int
+		wantRetried []int64
+	}{
+		{
+			name:        "ok",
+			path:        "/retries/42/run",
+			wantStatus:  http.StatusNoContent,
+			wantRetried: []int64{42},
+		},
+		{
+			name:        "not_found",
+			path:        "/retries/42/run",
+			err:         errors.Wrap(domain.ErrRecordNotFound, "no retry found for action status: 42"),
+			wantStatus:  http.StatusNotFound,
+			wantRetried: []int64{42},
+		},
+		{
+			name:        "push_failed",
+			path:        "/retries/42/run",
+			err:         errors.New("connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantRetried: []int64{42},
+		},
+		{
+			name:       "invalid_id",
+			path:       "/retries/abc/run",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockReleaseService{err: tt.err}
+
+			r := chi.NewRouter()
+			newReleaseHandler(encoder{}, service).Routes(r)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantRetried, service.retried)
+		})
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
+	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/web"
 
 	"github.com/go-chi/chi/v5"
@@ -26,8 +29,9 @@ type Server struct {
 	sse *sse.Server
 	db  *database.DB
 
-	config      *config.AppConfig
-	cookieStore *sessions.CookieStore
+	config       *config.AppConfig
+	cookieStore  *sessions.CookieStore
+	loginLimiter *loginLimiter
 
 	version string
 	commit  string
@@ -35,7 +39,9 @@ type Server struct {
 
 	actionService         actionService
 	apiService            apikeyService
+	auditService          auditService
 	authService           authService
+	backupService         backupService
 	downloadClientService downloadClientService
 	filterService         filterService
 	feedService           feedService
@@ -43,10 +49,12 @@ type Server struct {
 	ircService            ircService
 	notificationService   notificationService
 	releaseService        releaseService
+	sessionService        sessionService
 	updateService         updateService
+	userService           userService
 }
 
-func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, authService authService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, notificationSvc notificationService, releaseSvc releaseService, updateSvc updateService) Server {
+func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, auditSvc auditService, authService authService, backupSvc backupService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, notificationSvc notificationService, releaseSvc releaseService, sessionSvc sessionService, updateSvc updateService, userSvc userService) Server {
 	return Server{
 		log:     log.With().Str("module", "http").Logger(),
 		config:  config,
@@ -56,11 +64,14 @@ func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db
 		commit:  commit,
 		date:    date,
 
-		cookieStore: sessions.NewCookieStore([]byte(config.Config.SessionSecret)),
+		cookieStore:  sessions.NewCookieStore([]byte(config.Config.SessionSecret)),
+		loginLimiter: newLoginLimiter(config.Config.LoginMaxAttempts, time.Duration(config.Config.LoginLockoutMinutes)*time.Minute),
 
 		actionService:         actionService,
 		apiService:            apiService,
+		auditService:          auditSvc,
 		authService:           authService,
+		backupService:         backupSvc,
 		downloadClientService: downloadClientSvc,
 		filterService:         filterSvc,
 		feedService:           feedSvc,
@@ -68,7 +79,9 @@ func NewServer(log logger.Logger, config *config.AppConfig, sse *sse.Server, db
 		ircService:            ircSvc,
 		notificationService:   notificationSvc,
 		releaseService:        releaseSvc,
+		sessionService:        sessionSvc,
 		updateService:         updateSvc,
+		userService:           userSvc,
 	}
 }
 
@@ -92,7 +105,12 @@ func (s Server) Handler() http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	proxies, err := parseTrustedProxies(s.config.Config.TrustedProxies)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not parse trustedProxies, client addresses from proxy headers are ignored")
+	}
+
+	r.Use(RealIP(proxies))
 	r.Use(middleware.Recoverer)
 	r.Use(LoggerMiddleware(&s.log))
 
@@ -110,26 +128,28 @@ func (s Server) Handler() http.Handler {
 	encoder := encoder{}
 
 	r.Route("/api", func(r chi.Router) {
-		r.Route("/auth", newAuthHandler(encoder, s.log, s.config.Config, s.cookieStore, s.authService).Routes)
+		r.Route("/auth", newAuthHandler(encoder, s.log, s.config.Config, s.cookieStore, s.authService, s.sessionService, s.loginLimiter).Routes)
 		r.Route("/healthz", newHealthHandler(encoder, s.db).Routes)
 
 		r.Group(func(r chi.Router) {
 			r.Use(s.IsAuthenticated)
 
-			r.Route("/actions", newActionHandler(encoder, s.actionService).Routes)
-			r.Route("/config", newConfigHandler(encoder, s, s.config).Routes)
-			r.Route("/download_clients", newDownloadClientHandler(encoder, s.downloadClientService).Routes)
-			r.Route("/filters", newFilterHandler(encoder, s.filterService).Routes)
-			r.Route("/feeds", newFeedHandler(encoder, s.feedService).Routes)
-			r.Route("/irc", newIrcHandler(encoder, s.ircService).Routes)
-			r.Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService).Routes)
-			r.Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
-			r.Route("/logs", newLogsHandler(s.config).Routes)
-			r.Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
-			r.Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
-			r.Route("/updates", newUpdateHandler(encoder, s.updateService).Routes)
-
-			r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+			r.With(s.RequireScope("actions"), s.Audit("actions")).Route("/actions", newActionHandler(encoder, s.actionService).Routes)
+			r.With(s.RequireScope(domain.APIKeyScopeAdmin), s.Audit("backup")).Route("/backup", newBackupHandler(encoder, s.backupService, s.db).Routes)
+			r.With(s.RequireScope("config"), s.Audit("config")).Route("/config", newConfigHandler(encoder, s, s.config).Routes)
+			r.With(s.RequireScope("download_clients"), s.Audit("download_clients")).Route("/download_clients", newDownloadClientHandler(encoder, s.downloadClientService).Routes)
+			r.With(s.RequireScope("filters"), s.Audit("filters")).Route("/filters", newFilterHandler(encoder, s.filterService).Routes)
+			r.With(s.RequireScope("feeds"), s.Audit("feeds")).Route("/feeds", newFeedHandler(encoder, s.feedService).Routes)
+			r.With(s.RequireScope("irc"), s.Audit("irc")).Route("/irc", newIrcHandler(encoder, s.ircService).Routes)
+			r.With(s.RequireScope("indexers"), s.Audit("indexers")).Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService).Routes)
+			r.With(s.RequireScope(domain.APIKeyScopeAdmin), s.Audit("keys")).Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
+			r.With(s.RequireScope("logs")).Route("/logs", newLogsHandler(s.config).Routes)
+			r.With(s.RequireScope("notifications"), s.Audit("notifications")).Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
+			r.With(s.RequireScope("releases"), s.Audit("releases")).Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
+			r.With(s.RequireScope("updates")).Route("/updates", newUpdateHandler(encoder, s.updateService).Routes)
+			r.With(s.RequireScope(domain.APIKeyScopeAdmin), s.Audit("users")).Route("/users", newUserHandler(encoder, s.userService, s.auditService).Routes)
+
+			r.With(s.RequireScope("events")).HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
 
 				// inject CORS headers to bypass checks
 				s.sse.Headers = map[string]string{
@@ -144,6 +164,11 @@ func (s Server) Handler() http.Handler {
 		})
 	})
 
+	// prometheus metrics, at the root since that is where scrapers look by default
+	if s.config.Config.MetricsEnabled {
+		r.With(s.IsAuthenticated, s.RequireScope("metrics")).Handle("/metrics", metrics.Handler())
+	}
+
 	// serve the web
 	web.RegisterHandler(r, s.version, s.config.Config.BaseURL)
 
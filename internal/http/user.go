@@ -0,0 +1,142 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type userService interface {
+	FindByID(ctx context.Context, id int) (*domain.User, error)
+	List(ctx context.Context) ([]domain.User, error)
+	Create(ctx context.Context, req domain.CreateUserRequest) error
+	Update(ctx context.Context, req domain.UpdateUserRequest) error
+	Delete(ctx context.Context, id int) error
+}
+
+type auditService interface {
+	Record(ctx context.Context, event *domain.AuditEvent)
+	List(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEvent, error)
+}
+
+type userHandler struct {
+	encoder      encoder
+	service      userService
+	auditService auditService
+}
+
+func newUserHandler(encoder encoder, service userService, auditService auditService) *userHandler {
+	return &userHandler{
+		encoder:      encoder,
+		service:      service,
+		auditService: auditService,
+	}
+}
+
+func (h userHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+	r.Get("/audit", h.audit)
+	r.Put("/{userID}", h.update)
+	r.Delete("/{userID}", h.delete)
+}
+
+func (h userHandler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.List(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, users)
+}
+
+func (h userHandler) store(w http.ResponseWriter, r *http.Request) {
+	var data domain.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	if err := h.service.Create(r.Context(), data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.StatusCreated(w)
+}
+
+func (h userHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid user id"))
+		return
+	}
+
+	var data domain.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode json"))
+		return
+	}
+
+	data.ID = id
+
+	if err := h.service.Update(r.Context(), data); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h userHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid user id"))
+		return
+	}
+
+	if user, ok := r.Context().Value(ctxKeyUser).(*domain.User); ok && user.ID == id {
+		h.encoder.StatusError(w, http.StatusBadRequest, errors.New("can not delete your own user"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.encoder.StatusError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h userHandler) audit(w http.ResponseWriter, r *http.Request) {
+	params := domain.AuditQueryParams{
+		Resource: r.URL.Query().Get("resource"),
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			h.encoder.StatusError(w, http.StatusBadRequest, errors.Wrap(err, "invalid limit"))
+			return
+		}
+		params.Limit = l
+	}
+
+	events, err := h.auditService.List(r.Context(), params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(w, http.StatusOK, events)
+}
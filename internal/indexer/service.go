@@ -34,6 +34,7 @@ type Service interface {
 	LoadIndexerDefinitions() error
 	GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition
 	GetTorznabIndexers() []domain.IndexerDefinition
+	GetMappedDefinitionByName(name string) *domain.IndexerDefinition
 	Start() error
 	TestApi(ctx context.Context, req domain.IndexerTestApiRequest) error
 }
@@ -631,6 +632,10 @@ func (s *service) getMappedDefinitionByName(name string) *domain.IndexerDefiniti
 	return nil
 }
 
+func (s *service) GetMappedDefinitionByName(name string) *domain.IndexerDefinition {
+	return s.getMappedDefinitionByName(name)
+}
+
 func (s *service) stopFeed(indexer string) {
 	// verify indexer is torznab indexer
 	_, ok := s.torznabIndexers[indexer]
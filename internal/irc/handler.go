@@ -11,6 +11,7 @@ import (
 
 	"github.com/autobrr/autobrr/internal/announce"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
@@ -31,15 +32,40 @@ type channelHealth struct {
 	monitoring      bool
 	monitoringSince time.Time
 	lastAnnounce    time.Time
+	quietNotified   bool
 }
 
 // SetLastAnnounce set last announce to now
 func (ch *channelHealth) SetLastAnnounce() {
 	ch.m.Lock()
 	ch.lastAnnounce = time.Now()
+	ch.quietNotified = false
 	ch.m.Unlock()
 }
 
+// checkQuiet returns true the first time the channel has been monitored without announces for longer than the threshold
+func (ch *channelHealth) checkQuiet(threshold time.Duration) bool {
+	ch.m.Lock()
+	defer ch.m.Unlock()
+
+	if !ch.monitoring || ch.quietNotified {
+		return false
+	}
+
+	last := ch.lastAnnounce
+	if last.IsZero() {
+		last = ch.monitoringSince
+	}
+
+	if time.Since(last) < threshold {
+		return false
+	}
+
+	ch.quietNotified = true
+
+	return true
+}
+
 // SetMonitoring set monitoring and time
 func (ch *channelHealth) SetMonitoring() {
 	ch.m.Lock()
@@ -54,6 +80,7 @@ func (ch *channelHealth) resetMonitoring() {
 	ch.monitoring = false
 	ch.monitoringSince = time.Time{}
 	ch.lastAnnounce = time.Time{}
+	ch.quietNotified = false
 	ch.m.Unlock()
 }
 
@@ -292,6 +319,21 @@ func (h *Handler) AddChannelHealth(channel string) {
 	h.m.Unlock()
 }
 
+// QuietChannels returns the channels that went quiet for longer than the threshold since the last check
+func (h *Handler) QuietChannels(threshold time.Duration) []string {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	var channels []string
+	for name, ch := range h.channelHealth {
+		if ch.checkQuiet(threshold) {
+			channels = append(channels, name)
+		}
+	}
+
+	return channels
+}
+
 func (h *Handler) resetChannelHealth() {
 	for _, ch := range h.channelHealth {
 		ch.resetMonitoring()
@@ -342,6 +384,8 @@ func (h *Handler) onConnect(m ircmsg.Message) {
 		}
 		h.m.Unlock()
 
+		metrics.IRCConnected.WithLabelValues(h.network.Name).Set(1)
+
 		h.log.Debug().Msgf("connected to: %v", h.network.Name)
 	}()
 
@@ -359,6 +403,8 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 	// reset connectedSince
 	h.connectedSince = time.Time{}
 
+	metrics.IRCConnected.WithLabelValues(h.network.Name).Set(0)
+
 	// reset channelHealth
 	for _, ch := range h.channelHealth {
 		ch.resetMonitoring()
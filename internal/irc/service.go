@@ -5,6 +5,7 @@ package irc
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -12,10 +13,13 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/rs/zerolog"
 )
 
@@ -38,21 +42,27 @@ type service struct {
 	lock   sync.RWMutex
 
 	log                 zerolog.Logger
+	config              *domain.Config
+	bus                 EventBus.Bus
 	repo                domain.IrcRepo
 	releaseService      release.Service
 	indexerService      indexer.Service
 	notificationService notification.Service
+	scheduler           scheduler.Service
 	indexerMap          map[string]string
 	handlers            map[handlerKey]*Handler
 }
 
-func NewService(log logger.Logger, repo domain.IrcRepo, releaseSvc release.Service, indexerSvc indexer.Service, notificationSvc notification.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.IrcRepo, releaseSvc release.Service, indexerSvc indexer.Service, notificationSvc notification.Service, scheduler scheduler.Service, bus EventBus.Bus) Service {
 	return &service{
 		log:                 log.With().Str("module", "irc").Logger(),
+		config:              config,
+		bus:                 bus,
 		repo:                repo,
 		releaseService:      releaseSvc,
 		indexerService:      indexerSvc,
 		notificationService: notificationSvc,
+		scheduler:           scheduler,
 		handlers:            make(map[handlerKey]*Handler),
 	}
 }
@@ -102,9 +112,70 @@ func (s *service) StartHandlers() {
 			}
 		}(network)
 	}
+
+	s.scheduleQuietChannels()
+}
+
+const quietChannelsJobIdentifier = "irc-quiet-channels"
+
+type QuietChannelsJob struct {
+	Name      string
+	Log       zerolog.Logger
+	Threshold time.Duration
+	ircSvc    *service
+}
+
+func (j *QuietChannelsJob) Run() {
+	j.ircSvc.checkQuietChannels(j.Threshold)
+}
+
+// scheduleQuietChannels adds the job that periodically checks for channels without announces for longer than the quiet threshold
+func (s *service) scheduleQuietChannels() {
+	if s.config.IRCQuietThreshold <= 0 {
+		return
+	}
+
+	job := &QuietChannelsJob{
+		Name:      quietChannelsJobIdentifier,
+		Log:       s.log.With().Str("job", quietChannelsJobIdentifier).Logger(),
+		Threshold: time.Duration(s.config.IRCQuietThreshold) * time.Hour,
+		ircSvc:    s,
+	}
+
+	if _, err := s.scheduler.AddJob(job, 5*time.Minute, quietChannelsJobIdentifier); err != nil {
+		s.log.Error().Err(err).Msg("failed to add quiet channels job")
+	}
+}
+
+func (s *service) checkQuietChannels(threshold time.Duration) {
+	s.lock.RLock()
+	handlers := make([]*Handler, 0, len(s.handlers))
+	for _, handler := range s.handlers {
+		handlers = append(handlers, handler)
+	}
+	s.lock.RUnlock()
+
+	for _, handler := range handlers {
+		for _, channel := range handler.QuietChannels(threshold) {
+			s.log.Warn().Msgf("channel %v on network %v has not announced in %v", channel, handler.network.Name, threshold)
+
+			payload := &domain.NotificationPayload{
+				Subject:   "IRC Channel Quiet",
+				Message:   fmt.Sprintf("Network: %v Channel: %v has not announced in %v", handler.network.Name, channel, threshold),
+				Event:     domain.NotificationEventIRCChannelQuiet,
+				Timestamp: time.Now(),
+			}
+
+			s.bus.Publish("events:notification", &payload.Event, payload)
+		}
+	}
 }
 
 func (s *service) StopHandlers() {
+	if err := s.scheduler.RemoveJobByIdentifier(quietChannelsJobIdentifier); err != nil {
+		s.log.Error().Err(err).Msg("failed to remove quiet channels job")
+	}
+
 	for _, handler := range s.handlers {
 		s.log.Info().Msgf("stopping network: %+v", handler.network.Name)
 		handler.Stop()
@@ -326,6 +397,7 @@ func (s *service) StopAndRemoveNetwork(key handlerKey) error {
 
 		// remove from handlers
 		delete(s.handlers, key)
+		metrics.IRCConnected.DeleteLabelValues(handler.GetNetwork().Name)
 		s.log.Debug().Msgf("stopped network: %+v", key)
 	}
 
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the metrics served on /metrics, together with the go runtime and process metrics
+var Registry = newRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// AnnouncesTotal counts IRC announces per indexer, with result parsed or failed
+	AnnouncesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "autobrr_announces_total",
+		Help: "IRC announces processed per indexer, by parse result.",
+	}, []string{"indexer", "result"})
+
+	// FilterChecksTotal counts releases checked against a filter, with result match, rejected or duplicate
+	FilterChecksTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "autobrr_filter_checks_total",
+		Help: "Releases checked against filters, by result.",
+	}, []string{"filter", "result"})
+
+	// ActionPushesTotal counts releases pushed by actions, with the push status of the result
+	ActionPushesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "autobrr_action_pushes_total",
+		Help: "Releases pushed by actions, by action type, client and status.",
+	}, []string{"type", "client", "status"})
+
+	// FeedRunDuration measures feed job runs
+	FeedRunDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "autobrr_feed_run_duration_seconds",
+		Help:    "Duration of feed job runs.",
+		Buckets: []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"feed", "type"})
+
+	// FeedRunErrorsTotal counts failed feed job runs
+	FeedRunErrorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "autobrr_feed_run_errors_total",
+		Help: "Failed feed job runs.",
+	}, []string{"feed", "type"})
+
+	// IRCConnected is 1 while an IRC network is connected and 0 otherwise
+	IRCConnected = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "autobrr_irc_connected",
+		Help: "Whether the IRC network is connected.",
+	}, []string{"network"})
+
+	// TorrentDownloadDuration measures torrent file downloads per indexer, with result success or error
+	TorrentDownloadDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "autobrr_torrent_download_duration_seconds",
+		Help:    "Time to download torrent files, by indexer and result.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"indexer", "result"})
+)
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(collectors.NewGoCollector())
+	r.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	return r
+}
+
+// Handler serves the metrics for Prometheus to scrape
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveTorrentDownload observes a torrent file download started at start
+func ObserveTorrentDownload(indexer string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	TorrentDownloadDuration.WithLabelValues(indexer, result).Observe(time.Since(start).Seconds())
+}
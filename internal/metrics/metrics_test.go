@@ -0,0 +1,33 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveTorrentDownload(t *testing.T) {
+	ObserveTorrentDownload("mock", time.Now(), nil)
+	ObserveTorrentDownload("mock", time.Now(), errors.New("unexpected status: 500"))
+	ObserveTorrentDownload("mock", time.Now(), nil)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(TorrentDownloadDuration, "autobrr_torrent_download_duration_seconds"))
+}
+
+func TestHandler(t *testing.T) {
+	AnnouncesTotal.WithLabelValues("mock", "parsed").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), `autobrr_announces_total{indexer="mock",result="parsed"} 1`)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// ReleaseRepo is an in memory domain.ReleaseRepo for tests, safe to use from the delay and retry timers
+type ReleaseRepo struct {
+	m      sync.Mutex
+	nextID int64
+
+	Releases map[int64]domain.Release
+	Statuses map[int64]domain.ReleaseActionStatus
+	Delays   map[int64]domain.ReleaseDelay
+	Retries  map[int64]domain.ReleaseActionRetry
+}
+
+func NewReleaseRepo(releases ...domain.Release) *ReleaseRepo {
+	r := &ReleaseRepo{
+		Releases: map[int64]domain.Release{},
+		Statuses: map[int64]domain.ReleaseActionStatus{},
+		Delays:   map[int64]domain.ReleaseDelay{},
+		Retries:  map[int64]domain.ReleaseActionRetry{},
+	}
+
+	for _, rls := range releases {
+		if rls.ID > r.nextID {
+			r.nextID = rls.ID
+		}
+		r.Releases[rls.ID] = rls
+	}
+
+	return r
+}
+
+func (r *ReleaseRepo) id() int64 {
+	r.nextID++
+	return r.nextID
+}
+
+func (r *ReleaseRepo) Store(ctx context.Context, release *domain.Release) (*domain.Release, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if release.ID == 0 {
+		release.ID = r.id()
+	}
+	r.Releases[release.ID] = *release
+
+	return release, nil
+}
+
+func (r *ReleaseRepo) FindByID(ctx context.Context, id int64) (*domain.Release, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	rls, ok := r.Releases[id]
+	if !ok {
+		return nil, errors.Wrap(domain.ErrRecordNotFound, "release not found: %d", id)
+	}
+
+	return &rls, nil
+}
+
+func (r *ReleaseRepo) Find(ctx context.Context, params domain.ReleaseQueryParams) ([]*domain.Release, int64, int64, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	res := make([]*domain.Release, 0, len(r.Releases))
+	for _, rls := range r.Releases {
+		rls := rls
+		res = append(res, &rls)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID > res[j].ID })
+
+	return res, 0, int64(len(res)), nil
+}
+
+func (r *ReleaseRepo) FindRecent(ctx context.Context) ([]*domain.Release, error) {
+	res, _, _, err := r.Find(ctx, domain.ReleaseQueryParams{})
+	return res, err
+}
+
+func (r *ReleaseRepo) GetIndexerOptions(ctx context.Context) ([]string, error) {
+	return []string{}, nil
+}
+
+func (r *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, releaseID int64) ([]domain.ReleaseActionStatus, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var res []domain.ReleaseActionStatus
+	for _, s := range r.Statuses {
+		if s.ReleaseID == releaseID {
+			res = append(res, s)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+func (r *ReleaseRepo) Stats(ctx context.Context) (*domain.ReleaseStats, error) {
+	return &domain.ReleaseStats{}, nil
+}
+
+func (r *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, status *domain.ReleaseActionStatus) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if status.ID == 0 {
+		status.ID = r.id()
+	}
+	r.Statuses[status.ID] = *status
+
+	return nil
+}
+
+func (r *ReleaseRepo) Delete(ctx context.Context) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Releases = map[int64]domain.Release{}
+	r.Statuses = map[int64]domain.ReleaseActionStatus{}
+	r.Delays = map[int64]domain.ReleaseDelay{}
+	r.Retries = map[int64]domain.ReleaseActionRetry{}
+
+	return nil
+}
+
+func (r *ReleaseRepo) Prune(ctx context.Context, params domain.ReleasePruneParams) (int64, error) {
+	return 0, nil
+}
+
+func (r *ReleaseRepo) CanDownloadShow(ctx context.Context, release *domain.Release) (bool, error) {
+	return true, nil
+}
+
+func (r *ReleaseRepo) FindSameEpisode(ctx context.Context, release *domain.Release) ([]*domain.Release, error) {
+	return nil, nil
+}
+
+func (r *ReleaseRepo) StoreDelay(ctx context.Context, delay *domain.ReleaseDelay) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if delay.ID == 0 {
+		delay.ID = r.id()
+	}
+	r.Delays[delay.ID] = *delay
+
+	return nil
+}
+
+func (r *ReleaseRepo) FindDelays(ctx context.Context) ([]domain.ReleaseDelay, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	res := make([]domain.ReleaseDelay, 0, len(r.Delays))
+	for _, d := range r.Delays {
+		res = append(res, d)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].RunAt.Before(res[j].RunAt) })
+
+	return res, nil
+}
+
+func (r *ReleaseRepo) DeleteDelay(ctx context.Context, id int64) (bool, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.Delays[id]; !ok {
+		return false, nil
+	}
+	delete(r.Delays, id)
+
+	return true, nil
+}
+
+func (r *ReleaseRepo) FindDuplicates(ctx context.Context, params domain.ReleaseDuplicateParams) ([]*domain.Release, error) {
+	return nil, nil
+}
+
+func (r *ReleaseRepo) FindGrabbedSince(ctx context.Context, since time.Time) ([]*domain.Release, error) {
+	return nil, nil
+}
+
+func (r *ReleaseRepo) StoreRetry(ctx context.Context, retry *domain.ReleaseActionRetry) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if retry.ID == 0 {
+		retry.ID = r.id()
+	}
+	r.Retries[retry.ID] = *retry
+
+	return nil
+}
+
+func (r *ReleaseRepo) FindRetries(ctx context.Context) ([]domain.ReleaseActionRetry, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	res := make([]domain.ReleaseActionRetry, 0, len(r.Retries))
+	for _, retry := range r.Retries {
+		res = append(res, retry)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].NextRunAt.Before(res[j].NextRunAt) })
+
+	return res, nil
+}
+
+func (r *ReleaseRepo) FindRetryByActionStatusID(ctx context.Context, actionStatusID int64) (*domain.ReleaseActionRetry, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, retry := range r.Retries {
+		if retry.ActionStatusID == actionStatusID {
+			return &retry, nil
+		}
+	}
+
+	return nil, errors.Wrap(domain.ErrRecordNotFound, "no retry found for action status: %d", actionStatusID)
+}
+
+func (r *ReleaseRepo) DeleteRetry(ctx context.Context, id int64) (bool, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.Retries[id]; !ok {
+		return false, nil
+	}
+	delete(r.Retries, id)
+
+	return true, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// SessionRepo is an in memory domain.SessionRepo for tests
+type SessionRepo struct {
+	Sessions map[string]domain.Session
+}
+
+func NewSessionRepo(sessions ...domain.Session) *SessionRepo {
+	r := &SessionRepo{Sessions: map[string]domain.Session{}}
+	for _, s := range sessions {
+		r.Sessions[s.ID] = s
+	}
+
+	return r
+}
+
+func (r *SessionRepo) Store(ctx context.Context, session *domain.Session) error {
+	r.Sessions[session.ID] = *session
+	return nil
+}
+
+func (r *SessionRepo) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	s, ok := r.Sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *SessionRepo) ListByUser(ctx context.Context, userID int) ([]domain.Session, error) {
+	var res []domain.Session
+	for _, s := range r.Sessions {
+		if s.UserID == userID {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (r *SessionRepo) Touch(ctx context.Context, id string, lastSeen time.Time) error {
+	s := r.Sessions[id]
+	s.LastSeen = lastSeen
+	r.Sessions[id] = s
+	return nil
+}
+
+func (r *SessionRepo) Delete(ctx context.Context, id string) error {
+	delete(r.Sessions, id)
+	return nil
+}
+
+func (r *SessionRepo) DeleteByUser(ctx context.Context, userID int, except string) error {
+	for id, s := range r.Sessions {
+		if s.UserID == userID && id != except {
+			delete(r.Sessions, id)
+		}
+	}
+	return nil
+}
+
+func (r *SessionRepo) DeleteExpired(ctx context.Context, lastSeenBefore time.Time) error {
+	for id, s := range r.Sessions {
+		if s.LastSeen.Before(lastSeenBefore) {
+			delete(r.Sessions, id)
+		}
+	}
+	return nil
+}
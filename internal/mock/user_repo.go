@@ -0,0 +1,74 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package mock
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// UserRepo is an in memory domain.UserRepo for tests
+type UserRepo struct {
+	Users []domain.User
+}
+
+func (r *UserRepo) GetUserCount(ctx context.Context) (int, error) {
+	return len(r.Users), nil
+}
+
+func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	for _, u := range r.Users {
+		if u.Username == username {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserRepo) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	for _, u := range r.Users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserRepo) FindByOIDCSubject(ctx context.Context, subject string) (*domain.User, error) {
+	for _, u := range r.Users {
+		if subject != "" && u.OIDCSubject == subject {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *UserRepo) List(ctx context.Context) ([]domain.User, error) {
+	return r.Users, nil
+}
+
+func (r *UserRepo) Store(ctx context.Context, req domain.CreateUserRequest) error {
+	r.Users = append(r.Users, domain.User{ID: len(r.Users) + 1, Username: req.Username, Password: req.Password, Role: req.Role, OIDCSubject: req.OIDCSubject})
+	return nil
+}
+
+func (r *UserRepo) Update(ctx context.Context, user domain.User) error {
+	for i, u := range r.Users {
+		if u.ID == user.ID {
+			r.Users[i] = user
+		}
+	}
+	return nil
+}
+
+func (r *UserRepo) Delete(ctx context.Context, id int) error {
+	for i, u := range r.Users {
+		if u.ID == id {
+			r.Users = append(r.Users[:i], r.Users[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
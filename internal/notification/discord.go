@@ -145,9 +145,11 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		color = GREEN
 	case domain.NotificationEventPushRejected:
 		color = GRAY
-	case domain.NotificationEventPushError:
+	case domain.NotificationEventPushError, domain.NotificationEventPushRetryFailed:
 		color = RED
-	case domain.NotificationEventIRCDisconnected:
+	case domain.NotificationEventIRCDisconnected, domain.NotificationEventIRCChannelQuiet:
+		color = RED
+	case domain.NotificationEventFeedFailed, domain.NotificationEventClientError:
 		color = RED
 	case domain.NotificationEventIRCReconnected:
 		color = GREEN
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+type gotifySender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewGotifySender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &gotifySender{
+		log:      log.With().Str("sender", "gotify").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *gotifySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := gotifyMessage{
+		Title:    messageTitle(payload),
+		Message:  messageText(payload),
+		Priority: gotifyPriority(s.Settings.Priority),
+	}
+
+	header := http.Header{}
+	header.Set("X-Gotify-Key", s.Settings.Token)
+
+	body, err := sendJSON(http.MethodPost, strings.TrimSuffix(s.Settings.Host, "/")+"/message", m, header)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("gotify client request error: %v", event)
+		return errors.Wrap(err, "could not send message")
+	}
+
+	s.log.Trace().Msgf("gotify response: %v", string(body))
+
+	s.log.Debug().Msg("notification successfully sent to gotify")
+
+	return nil
+}
+
+func (s *gotifySender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *gotifySender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Host != "" && s.Settings.Token != "" {
+		return true
+	}
+	return false
+}
+
+func (s *gotifySender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gotifyPriority maps the -2 to 2 priority to the 0 to 10 gotify priority.
+// Gotify clients treat 0 as silent, 1-3 as low, 4-7 as normal and 8-10 as high.
+func gotifyPriority(priority int32) int {
+	switch {
+	case priority <= -2:
+		return 0
+	case priority == -1:
+		return 2
+	case priority == 0:
+		return 5
+	case priority == 1:
+		return 8
+	default:
+		return 10
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// iftttMessage holds the three values an ifttt webhook trigger accepts
+type iftttMessage struct {
+	Value1 string `json:"value1"`
+	Value2 string `json:"value2"`
+	Value3 string `json:"value3"`
+}
+
+type iftttSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	baseUrl  string
+}
+
+func NewIFTTTSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &iftttSender{
+		log:      log.With().Str("sender", "ifttt").Logger(),
+		Settings: settings,
+		baseUrl:  "https://maker.ifttt.com",
+	}
+}
+
+func (s *iftttSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := iftttMessage{
+		Value1: messageTitle(payload),
+		Value2: messageDescription(payload),
+		Value3: string(event),
+	}
+
+	// trigger the autobrr applet unless specific event names are set
+	targets := splitSetting(s.Settings.Targets)
+	if len(targets) == 0 {
+		targets = []string{"autobrr"}
+	}
+
+	for _, target := range targets {
+		u := fmt.Sprintf("%v/trigger/%v/with/key/%v", s.baseUrl, url.PathEscape(target), url.PathEscape(s.Settings.APIKey))
+
+		body, err := sendJSON(http.MethodPost, u, m, nil)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("ifttt client request error: %v", event)
+			return errors.Wrap(err, "could not trigger event: %v", target)
+		}
+
+		s.log.Trace().Msgf("ifttt response: %v", string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to ifttt")
+
+	return nil
+}
+
+func (s *iftttSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *iftttSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.APIKey != "" {
+		return true
+	}
+	return false
+}
+
+func (s *iftttSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
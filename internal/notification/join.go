@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type joinResponse struct {
+	Success      bool   `json:"success"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type joinSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	baseUrl  string
+}
+
+func NewJoinSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &joinSender{
+		log:      log.With().Str("sender", "join").Logger(),
+		Settings: settings,
+		baseUrl:  "https://joinjoaomgcd.appspot.com/_ah/api/messaging/v1/sendPush",
+	}
+}
+
+func (s *joinSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	// push to all devices unless specific devices are set
+	devices := splitSetting(s.Settings.Devices)
+	if len(devices) == 0 {
+		devices = []string{"group.all"}
+	}
+
+	data := url.Values{}
+	data.Set("apikey", s.Settings.APIKey)
+	data.Set("deviceIds", strings.Join(devices, ","))
+	data.Set("title", messageTitle(payload))
+	data.Set("text", messageText(payload))
+
+	if s.Settings.Icon != "" {
+		data.Set("icon", s.Settings.Icon)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.baseUrl+"?"+data.Encode(), nil)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("join client request error: %v", event)
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	client := http.Client{Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("join client request error: %v", event)
+		return errors.Wrap(err, "could not make request")
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("join client request error: %v", event)
+		return errors.Wrap(err, "could not read data")
+	}
+
+	s.log.Trace().Msgf("join status: %v response: %v", res.StatusCode, string(body))
+
+	if res.StatusCode != http.StatusOK {
+		s.log.Error().Msgf("join client request error: %v", string(body))
+		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	// join responds with 200 on errors too
+	var response joinResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return errors.Wrap(err, "could not unmarshal response")
+	}
+
+	if !response.Success {
+		return errors.New("join error: %v", response.ErrorMessage)
+	}
+
+	s.log.Debug().Msg("notification successfully sent to join")
+
+	return nil
+}
+
+func (s *joinSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *joinSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.APIKey != "" {
+		return true
+	}
+	return false
+}
+
+func (s *joinSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
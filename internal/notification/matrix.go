@@ -0,0 +1,104 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+type matrixSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewMatrixSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &matrixSender{
+		log:      log.With().Str("sender", "matrix").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *matrixSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := matrixMessage{
+		MsgType:       "m.text",
+		Body:          fmt.Sprintf("%v\n%v", messageTitle(payload), messageText(payload)),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: s.buildMessage(payload),
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+s.Settings.Token)
+
+	host := strings.TrimSuffix(s.Settings.Host, "/")
+
+	for i, room := range splitSetting(s.Settings.Rooms) {
+		// the transaction id makes retried requests idempotent, so it has to be unique per message
+		txnID := fmt.Sprintf("autobrr-%d-%d", time.Now().UnixNano(), i)
+
+		u := fmt.Sprintf("%v/_matrix/client/v3/rooms/%v/send/m.room.message/%v", host, url.PathEscape(room), txnID)
+
+		body, err := sendJSON(http.MethodPut, u, m, header)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("matrix client request error: %v", event)
+			return errors.Wrap(err, "could not send to room: %v", room)
+		}
+
+		s.log.Trace().Msgf("matrix response: %v", string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to matrix")
+
+	return nil
+}
+
+func (s *matrixSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *matrixSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Host != "" && s.Settings.Token != "" && s.Settings.Rooms != "" {
+		return true
+	}
+	return false
+}
+
+func (s *matrixSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *matrixSender) buildMessage(payload domain.NotificationPayload) string {
+	msg := fmt.Sprintf("<b>%v</b><br>%v", html.EscapeString(messageTitle(payload)), html.EscapeString(messageDescription(payload)))
+
+	for _, f := range messageFields(payload) {
+		msg += fmt.Sprintf("<br><b>%v:</b> %v", f.Name, html.EscapeString(f.Value))
+	}
+
+	return msg
+}
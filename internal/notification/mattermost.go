@@ -0,0 +1,78 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type mattermostMessage struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	IconURL     string            `json:"icon_url,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type mattermostSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewMattermostSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &mattermostSender{
+		log:      log.With().Str("sender", "mattermost").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *mattermostSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := mattermostMessage{
+		Text:        messageTitle(payload),
+		Channel:     s.Settings.Channel,
+		Username:    s.Settings.Username,
+		IconURL:     s.Settings.Icon,
+		Attachments: []slackAttachment{buildSlackAttachment(event, payload)},
+	}
+
+	body, err := sendJSON(http.MethodPost, s.Settings.Webhook, m, nil)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("mattermost client request error: %v", event)
+		return err
+	}
+
+	s.log.Trace().Msgf("mattermost response: %v", string(body))
+
+	s.log.Debug().Msg("notification successfully sent to mattermost")
+
+	return nil
+}
+
+func (s *mattermostSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *mattermostSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *mattermostSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
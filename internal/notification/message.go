@@ -0,0 +1,155 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// messageField is a named payload value rendered as a field or line by the senders
+type messageField struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// messageTitle returns the subject, or the release name for release events
+func messageTitle(payload domain.NotificationPayload) string {
+	if payload.Subject != "" && payload.Message != "" {
+		return payload.Subject
+	}
+
+	return payload.ReleaseName
+}
+
+// messageDescription returns the message, or a default for release events
+func messageDescription(payload domain.NotificationPayload) string {
+	if payload.Subject != "" && payload.Message != "" {
+		return payload.Message
+	}
+
+	return "New release!"
+}
+
+// messageFields returns the same fields the discord embeds render
+func messageFields(payload domain.NotificationPayload) []messageField {
+	var fields []messageField
+
+	if payload.Status != "" {
+		fields = append(fields, messageField{Name: "Status", Value: payload.Status.String(), Inline: true})
+	}
+	if payload.Indexer != "" {
+		fields = append(fields, messageField{Name: "Indexer", Value: payload.Indexer, Inline: true})
+	}
+	if payload.Filter != "" {
+		fields = append(fields, messageField{Name: "Filter", Value: payload.Filter, Inline: true})
+	}
+	if payload.Action != "" {
+		fields = append(fields, messageField{Name: "Action", Value: payload.Action, Inline: true})
+	}
+	if payload.ActionType != "" {
+		fields = append(fields, messageField{Name: "Action type", Value: string(payload.ActionType), Inline: true})
+	}
+	if payload.ActionClient != "" {
+		fields = append(fields, messageField{Name: "Action client", Value: payload.ActionClient, Inline: true})
+	}
+	if len(payload.Rejections) > 0 {
+		fields = append(fields, messageField{Name: "Reasons", Value: strings.Join(payload.Rejections, ", ")})
+	}
+
+	return fields
+}
+
+// messageText renders the payload as plain text for senders without rich formatting
+func messageText(payload domain.NotificationPayload) string {
+	lines := []string{messageDescription(payload)}
+
+	if payload.ReleaseName != "" && payload.ReleaseName != payload.Message {
+		lines = append(lines, fmt.Sprintf("New release: %v", payload.ReleaseName))
+	}
+
+	for _, f := range messageFields(payload) {
+		lines = append(lines, fmt.Sprintf("%v: %v", f.Name, f.Value))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// eventColor returns the discord embed color of the event as hex
+func eventColor(event domain.NotificationEvent) string {
+	color := LIGHT_BLUE
+	switch event {
+	case domain.NotificationEventPushApproved, domain.NotificationEventIRCReconnected:
+		color = GREEN
+	case domain.NotificationEventPushRejected:
+		color = GRAY
+	case domain.NotificationEventPushError, domain.NotificationEventPushRetryFailed, domain.NotificationEventIRCDisconnected:
+		color = RED
+	case domain.NotificationEventIRCChannelQuiet, domain.NotificationEventFeedFailed, domain.NotificationEventClientError:
+		color = RED
+	}
+
+	return fmt.Sprintf("#%06x", int(color))
+}
+
+// sendJSON sends the data as json and returns the response body, or an error for non 2xx responses
+func sendJSON(method string, url string, data interface{}, header http.Header) ([]byte, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal data: %+v", data)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create request")
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	client := http.Client{Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not make request: %v", url)
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read data")
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return body, errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// splitSetting splits a comma separated setting like rooms, targets or devices
+func splitSetting(setting string) []string {
+	var values []string
+
+	for _, v := range strings.Split(setting, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
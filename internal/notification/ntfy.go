@@ -0,0 +1,132 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type ntfySender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewNtfySender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &ntfySender{
+		log:      log.With().Str("sender", "ntfy").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *ntfySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := ntfyMessage{
+		Topic:    s.Settings.Channel,
+		Title:    messageTitle(payload),
+		Message:  messageText(payload),
+		Priority: ntfyPriority(s.Settings.Priority),
+		Tags:     ntfyTags(event),
+	}
+
+	header := http.Header{}
+	if s.Settings.Token != "" {
+		header.Set("Authorization", "Bearer "+s.Settings.Token)
+	} else if s.Settings.Username != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(s.Settings.Username + ":" + s.Settings.Password))
+		header.Set("Authorization", "Basic "+auth)
+	}
+
+	// json messages are published to the root url with the topic in the body
+	body, err := sendJSON(http.MethodPost, s.host(), m, header)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("ntfy client request error: %v", event)
+		return errors.Wrap(err, "could not publish to topic: %v", s.Settings.Channel)
+	}
+
+	s.log.Trace().Msgf("ntfy response: %v", string(body))
+
+	s.log.Debug().Msg("notification successfully sent to ntfy")
+
+	return nil
+}
+
+func (s *ntfySender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *ntfySender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Channel != "" {
+		return true
+	}
+	return false
+}
+
+func (s *ntfySender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// host returns the server url, defaulting to the public ntfy.sh instance
+func (s *ntfySender) host() string {
+	if s.Settings.Host == "" {
+		return "https://ntfy.sh"
+	}
+
+	return strings.TrimSuffix(s.Settings.Host, "/")
+}
+
+// ntfyPriority maps the -2 to 2 priority to the 1 (min) to 5 (max) ntfy priority
+func ntfyPriority(priority int32) int {
+	p := int(priority) + 3
+
+	if p < 1 {
+		return 1
+	} else if p > 5 {
+		return 5
+	}
+
+	return p
+}
+
+// ntfyTags returns an emoji tag for the event
+func ntfyTags(event domain.NotificationEvent) []string {
+	switch event {
+	case domain.NotificationEventPushApproved, domain.NotificationEventIRCReconnected:
+		return []string{"white_check_mark"}
+	case domain.NotificationEventPushRejected:
+		return []string{"no_entry_sign"}
+	case domain.NotificationEventPushError, domain.NotificationEventPushRetryFailed, domain.NotificationEventIRCDisconnected:
+		return []string{"warning"}
+	case domain.NotificationEventIRCChannelQuiet, domain.NotificationEventFeedFailed, domain.NotificationEventClientError:
+		return []string{"warning"}
+	case domain.NotificationEventDailyDigest:
+		return []string{"calendar"}
+	case domain.NotificationEventAppUpdateAvailable:
+		return []string{"tada"}
+	}
+
+	return nil
+}
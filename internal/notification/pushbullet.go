@@ -0,0 +1,93 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type pushBulletMessage struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Body       string `json:"body"`
+	DeviceIden string `json:"device_iden,omitempty"`
+	ChannelTag string `json:"channel_tag,omitempty"`
+}
+
+type pushBulletSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+	baseUrl  string
+}
+
+func NewPushBulletSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &pushBulletSender{
+		log:      log.With().Str("sender", "pushbullet").Logger(),
+		Settings: settings,
+		baseUrl:  "https://api.pushbullet.com/v2/pushes",
+	}
+}
+
+func (s *pushBulletSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := pushBulletMessage{
+		Type:       "note",
+		Title:      messageTitle(payload),
+		Body:       messageText(payload),
+		ChannelTag: s.Settings.Channel,
+	}
+
+	header := http.Header{}
+	header.Set("Access-Token", s.Settings.APIKey)
+
+	// push to all devices unless specific devices are set
+	devices := splitSetting(s.Settings.Devices)
+	if len(devices) == 0 {
+		devices = []string{""}
+	}
+
+	for _, device := range devices {
+		m.DeviceIden = device
+
+		body, err := sendJSON(http.MethodPost, s.baseUrl, m, header)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("pushbullet client request error: %v", event)
+			return errors.Wrap(err, "could not push to device: %v", device)
+		}
+
+		s.log.Trace().Msgf("pushbullet response: %v", string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to pushbullet")
+
+	return nil
+}
+
+func (s *pushBulletSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *pushBulletSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.APIKey != "" {
+		return true
+	}
+	return false
+}
+
+func (s *pushBulletSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
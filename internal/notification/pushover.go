@@ -49,7 +49,7 @@ func (s *pushoverSender) Send(event domain.NotificationEvent, payload domain.Not
 		User:      s.Settings.Token,
 		Priority:  s.Settings.Priority,
 		Message:   s.buildMessage(payload),
-		Title:     s.buildTitle(event),
+		Title:     s.buildTitle(event, payload),
 		Timestamp: time.Now(),
 		Html:      1,
 	}
@@ -142,8 +142,9 @@ func (s *pushoverSender) isEnabledEvent(event domain.NotificationEvent) bool {
 func (s *pushoverSender) buildMessage(payload domain.NotificationPayload) string {
 	msg := ""
 
-	if payload.Subject != "" && payload.Message != "" {
-		msg += fmt.Sprintf("%v\n<b>%v</b>", payload.Subject, html.EscapeString(payload.Message))
+	// the subject is sent as the title
+	if payload.Message != "" {
+		msg += fmt.Sprintf("<b>%v</b>", html.EscapeString(payload.Message))
 	}
 	if payload.ReleaseName != "" {
 		msg += fmt.Sprintf("\n<b>New release:</b> %v", html.EscapeString(payload.ReleaseName))
@@ -171,7 +172,11 @@ func (s *pushoverSender) buildMessage(payload domain.NotificationPayload) string
 	return msg
 }
 
-func (s *pushoverSender) buildTitle(event domain.NotificationEvent) string {
+func (s *pushoverSender) buildTitle(event domain.NotificationEvent, payload domain.NotificationPayload) string {
+	if payload.Subject != "" {
+		return payload.Subject
+	}
+
 	title := ""
 
 	switch event {
@@ -183,10 +188,20 @@ func (s *pushoverSender) buildTitle(event domain.NotificationEvent) string {
 		title = "Push Rejected"
 	case domain.NotificationEventPushError:
 		title = "Error"
+	case domain.NotificationEventPushRetryFailed:
+		title = "Push Retries Failed"
 	case domain.NotificationEventIRCDisconnected:
 		title = "IRC Disconnected"
 	case domain.NotificationEventIRCReconnected:
 		title = "IRC Reconnected"
+	case domain.NotificationEventIRCChannelQuiet:
+		title = "IRC Channel Quiet"
+	case domain.NotificationEventFeedFailed:
+		title = "Feed Failed"
+	case domain.NotificationEventClientError:
+		title = "Download Client Error"
+	case domain.NotificationEventDailyDigest:
+		title = "Daily Digest"
 	case domain.NotificationEventTest:
 		title = "Test"
 	}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type rocketChatMessage struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Alias       string            `json:"alias,omitempty"`
+	Avatar      string            `json:"avatar,omitempty"`
+	Emoji       string            `json:"emoji,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type rocketChatSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewRocketChatSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &rocketChatSender{
+		log:      log.With().Str("sender", "rocketchat").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *rocketChatSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := rocketChatMessage{
+		Text:        messageTitle(payload),
+		Channel:     s.Settings.Channel,
+		Alias:       s.Settings.Username,
+		Attachments: []slackAttachment{buildSlackAttachment(event, payload)},
+	}
+
+	// icons are either an emoji like :robot: or an avatar url
+	if strings.HasPrefix(s.Settings.Icon, ":") {
+		m.Emoji = s.Settings.Icon
+	} else {
+		m.Avatar = s.Settings.Icon
+	}
+
+	body, err := sendJSON(http.MethodPost, s.Settings.Webhook, m, nil)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("rocketchat client request error: %v", event)
+		return err
+	}
+
+	s.log.Trace().Msgf("rocketchat response: %v", string(body))
+
+	s.log.Debug().Msg("notification successfully sent to rocketchat")
+
+	return nil
+}
+
+func (s *rocketChatSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *rocketChatSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *rocketChatSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,501 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPayload = domain.NotificationPayload{
+	Subject:        "New release!",
+	Message:        "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+	Event:          domain.NotificationEventPushApproved,
+	ReleaseName:    "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+	Filter:         "TV",
+	Indexer:        "MockIndexer",
+	Status:         domain.ReleasePushStatusApproved,
+	Action:         "Send to qBittorrent",
+	ActionType:     domain.ActionTypeQbittorrent,
+	ActionClient:   "qBittorrent",
+	Protocol:       domain.ReleaseProtocolTorrent,
+	Implementation: domain.ReleaseImplementationIRC,
+	Timestamp:      time.Now(),
+}
+
+// testRequest is a request received by the stand-in server
+type testRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newTestServer starts a stand-in server recording the requests and responding with the status and body
+func newTestServer(t *testing.T, status int, response string) (*httptest.Server, *[]testRequest) {
+	t.Helper()
+
+	var requests []testRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := testRequest{
+			Method: r.Method,
+			Path:   r.URL.EscapedPath(),
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &req.Body); err != nil {
+				t.Errorf("invalid json body: %v", err)
+			}
+		}
+
+		requests = append(requests, req)
+
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestSlackSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, "ok")
+
+	sender := NewSlackSender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Webhook:  srv.URL + "/services/T000/B000/XXX",
+		Channel:  "#autobrr",
+		Username: "autobrr",
+		Icon:     ":robot_face:",
+		Events:   []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+	assert.False(t, sender.CanSend(domain.NotificationEventPushRejected))
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/services/T000/B000/XXX", req.Path)
+		assert.Equal(t, "#autobrr", req.Body["channel"])
+		assert.Equal(t, ":robot_face:", req.Body["icon_emoji"])
+		assert.Nil(t, req.Body["icon_url"])
+
+		attachment := req.Body["attachments"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#57f287", attachment["color"])
+		assert.Equal(t, "New release!", attachment["title"])
+		assert.Len(t, attachment["fields"], 6)
+	}
+}
+
+func TestSlackSender_Send_Error(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusNotFound, "no_service")
+
+	sender := NewSlackSender(zerolog.Nop(), domain.Notification{Enabled: true, Webhook: srv.URL})
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.ErrorContains(t, err, "no_service")
+}
+
+func TestMattermostSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, "ok")
+
+	sender := NewMattermostSender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Webhook:  srv.URL + "/hooks/xxx",
+		Username: "autobrr",
+		Icon:     "https://autobrr.com/logo.png",
+		Events:   []string{string(domain.NotificationEventPushError)},
+	})
+
+	payload := testPayload
+	payload.Event = domain.NotificationEventPushError
+	payload.Rejections = []string{"error pushing to client"}
+
+	err := sender.Send(domain.NotificationEventPushError, payload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, "/hooks/xxx", req.Path)
+		assert.Equal(t, "autobrr", req.Body["username"])
+		assert.Equal(t, "https://autobrr.com/logo.png", req.Body["icon_url"])
+
+		attachment := req.Body["attachments"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "#ed4245", attachment["color"])
+		assert.Len(t, attachment["fields"], 7)
+	}
+}
+
+func TestRocketChatSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"success":true}`)
+
+	sender := NewRocketChatSender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Webhook:  srv.URL + "/hooks/xxx/yyy",
+		Channel:  "#autobrr",
+		Username: "autobrr",
+		Icon:     "https://autobrr.com/logo.png",
+	})
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, "/hooks/xxx/yyy", req.Path)
+		assert.Equal(t, "autobrr", req.Body["alias"])
+		assert.Equal(t, "https://autobrr.com/logo.png", req.Body["avatar"])
+		assert.Len(t, req.Body["attachments"], 1)
+	}
+}
+
+func TestMatrixSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"event_id":"$abc"}`)
+
+	sender := NewMatrixSender(zerolog.Nop(), domain.Notification{
+		Enabled: true,
+		Host:    srv.URL + "/",
+		Token:   "secret",
+		Rooms:   "!room1:example.org, !room2:example.org",
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 2) {
+		req := (*requests)[0]
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.True(t, strings.HasPrefix(req.Path, "/_matrix/client/v3/rooms/%21room1:example.org/send/m.room.message/"), req.Path)
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "m.text", req.Body["msgtype"])
+		assert.Contains(t, req.Body["formatted_body"], "<b>Indexer:</b> MockIndexer")
+
+		assert.True(t, strings.HasPrefix((*requests)[1].Path, "/_matrix/client/v3/rooms/%21room2:example.org/"))
+		assert.NotEqual(t, req.Path, (*requests)[1].Path)
+	}
+}
+
+func TestMatrixSender_CanSend(t *testing.T) {
+	sender := NewMatrixSender(zerolog.Nop(), domain.Notification{
+		Enabled: true,
+		Host:    "https://matrix.org",
+		Token:   "secret",
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.False(t, sender.CanSend(domain.NotificationEventPushApproved), "no rooms")
+}
+
+func TestPushBulletSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"active":true}`)
+
+	sender := &pushBulletSender{
+		log:      zerolog.Nop(),
+		Settings: domain.Notification{Enabled: true, APIKey: "secret", Devices: "dev1,dev2"},
+		baseUrl:  srv.URL + "/v2/pushes",
+	}
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 2) {
+		req := (*requests)[0]
+		assert.Equal(t, "secret", req.Header.Get("Access-Token"))
+		assert.Equal(t, "note", req.Body["type"])
+		assert.Equal(t, "dev1", req.Body["device_iden"])
+		assert.Equal(t, "dev2", (*requests)[1].Body["device_iden"])
+	}
+}
+
+func TestIFTTTSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, "Congratulations!")
+
+	sender := &iftttSender{
+		log:      zerolog.Nop(),
+		Settings: domain.Notification{Enabled: true, APIKey: "secret"},
+		baseUrl:  srv.URL,
+	}
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, "/trigger/autobrr/with/key/secret", req.Path)
+		assert.Equal(t, "New release!", req.Body["value1"])
+		assert.Equal(t, "PUSH_APPROVED", req.Body["value3"])
+	}
+}
+
+func TestJoinSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"success":true}`)
+
+	sender := &joinSender{
+		log:      zerolog.Nop(),
+		Settings: domain.Notification{Enabled: true, APIKey: "secret"},
+		baseUrl:  srv.URL + "/sendPush",
+	}
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Contains(t, req.Query, "apikey=secret")
+		assert.Contains(t, req.Query, "deviceIds=group.all")
+	}
+}
+
+func TestJoinSender_Send_Error(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK, `{"success":false,"errorMessage":"invalid api key"}`)
+
+	sender := &joinSender{
+		log:      zerolog.Nop(),
+		Settings: domain.Notification{Enabled: true, APIKey: "secret"},
+		baseUrl:  srv.URL,
+	}
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.ErrorContains(t, err, "invalid api key")
+}
+
+func TestNtfySender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"id":"abc"}`)
+
+	sender := NewNtfySender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Host:     srv.URL + "/",
+		Channel:  "autobrr",
+		Username: "user",
+		Password: "pass",
+		Priority: 1,
+		Events:   []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/", req.Path)
+		assert.Equal(t, "Basic dXNlcjpwYXNz", req.Header.Get("Authorization"))
+		assert.Equal(t, "autobrr", req.Body["topic"])
+		assert.Equal(t, "New release!", req.Body["title"])
+		assert.Equal(t, float64(4), req.Body["priority"])
+		assert.Equal(t, []interface{}{"white_check_mark"}, req.Body["tags"])
+	}
+}
+
+func TestNtfySender_Send_Token(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"id":"abc"}`)
+
+	sender := NewNtfySender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Host:     srv.URL,
+		Channel:  "autobrr",
+		Token:    "tk_secret",
+		Username: "user",
+	})
+
+	err := sender.Send(domain.NotificationEventPushError, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		assert.Equal(t, "Bearer tk_secret", (*requests)[0].Header.Get("Authorization"))
+	}
+}
+
+func TestNtfySender_CanSend(t *testing.T) {
+	sender := NewNtfySender(zerolog.Nop(), domain.Notification{
+		Enabled: true,
+		Events:  []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.False(t, sender.CanSend(domain.NotificationEventPushApproved), "no topic")
+}
+
+func Test_ntfyPriority(t *testing.T) {
+	tests := []struct {
+		priority int32
+		want     int
+	}{
+		{priority: -5, want: 1},
+		{priority: -2, want: 1},
+		{priority: -1, want: 2},
+		{priority: 0, want: 3},
+		{priority: 1, want: 4},
+		{priority: 2, want: 5},
+		{priority: 5, want: 5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ntfyPriority(tt.priority), tt.priority)
+	}
+}
+
+func TestGotifySender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"id":1}`)
+
+	sender := NewGotifySender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Host:     srv.URL + "/",
+		Token:    "secret",
+		Priority: 2,
+		Events:   []string{string(domain.NotificationEventPushApproved)},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		req := (*requests)[0]
+		assert.Equal(t, "/message", req.Path)
+		assert.Equal(t, "secret", req.Header.Get("X-Gotify-Key"))
+		assert.Equal(t, "New release!", req.Body["title"])
+		assert.Equal(t, float64(10), req.Body["priority"])
+	}
+}
+
+func TestGotifySender_Send_Error(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusUnauthorized, `{"error":"Unauthorized"}`)
+
+	sender := NewGotifySender(zerolog.Nop(), domain.Notification{Enabled: true, Host: srv.URL, Token: "wrong"})
+
+	err := sender.Send(domain.NotificationEventPushApproved, testPayload)
+	assert.ErrorContains(t, err, "Unauthorized")
+}
+
+func Test_gotifyPriority(t *testing.T) {
+	tests := []struct {
+		priority int32
+		want     int
+	}{
+		{priority: -3, want: 0},
+		{priority: -2, want: 0},
+		{priority: -1, want: 2},
+		{priority: 0, want: 5},
+		{priority: 1, want: 8},
+		{priority: 2, want: 10},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, gotifyPriority(tt.priority), tt.priority)
+	}
+}
+
+func Test_service_Test(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"id":"abc"}`)
+
+	s := &service{log: zerolog.Nop()}
+
+	err := s.Test(context.Background(), domain.Notification{
+		Type:    domain.NotificationTypeNtfy,
+		Enabled: true,
+		Host:    srv.URL,
+		Channel: "autobrr",
+		Events:  []string{string(domain.NotificationEventPushRejected)},
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 1) {
+		assert.Contains(t, (*requests)[0].Body["message"], "Unknown Series")
+	}
+
+	err = s.Test(context.Background(), domain.Notification{Type: "UNKNOWN"})
+	assert.Error(t, err)
+}
+
+func Test_newSender(t *testing.T) {
+	types := []domain.NotificationType{
+		domain.NotificationTypeDiscord,
+		domain.NotificationTypeNotifiarr,
+		domain.NotificationTypeTelegram,
+		domain.NotificationTypePushover,
+		domain.NotificationTypeSlack,
+		domain.NotificationTypeMattermost,
+		domain.NotificationTypeRocketChat,
+		domain.NotificationTypeMatrix,
+		domain.NotificationTypePushBullet,
+		domain.NotificationTypeIFTTT,
+		domain.NotificationTypeJoin,
+		domain.NotificationTypeNtfy,
+		domain.NotificationTypeGotify,
+	}
+
+	for _, typ := range types {
+		assert.NotNil(t, newSender(zerolog.Nop(), domain.Notification{Type: typ}), typ)
+	}
+
+	assert.Nil(t, newSender(zerolog.Nop(), domain.Notification{Type: "UNKNOWN"}))
+}
+
+// recordSender records the payloads it is asked to send
+type recordSender struct {
+	sent chan domain.NotificationPayload
+}
+
+func (s *recordSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	s.sent <- payload
+	return nil
+}
+
+func (s *recordSender) CanSend(event domain.NotificationEvent) bool {
+	return true
+}
+
+func Test_service_Send_Scope(t *testing.T) {
+	tv := &recordSender{sent: make(chan domain.NotificationPayload, 1)}
+	music := &recordSender{sent: make(chan domain.NotificationPayload, 1)}
+
+	s := &service{
+		log: zerolog.Nop(),
+		senders: []registeredSender{
+			{notification: domain.Notification{Name: "music", Filters: []int{2}, Indexers: []string{"red", "ops"}}, sender: music},
+			{notification: domain.Notification{Name: "tv", Filters: []int{1}}, sender: tv},
+		},
+	}
+
+	payload := testPayload
+	payload.FilterID = 1
+
+	s.Send(domain.NotificationEventPushApproved, payload)
+
+	select {
+	case p := <-tv.sent:
+		assert.Equal(t, payload.ReleaseName, p.ReleaseName)
+	case <-time.After(time.Second):
+		t.Fatal("expected notification for filter 1")
+	}
+
+	// senders are handled in order, so music has been skipped before tv was sent to
+	assert.Len(t, music.sent, 0)
+}
@@ -24,19 +24,26 @@ type Service interface {
 	Delete(ctx context.Context, id int) error
 	Send(event domain.NotificationEvent, payload domain.NotificationPayload)
 	Test(ctx context.Context, notification domain.Notification) error
+	Preview(ctx context.Context, notification domain.Notification) ([]domain.NotificationPreview, error)
 }
 
 type service struct {
 	log     zerolog.Logger
 	repo    domain.NotificationRepo
-	senders []domain.NotificationSender
+	senders []registeredSender
+}
+
+// registeredSender is an enabled sender with the notification it was created from, used to route payloads
+type registeredSender struct {
+	notification domain.Notification
+	sender       domain.NotificationSender
 }
 
 func NewService(log logger.Logger, repo domain.NotificationRepo) Service {
 	s := &service{
 		log:     log.With().Str("module", "notification").Logger(),
 		repo:    repo,
-		senders: []domain.NotificationSender{},
+		senders: []registeredSender{},
 	}
 
 	s.registerSenders()
@@ -65,6 +72,11 @@ func (s *service) FindByID(ctx context.Context, id int) (*domain.Notification, e
 }
 
 func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
+	if err := n.Templates.Validate(); err != nil {
+		s.log.Error().Err(err).Msgf("invalid notification templates: %v", n.Name)
+		return nil, err
+	}
+
 	_, err := s.repo.Store(ctx, n)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not store notification: %+v", n)
@@ -72,7 +84,7 @@ func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Not
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -81,6 +93,11 @@ func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Not
 }
 
 func (s *service) Update(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
+	if err := n.Templates.Validate(); err != nil {
+		s.log.Error().Err(err).Msgf("invalid notification templates: %v", n.Name)
+		return nil, err
+	}
+
 	_, err := s.repo.Update(ctx, n)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not update notification: %+v", n)
@@ -88,7 +105,7 @@ func (s *service) Update(ctx context.Context, n domain.Notification) (*domain.No
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -104,7 +121,7 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	}
 
 	// reset senders
-	s.senders = []domain.NotificationSender{}
+	s.senders = []registeredSender{}
 
 	// re register senders
 	s.registerSenders()
@@ -121,15 +138,8 @@ func (s *service) registerSenders() {
 
 	for _, n := range senders {
 		if n.Enabled {
-			switch n.Type {
-			case domain.NotificationTypeDiscord:
-				s.senders = append(s.senders, NewDiscordSender(s.log, n))
-			case domain.NotificationTypeNotifiarr:
-				s.senders = append(s.senders, NewNotifiarrSender(s.log, n))
-			case domain.NotificationTypeTelegram:
-				s.senders = append(s.senders, NewTelegramSender(s.log, n))
-			case domain.NotificationTypePushover:
-				s.senders = append(s.senders, NewPushoverSender(s.log, n))
+			if sender := newSender(s.log, n); sender != nil {
+				s.senders = append(s.senders, registeredSender{notification: n, sender: sender})
 			}
 		}
 	}
@@ -137,6 +147,50 @@ func (s *service) registerSenders() {
 	return
 }
 
+// newSender creates the sender for the notification type, or nil if the type is not supported.
+// Senders of notifications with templates are wrapped to render them.
+func newSender(log zerolog.Logger, n domain.Notification) domain.NotificationSender {
+	sender := newTypeSender(log, n)
+	if sender != nil && len(n.Templates) > 0 {
+		return newTemplateSender(log, sender, n.Templates)
+	}
+
+	return sender
+}
+
+func newTypeSender(log zerolog.Logger, n domain.Notification) domain.NotificationSender {
+	switch n.Type {
+	case domain.NotificationTypeDiscord:
+		return NewDiscordSender(log, n)
+	case domain.NotificationTypeNotifiarr:
+		return NewNotifiarrSender(log, n)
+	case domain.NotificationTypeTelegram:
+		return NewTelegramSender(log, n)
+	case domain.NotificationTypePushover:
+		return NewPushoverSender(log, n)
+	case domain.NotificationTypeSlack:
+		return NewSlackSender(log, n)
+	case domain.NotificationTypeMattermost:
+		return NewMattermostSender(log, n)
+	case domain.NotificationTypeRocketChat:
+		return NewRocketChatSender(log, n)
+	case domain.NotificationTypeMatrix:
+		return NewMatrixSender(log, n)
+	case domain.NotificationTypePushBullet:
+		return NewPushBulletSender(log, n)
+	case domain.NotificationTypeIFTTT:
+		return NewIFTTTSender(log, n)
+	case domain.NotificationTypeJoin:
+		return NewJoinSender(log, n)
+	case domain.NotificationTypeNtfy:
+		return NewNtfySender(log, n)
+	case domain.NotificationTypeGotify:
+		return NewGotifySender(log, n)
+	}
+
+	return nil
+}
+
 // Send notifications
 func (s *service) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
 	if len(s.senders) > 0 {
@@ -144,11 +198,18 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 	}
 
 	go func() {
-		for _, sender := range s.senders {
+		for _, r := range s.senders {
 			// check if sender is active and have notification types
-			if sender.CanSend(event) {
-				sender.Send(event, payload)
+			if !r.sender.CanSend(event) {
+				continue
+			}
+
+			// check if the notification is limited to other filters or indexers
+			if !r.notification.InScope(payload) {
+				continue
 			}
+
+			r.sender.Send(event, payload)
 		}
 	}()
 
@@ -158,8 +219,52 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 func (s *service) Test(ctx context.Context, notification domain.Notification) error {
 	var agent domain.NotificationSender
 
-	// send test events
-	events := []domain.NotificationPayload{
+	events := testPayloads()
+
+	agent = newSender(s.log, notification)
+	if agent == nil {
+		s.log.Error().Msgf("unsupported notification type: %v", notification.Type)
+		return errors.New("unsupported notification type")
+	}
+
+	g, _ := errgroup.WithContext(ctx)
+
+	for _, event := range events {
+		e := event
+
+		if !enabledEvent(notification.Events, e.Event) {
+			continue
+		}
+
+		if err := agent.Send(e.Event, e); err != nil {
+			s.log.Error().Err(err).Msgf("error sending test notification: %#v", notification)
+			return err
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	if err := g.Wait(); err != nil {
+		s.log.Error().Err(err).Msgf("Something went wrong sending test notifications to %v", notification.Type)
+		return err
+	}
+
+	return nil
+}
+
+func enabledEvent(events []string, e domain.NotificationEvent) bool {
+	for _, v := range events {
+		if v == string(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// testPayloads returns sample payloads for each event, used to send test notifications and preview templates
+func testPayloads() []domain.NotificationPayload {
+	return []domain.NotificationPayload{
 		{
 			Subject:   "Test Notification",
 			Message:   "autobrr goes brr!!",
@@ -173,6 +278,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			ReleaseName:    "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
 			Filter:         "TV",
 			Indexer:        "MockIndexer",
+			Size:           2254857830,
 			Status:         domain.ReleasePushStatusApproved,
 			Action:         "Send to qBittorrent",
 			ActionType:     domain.ActionTypeQbittorrent,
@@ -189,6 +295,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			ReleaseName:    "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
 			Filter:         "TV",
 			Indexer:        "MockIndexer",
+			Size:           2254857830,
 			Status:         domain.ReleasePushStatusRejected,
 			Action:         "Send to Sonarr",
 			ActionType:     domain.ActionTypeSonarr,
@@ -205,6 +312,24 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			ReleaseName:    "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
 			Filter:         "TV",
 			Indexer:        "MockIndexer",
+			Size:           2254857830,
+			Status:         domain.ReleasePushStatusErr,
+			Action:         "Send to Sonarr",
+			ActionType:     domain.ActionTypeSonarr,
+			ActionClient:   "Sonarr",
+			Rejections:     []string{"error pushing to client"},
+			Protocol:       domain.ReleaseProtocolTorrent,
+			Implementation: domain.ReleaseImplementationIRC,
+			Timestamp:      time.Now(),
+		},
+		{
+			Subject:        "Push retries failed",
+			Message:        "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+			Event:          domain.NotificationEventPushRetryFailed,
+			ReleaseName:    "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+			Filter:         "TV",
+			Indexer:        "MockIndexer",
+			Size:           2254857830,
 			Status:         domain.ReleasePushStatusErr,
 			Action:         "Send to Sonarr",
 			ActionType:     domain.ActionTypeSonarr,
@@ -232,53 +357,41 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Event:     domain.NotificationEventAppUpdateAvailable,
 			Timestamp: time.Now(),
 		},
+		{
+			Subject:   "IRC Channel Quiet",
+			Message:   "Network: P2P-Network Channel: #announce has not announced in 12h0m0s",
+			Event:     domain.NotificationEventIRCChannelQuiet,
+			Timestamp: time.Now(),
+		},
+		{
+			Subject:   "Feed failed",
+			Message:   "Feed: MockIndexer Torznab failed 3 times in a row: bad status: 503",
+			Event:     domain.NotificationEventFeedFailed,
+			Indexer:   "MockIndexer",
+			Timestamp: time.Now(),
+		},
+		{
+			Subject:      "Download client error",
+			Message:      "Client: qBittorrent could not be reached: connection refused",
+			Event:        domain.NotificationEventClientError,
+			Indexer:      "MockIndexer",
+			Filter:       "TV",
+			ActionClient: "qBittorrent",
+			Timestamp:    time.Now(),
+		},
+		{
+			Subject:   "Daily digest",
+			Message:   "2 releases grabbed in the last 24 hours\nBest.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP\nBest.Show.Ever.S18E22.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+			Event:     domain.NotificationEventDailyDigest,
+			Timestamp: time.Now(),
+		},
 	}
-
-	switch notification.Type {
-	case domain.NotificationTypeDiscord:
-		agent = NewDiscordSender(s.log, notification)
-	case domain.NotificationTypeNotifiarr:
-		agent = NewNotifiarrSender(s.log, notification)
-	case domain.NotificationTypeTelegram:
-		agent = NewTelegramSender(s.log, notification)
-	case domain.NotificationTypePushover:
-		agent = NewPushoverSender(s.log, notification)
-	default:
-		s.log.Error().Msgf("unsupported notification type: %v", notification.Type)
-		return errors.New("unsupported notification type")
-	}
-
-	g, _ := errgroup.WithContext(ctx)
-
-	for _, event := range events {
-		e := event
-
-		if !enabledEvent(notification.Events, e.Event) {
-			continue
-		}
-
-		if err := agent.Send(e.Event, e); err != nil {
-			s.log.Error().Err(err).Msgf("error sending test notification: %#v", notification)
-			return err
-		}
-
-		time.Sleep(1 * time.Second)
-	}
-
-	if err := g.Wait(); err != nil {
-		s.log.Error().Err(err).Msgf("Something went wrong sending test notifications to %v", notification.Type)
-		return err
-	}
-
-	return nil
 }
 
-func enabledEvent(events []string, e domain.NotificationEvent) bool {
-	for _, v := range events {
-		if v == string(e) {
-			return true
-		}
+func (s *service) Preview(ctx context.Context, notification domain.Notification) ([]domain.NotificationPreview, error) {
+	if err := notification.Templates.Validate(); err != nil {
+		return nil, err
 	}
 
-	return false
+	return previewTemplates(notification.Templates, testPayloads()), nil
 }
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	IconURL     string            `json:"icon_url,omitempty"`
+	IconEmoji   string            `json:"icon_emoji,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+// slackAttachment is the legacy slack attachment also supported by mattermost and rocket.chat webhooks
+type slackAttachment struct {
+	Fallback  string                 `json:"fallback,omitempty"`
+	Color     string                 `json:"color"`
+	Title     string                 `json:"title"`
+	Text      string                 `json:"text"`
+	Fields    []slackAttachmentField `json:"fields,omitempty"`
+	Timestamp int64                  `json:"ts,omitempty"`
+}
+
+type slackAttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+type slackSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewSlackSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &slackSender{
+		log:      log.With().Str("sender", "slack").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *slackSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := slackMessage{
+		Text:        messageTitle(payload),
+		Channel:     s.Settings.Channel,
+		Username:    s.Settings.Username,
+		Attachments: []slackAttachment{buildSlackAttachment(event, payload)},
+	}
+
+	// icons are either an emoji like :robot_face: or an image url
+	if strings.HasPrefix(s.Settings.Icon, ":") {
+		m.IconEmoji = s.Settings.Icon
+	} else {
+		m.IconURL = s.Settings.Icon
+	}
+
+	body, err := sendJSON(http.MethodPost, s.Settings.Webhook, m, nil)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("slack client request error: %v", event)
+		return err
+	}
+
+	s.log.Trace().Msgf("slack response: %v", string(body))
+
+	s.log.Debug().Msg("notification successfully sent to slack")
+
+	return nil
+}
+
+func (s *slackSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *slackSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Webhook != "" {
+		return true
+	}
+	return false
+}
+
+func (s *slackSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func buildSlackAttachment(event domain.NotificationEvent, payload domain.NotificationPayload) slackAttachment {
+	attachment := slackAttachment{
+		Fallback:  messageText(payload),
+		Color:     eventColor(event),
+		Title:     messageTitle(payload),
+		Text:      messageDescription(payload),
+		Timestamp: time.Now().Unix(),
+	}
+
+	for _, f := range messageFields(payload) {
+		attachment.Fields = append(attachment.Fields, slackAttachmentField{
+			Title: f.Name,
+			Value: f.Value,
+			Short: f.Inline,
+		})
+	}
+
+	return attachment
+}
@@ -116,7 +116,7 @@ func (s *telegramSender) buildMessage(event domain.NotificationEvent, payload do
 	msg := ""
 
 	if payload.Subject != "" && payload.Message != "" {
-		msg += fmt.Sprintf("%v\n<b>%v</b>", payload.Subject, html.EscapeString(payload.Message))
+		msg += fmt.Sprintf("%v\n<b>%v</b>", html.EscapeString(payload.Subject), html.EscapeString(payload.Message))
 	}
 	if payload.ReleaseName != "" {
 		msg += fmt.Sprintf("\n<b>New release:</b> %v", html.EscapeString(payload.ReleaseName))
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// templateSender renders the user defined templates before handing the message to the sender
+type templateSender struct {
+	log       zerolog.Logger
+	sender    domain.NotificationSender
+	templates domain.NotificationTemplates
+}
+
+func newTemplateSender(log zerolog.Logger, sender domain.NotificationSender, templates domain.NotificationTemplates) domain.NotificationSender {
+	return &templateSender{
+		log:       log,
+		sender:    sender,
+		templates: templates,
+	}
+}
+
+func (s *templateSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	return s.sender.Send(event, s.render(event, payload))
+}
+
+func (s *templateSender) CanSend(event domain.NotificationEvent) bool {
+	return s.sender.CanSend(event)
+}
+
+// render returns a payload with only the rendered title and body, so every sender uses its plain message layout.
+// If there is no template for the event or it fails to render the payload is returned as is.
+func (s *templateSender) render(event domain.NotificationEvent, payload domain.NotificationPayload) domain.NotificationPayload {
+	tmpl, ok := s.templates[event]
+	if !ok || tmpl.IsEmpty() {
+		return payload
+	}
+
+	title, body, err := renderTemplate(tmpl, payload)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not render template for event: %v, using default message", event)
+		return payload
+	}
+
+	return domain.NotificationPayload{
+		Subject:   title,
+		Message:   body,
+		Event:     payload.Event,
+		Timestamp: payload.Timestamp,
+	}
+}
+
+// previewTemplates renders the templates, or the plain message layout for events without template, for each payload
+func previewTemplates(templates domain.NotificationTemplates, payloads []domain.NotificationPayload) []domain.NotificationPreview {
+	previews := make([]domain.NotificationPreview, 0, len(payloads))
+
+	for _, payload := range payloads {
+		preview := domain.NotificationPreview{
+			Event: payload.Event,
+			Title: messageTitle(payload),
+			Body:  messageText(payload),
+		}
+
+		if tmpl, ok := templates[payload.Event]; ok && !tmpl.IsEmpty() {
+			title, body, err := renderTemplate(tmpl, payload)
+			if err != nil {
+				preview.Error = err.Error()
+			} else {
+				preview.Title = title
+				preview.Body = body
+			}
+		}
+
+		previews = append(previews, preview)
+	}
+
+	return previews
+}
+
+// renderTemplate renders the template and falls back to the default title or description if either is empty
+func renderTemplate(tmpl domain.NotificationTemplate, payload domain.NotificationPayload) (string, string, error) {
+	title, body, err := tmpl.Render(payload)
+	if err != nil {
+		return "", "", err
+	}
+
+	if title == "" {
+		title = messageTitle(payload)
+	}
+	if body == "" {
+		body = messageDescription(payload)
+	}
+
+	return title, body, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package notification
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateSender_Send(t *testing.T) {
+	srv, requests := newTestServer(t, http.StatusOK, `{"id":1}`)
+
+	sender := newSender(zerolog.Nop(), domain.Notification{
+		Type:    domain.NotificationTypeGotify,
+		Enabled: true,
+		Host:    srv.URL,
+		Token:   "secret",
+		Events:  []string{string(domain.NotificationEventPushApproved), string(domain.NotificationEventPushRejected)},
+		Templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {
+				Title: "{{ .Indexer }}: {{ .Filter }}",
+				Body:  "{{ .ReleaseName }} ({{ .SizeString }})",
+			},
+		},
+	})
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+
+	payload := testPayload
+	payload.Size = 2254857830
+
+	err := sender.Send(domain.NotificationEventPushApproved, payload)
+	assert.NoError(t, err)
+
+	// events without template use the default layout
+	err = sender.Send(domain.NotificationEventPushRejected, payload)
+	assert.NoError(t, err)
+
+	if assert.Len(t, *requests, 2) {
+		assert.Equal(t, "MockIndexer: TV", (*requests)[0].Body["title"])
+		assert.Equal(t, "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP (2.3 GB)", (*requests)[0].Body["message"])
+
+		assert.Equal(t, "New release!", (*requests)[1].Body["title"])
+		assert.Contains(t, (*requests)[1].Body["message"], "Indexer: MockIndexer")
+	}
+}
+
+func TestTemplateSender_render(t *testing.T) {
+	s := &templateSender{
+		log: zerolog.Nop(),
+		templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {Body: "{{ .Rejections | join \", \" | upper }}"},
+			domain.NotificationEventPushError:    {Body: "{{ index .Rejections 5 }}"},
+		},
+	}
+
+	payload := testPayload
+	payload.Rejections = []string{"a", "b"}
+
+	got := s.render(domain.NotificationEventPushApproved, payload)
+	assert.Equal(t, domain.NotificationPayload{Subject: "New release!", Message: "A, B", Event: payload.Event, Timestamp: payload.Timestamp}, got)
+
+	// falls back to the payload if the template fails
+	got = s.render(domain.NotificationEventPushError, payload)
+	assert.Equal(t, payload, got)
+
+	got = s.render(domain.NotificationEventPushRejected, payload)
+	assert.Equal(t, payload, got)
+}
+
+func TestTemplateSender_Telegram(t *testing.T) {
+	s := &templateSender{
+		log: zerolog.Nop(),
+		templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {Title: "{{ .ReleaseName }}", Body: "{{ .Filter }}"},
+		},
+	}
+
+	payload := testPayload
+	payload.ReleaseName = "Show & Tell <2023>"
+	payload.Filter = "R&B"
+
+	sender := &telegramSender{log: zerolog.Nop()}
+
+	got := sender.buildMessage(domain.NotificationEventPushApproved, s.render(domain.NotificationEventPushApproved, payload))
+	assert.Equal(t, "Show &amp; Tell &lt;2023&gt;\n<b>R&amp;B</b>", got)
+}
+
+func TestTemplateSender_Pushover(t *testing.T) {
+	s := &templateSender{
+		log: zerolog.Nop(),
+		templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {Title: "{{ .ReleaseName }}", Body: "{{ .Filter }}"},
+		},
+	}
+
+	payload := testPayload
+	payload.ReleaseName = "Show & Tell <2023>"
+	payload.Filter = "R&B"
+
+	sender := &pushoverSender{log: zerolog.Nop()}
+	rendered := s.render(domain.NotificationEventPushApproved, payload)
+
+	// the title is sent as plain text, only the message is html
+	assert.Equal(t, "Show & Tell <2023>", sender.buildTitle(domain.NotificationEventPushApproved, rendered))
+	assert.Equal(t, "<b>R&amp;B</b>", sender.buildMessage(rendered))
+
+	// without a subject the title comes from the event
+	assert.Equal(t, "Push Approved", sender.buildTitle(domain.NotificationEventPushApproved, domain.NotificationPayload{}))
+}
+
+func Test_service_Preview(t *testing.T) {
+	s := &service{log: zerolog.Nop()}
+
+	previews, err := s.Preview(context.Background(), domain.Notification{
+		Templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {Title: "Grabbed {{ .ReleaseName }}", Body: "{{ .SizeString }} from {{ .Indexer }}"},
+			domain.NotificationEventPushError:    {Body: "{{ index .Rejections 5 }}"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, previews, len(testPayloads()))
+
+	for _, p := range previews {
+		switch p.Event {
+		case domain.NotificationEventPushApproved:
+			assert.Equal(t, "Grabbed Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP", p.Title)
+			assert.Equal(t, "2.3 GB from MockIndexer", p.Body)
+			assert.Empty(t, p.Error)
+		case domain.NotificationEventPushError:
+			assert.Contains(t, p.Error, "could not execute body template")
+		case domain.NotificationEventTest:
+			assert.Equal(t, "Test Notification", p.Title)
+			assert.Equal(t, "autobrr goes brr!!", p.Body)
+		}
+	}
+
+	_, err = s.Preview(context.Background(), domain.Notification{
+		Templates: domain.NotificationTemplates{
+			domain.NotificationEventPushApproved: {Title: "{{ .ReleaseName "},
+		},
+	})
+	assert.ErrorContains(t, err, "invalid template for event: PUSH_APPROVED")
+}
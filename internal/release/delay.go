@@ -0,0 +1,241 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type DelayedReleasesJob struct {
+	Name       string
+	Log        zerolog.Logger
+	releaseSvc Service
+}
+
+func (j *DelayedReleasesJob) Run() {
+	if err := j.releaseSvc.ProcessDelayed(context.Background()); err != nil {
+		j.Log.Error().Err(err).Msg("could not process delayed releases")
+	}
+}
+
+// Start resumes delayed releases stored before a restart and schedules the jobs that run delayed releases and retries when due
+func (s *service) Start() error {
+	delays, err := s.repo.FindDelays(context.Background())
+	if err != nil {
+		s.log.Error().Err(err).Msg("release.Start: could not find delayed releases")
+		return err
+	}
+
+	for _, delay := range delays {
+		s.scheduleDelayed(delay)
+	}
+
+	if len(delays) > 0 {
+		s.log.Info().Msgf("Resumed %d delayed releases", len(delays))
+	}
+
+	job := &DelayedReleasesJob{
+		Name:       "release-delayed",
+		Log:        s.log.With().Str("job", "release-delayed").Logger(),
+		releaseSvc: s,
+	}
+
+	if _, err := s.scheduler.AddJob(job, 1*time.Minute, "release-delayed"); err != nil {
+		return errors.Wrap(err, "release.Start: add job failed")
+	}
+
+	retryJob := &RetryReleasesJob{
+		Name:       "release-retry",
+		Log:        s.log.With().Str("job", "release-retry").Logger(),
+		releaseSvc: s,
+	}
+
+	if _, err := s.scheduler.AddJob(retryJob, 1*time.Minute, "release-retry"); err != nil {
+		return errors.Wrap(err, "release.Start: add retry job failed")
+	}
+
+	return nil
+}
+
+func (s *service) FindDelayed(ctx context.Context) ([]domain.ReleaseDelay, error) {
+	return s.repo.FindDelays(ctx)
+}
+
+func (s *service) CancelDelayed(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteDelay(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return errors.New("delayed release not found: %d", id)
+	}
+
+	s.log.Debug().Msgf("release.CancelDelayed: cancelled delayed release: %d", id)
+
+	return nil
+}
+
+// ProcessDelayed runs all delayed releases that are due
+func (s *service) ProcessDelayed(ctx context.Context) error {
+	delays, err := s.repo.FindDelays(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, delay := range delays {
+		if delay.RunAt.After(now) {
+			// ordered by run_at so the rest are not due either
+			break
+		}
+
+		s.runDelayed(ctx, delay)
+	}
+
+	return nil
+}
+
+// enqueueDelayed stores the release to run the filter actions once the filter delay has passed
+func (s *service) enqueueDelayed(ctx context.Context, release *domain.Release) error {
+	delay := domain.ReleaseDelay{
+		ReleaseID:   release.ID,
+		FilterID:    release.FilterID,
+		Filter:      release.FilterName,
+		Indexer:     release.Indexer,
+		TorrentName: release.TorrentName,
+		RunAt:       time.Now().Add(time.Duration(release.Filter.Delay) * time.Second).UTC(),
+	}
+
+	if err := s.repo.StoreDelay(ctx, &delay); err != nil {
+		return err
+	}
+
+	s.log.Debug().Msgf("Delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.FilterName, release.Indexer, release.Filter.Delay)
+
+	s.scheduleDelayed(delay)
+
+	return nil
+}
+
+// scheduleDelayed runs the delayed release on time instead of waiting for the next job run.
+// The timer holds no goroutine while waiting, and if it is lost the job picks the release up.
+func (s *service) scheduleDelayed(delay domain.ReleaseDelay) {
+	time.AfterFunc(time.Until(delay.RunAt), func() {
+		s.runDelayed(context.Background(), delay)
+	})
+}
+
+func (s *service) runDelayed(ctx context.Context, delay domain.ReleaseDelay) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error().Msgf("recovering from panic in delayed release process %s error: %v", delay.TorrentName, r)
+			return
+		}
+	}()
+
+	// claim the delayed release by deleting it so it only runs once, or not at all if cancelled
+	claimed, err := s.repo.DeleteDelay(ctx, delay.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.runDelayed: error claiming delayed release: %d", delay.ID)
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	release, err := s.rebuildRelease(ctx, delay.ReleaseID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.runDelayed: error finding delayed release: %d", delay.ReleaseID)
+		return
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	f, err := s.filterSvc.FindByID(ctx, delay.FilterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.runDelayed: error finding filter: %d", delay.FilterID)
+		return
+	}
+
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+	// another indexer may have pushed the same release while this one was waiting
+	if s.config.DupeProtection {
+		unlock := s.dupeLocks.Lock(release.DupeKey(s.config.DupeMatchEpisode))
+		defer unlock()
+
+		dupe, err := s.findDuplicate(ctx, release)
+		if err != nil {
+			l.Error().Err(err).Msg("release.runDelayed: error checking for duplicates")
+		}
+
+		if dupe != nil {
+			l.Info().Msgf("Skipping delayed release '%s' (%s), duplicate of %s from %s", release.TorrentName, f.Name, dupe.TorrentName, dupe.Indexer)
+			return
+		}
+	}
+
+	l.Debug().Msgf("Running delayed release '%s' (%s) for %s", release.TorrentName, f.Name, release.Indexer)
+
+	triedActionClients := map[actionClientTypeKey]struct{}{}
+
+	// if we have rejections from arr, continue with the filters after the delayed one like Process does
+	if rejections := s.runActions(ctx, l, release, triedActionClients); len(rejections) == 0 {
+		return
+	}
+
+	filters, err := s.filterSvc.FindByIndexerIdentifier(ctx, release.Indexer)
+	if err != nil {
+		l.Error().Err(err).Msgf("release.runDelayed: error finding filters for indexer: %s", release.Indexer)
+		return
+	}
+
+	for i := range filters {
+		if filters[i].ID == f.ID {
+			s.processFilters(ctx, release, filters[i+1:], triedActionClients)
+			return
+		}
+	}
+}
+
+// rebuildRelease loads a stored release and restores the cookie needed to download it again
+func (s *service) rebuildRelease(ctx context.Context, releaseID int64) (*domain.Release, error) {
+	release, err := s.repo.FindByID(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch release.Implementation {
+	case domain.ReleaseImplementationRSS:
+		feed, err := s.feedRepo.FindByIndexerIdentifier(ctx, release.Indexer)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("release.rebuildRelease: could not find feed for indexer: %s", release.Indexer)
+			break
+		}
+
+		release.RawCookie = feed.Cookie
+
+	default:
+		if def := s.indexerSvc.GetMappedDefinitionByName(release.Indexer); def != nil {
+			if v, ok := def.SettingsMap["cookie"]; ok {
+				release.RawCookie = v
+			}
+		}
+	}
+
+	return release, nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func delayedRelease() domain.Release {
+	return domain.Release{ID: 1, Indexer: "mock", TorrentName: "That.Show.S01E01.1080p.WEB.H264-GROUP", FilterID: 1}
+}
+
+func storeDelay(t *testing.T, repo *mock.ReleaseRepo, filterID int, runAt time.Time) domain.ReleaseDelay {
+	delay := domain.ReleaseDelay{ReleaseID: 1, FilterID: filterID, Indexer: "mock", RunAt: runAt}
+	if err := repo.StoreDelay(context.Background(), &delay); err != nil {
+		t.Fatal(err)
+	}
+
+	return delay
+}
+
+func TestService_Process_Delayed(t *testing.T) {
+	ctx := context.Background()
+
+	f := testFilter(1, &domain.Action{ID: 1, Name: "arr", Type: domain.ActionTypeRadarr, ClientID: 1})
+	f.Delay = 60
+
+	s := newTestService(mock.NewReleaseRepo(), f)
+	s.filters.match[1] = true
+
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2023.1080p.WEB.H264-GROUP"}
+	s.Process(release)
+
+	// the release is stored and queued but the actions are not run yet
+	assert.NotZero(t, release.ID)
+	assert.Contains(t, s.repo.Releases, release.ID)
+	assert.Empty(t, s.actions.Runs())
+
+	delays, _ := s.repo.FindDelays(ctx)
+	if assert.Len(t, delays, 1) {
+		assert.Equal(t, release.ID, delays[0].ReleaseID)
+		assert.Equal(t, 1, delays[0].FilterID)
+		assert.WithinDuration(t, time.Now().Add(60*time.Second), delays[0].RunAt, 5*time.Second)
+	}
+
+	// not due yet
+	assert.NoError(t, s.ProcessDelayed(ctx))
+	assert.Empty(t, s.actions.Runs())
+	assert.Len(t, s.repo.Delays, 1)
+}
+
+func TestService_ProcessDelayed(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()),
+		testFilter(1, &domain.Action{ID: 1, Name: "arr", Type: domain.ActionTypeRadarr, ClientID: 1}),
+	)
+
+	due := storeDelay(t, s.repo, 1, time.Now().Add(-time.Minute))
+	later := storeDelay(t, s.repo, 1, time.Now().Add(time.Hour))
+
+	assert.NoError(t, s.ProcessDelayed(ctx))
+	assert.Equal(t, []string{"arr"}, s.actions.Runs())
+
+	// only the due release is claimed
+	assert.NotContains(t, s.repo.Delays, due.ID)
+	assert.Contains(t, s.repo.Delays, later.ID)
+
+	// and it is not run again by the next job run
+	assert.NoError(t, s.ProcessDelayed(ctx))
+	assert.Equal(t, []string{"arr"}, s.actions.Runs())
+
+	statuses, _ := s.repo.GetActionStatusByReleaseID(ctx, 1)
+	if assert.Len(t, statuses, 1) {
+		assert.Equal(t, domain.ReleasePushStatusApproved, statuses[0].Status)
+	}
+}
+
+func TestService_runDelayed_OnlyOnce(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()),
+		testFilter(1, &domain.Action{ID: 1, Name: "arr", Type: domain.ActionTypeRadarr, ClientID: 1}),
+	)
+
+	delay := storeDelay(t, s.repo, 1, time.Now().Add(-time.Minute))
+
+	// the timer and the job can pick up the same release at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runDelayed(ctx, delay)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"arr"}, s.actions.Runs())
+	assert.Empty(t, s.repo.Delays)
+}
+
+func TestService_CancelDelayed(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()),
+		testFilter(1, &domain.Action{ID: 1, Name: "arr", Type: domain.ActionTypeRadarr, ClientID: 1}),
+	)
+
+	delay := storeDelay(t, s.repo, 1, time.Now().Add(-time.Minute))
+
+	assert.NoError(t, s.CancelDelayed(ctx, delay.ID))
+	assert.Empty(t, s.repo.Delays)
+
+	// a timer still holding the cancelled release does nothing
+	s.runDelayed(ctx, delay)
+	assert.Empty(t, s.actions.Runs())
+
+	assert.Error(t, s.CancelDelayed(ctx, delay.ID))
+}
+
+func TestService_Start_ResumesDelayed(t *testing.T) {
+	ctx := context.Background()
+
+	repo := mock.NewReleaseRepo(delayedRelease())
+
+	// stored before the restart, one already due and one still waiting
+	due := storeDelay(t, repo, 1, time.Now().Add(-time.Minute))
+	later := storeDelay(t, repo, 1, time.Now().Add(time.Hour))
+
+	s := newTestService(repo,
+		testFilter(1, &domain.Action{ID: 1, Name: "arr", Type: domain.ActionTypeRadarr, ClientID: 1}),
+	)
+	s.actions.ran = make(chan string, 2)
+
+	assert.NoError(t, s.Start())
+	assert.Equal(t, []string{"release-delayed", "release-retry"}, s.scheduler.jobs)
+
+	select {
+	case name := <-s.actions.ran:
+		assert.Equal(t, "arr", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("resumed delayed release was not run")
+	}
+
+	delays, _ := repo.FindDelays(ctx)
+	if assert.Len(t, delays, 1) {
+		assert.Equal(t, later.ID, delays[0].ID)
+	}
+	assert.NotContains(t, repo.Delays, due.ID)
+
+	// the job run right after does not run it again
+	assert.NoError(t, s.ProcessDelayed(ctx))
+	assert.Equal(t, []string{"arr"}, s.actions.Runs())
+}
+
+func TestService_runDelayed_FallsThrough(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()),
+		testFilter(1, &domain.Action{ID: 1, Name: "arr 1", Type: domain.ActionTypeRadarr, ClientID: 1}),
+		testFilter(2, &domain.Action{ID: 2, Name: "arr 2", Type: domain.ActionTypeRadarr, ClientID: 1}),
+		testFilter(3, &domain.Action{ID: 3, Name: "arr 3", Type: domain.ActionTypeRadarr, ClientID: 2}),
+		testFilter(4, &domain.Action{ID: 4, Name: "arr 4", Type: domain.ActionTypeRadarr, ClientID: 3}),
+		testFilter(5, &domain.Action{ID: 5, Name: "arr 5", Type: domain.ActionTypeRadarr, ClientID: 4}),
+	)
+
+	// filter 2 is delayed, filter 1 has a higher priority and was checked before the release was queued
+	s.filters.match = map[int]bool{1: true, 2: true, 3: false, 4: true, 5: true}
+	s.actions.rejections["arr 2"] = []string{"unknown movie"}
+
+	delay := storeDelay(t, s.repo, 2, time.Now().Add(-time.Minute))
+
+	s.runDelayed(ctx, delay)
+
+	// the filters after the delayed one are checked until an action is approved
+	assert.Equal(t, []int{3, 4}, s.filters.checked)
+	assert.Equal(t, []string{"arr 2", "arr 4"}, s.actions.Runs())
+
+	statuses, _ := s.repo.GetActionStatusByReleaseID(ctx, 1)
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, domain.ReleasePushStatusRejected, statuses[0].Status)
+		assert.Equal(t, "filter 2", statuses[0].Filter)
+		assert.Equal(t, domain.ReleasePushStatusApproved, statuses[1].Status)
+		assert.Equal(t, "filter 4", statuses[1].Filter)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type RetryReleasesJob struct {
+	Name       string
+	Log        zerolog.Logger
+	releaseSvc Service
+}
+
+func (j *RetryReleasesJob) Run() {
+	if err := j.releaseSvc.ProcessRetries(context.Background()); err != nil {
+		j.Log.Error().Err(err).Msg("could not process release retries")
+	}
+}
+
+func (s *service) FindRetries(ctx context.Context) ([]domain.ReleaseActionRetry, error) {
+	return s.repo.FindRetries(ctx)
+}
+
+// RetryNow runs the queued retry for the action status right away instead of waiting for the backoff
+func (s *service) RetryNow(ctx context.Context, actionStatusID int64) error {
+	retry, err := s.repo.FindRetryByActionStatusID(ctx, actionStatusID)
+	if err != nil {
+		return err
+	}
+
+	return s.runRetry(ctx, *retry)
+}
+
+// ProcessRetries runs all queued retries that are due
+func (s *service) ProcessRetries(ctx context.Context) error {
+	retries, err := s.repo.FindRetries(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, retry := range retries {
+		if retry.NextRunAt.After(now) {
+			// ordered by next_run_at so the rest are not due either
+			break
+		}
+
+		if err := s.runRetry(ctx, retry); err != nil {
+			s.log.Debug().Err(err).Msgf("release.ProcessRetries: retry failed for action status: %d", retry.ActionStatusID)
+		}
+	}
+
+	return nil
+}
+
+// enqueueRetry queues a failed push to be retried after the action backoff
+func (s *service) enqueueRetry(ctx context.Context, action *domain.Action, release *domain.Release, status *domain.ReleaseActionStatus, attempts int, pushErr error) error {
+	retry := domain.ReleaseActionRetry{
+		ReleaseID:      release.ID,
+		ActionStatusID: status.ID,
+		FilterID:       release.FilterID,
+		ActionID:       action.ID,
+		Attempts:       attempts,
+		NextRunAt:      time.Now().Add(action.RetryBackoff(attempts + 1)).UTC(),
+		LastError:      pushErr.Error(),
+	}
+
+	if err := s.repo.StoreRetry(ctx, &retry); err != nil {
+		return err
+	}
+
+	s.log.Debug().Msgf("Retrying action '%s' for '%s' at %s, attempt %d of %d", action.Name, release.TorrentName, retry.NextRunAt.Local().Format(time.RFC3339), attempts+1, action.RetryAttempts)
+
+	return nil
+}
+
+func (s *service) runRetry(ctx context.Context, retry domain.ReleaseActionRetry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error().Msgf("recovering from panic in release retry %s error: %v", retry.TorrentName, r)
+			err = errors.New("panic in release retry: %s", retry.TorrentName)
+		}
+	}()
+
+	// claim the retry by deleting it so it only runs once
+	claimed, err := s.repo.DeleteRetry(ctx, retry.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not claim retry: %d", retry.ID)
+	}
+
+	if !claimed {
+		return errors.New("retry already running or removed: %d", retry.ID)
+	}
+
+	release, err := s.rebuildRelease(ctx, retry.ReleaseID)
+	if err != nil {
+		return errors.Wrap(err, "could not find release: %d", retry.ReleaseID)
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	f, err := s.filterSvc.FindByID(ctx, retry.FilterID)
+	if err != nil {
+		return errors.Wrap(err, "could not find filter: %d", retry.FilterID)
+	}
+
+	act := findAction(f.Actions, retry.ActionID, retry.Action)
+	if act == nil {
+		return errors.New("could not find action %q on filter: %s", retry.Action, f.Name)
+	}
+
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	attempt := retry.Attempts + 1
+
+	s.log.Debug().Msgf("Retrying action '%s' for '%s' (%s), attempt %d of %d", act.Name, release.TorrentName, f.Name, attempt, act.RetryAttempts)
+
+	// reuse the failed action status to show the result of the latest attempt
+	status := domain.NewReleaseActionStatus(act, release)
+	status.ID = retry.ActionStatusID
+
+	status, pushErr := s.pushAction(ctx, act, release, status)
+
+	if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+		s.log.Error().Err(err).Msgf("release.runRetry: error storing action status for filter: %s", f.Name)
+	}
+
+	if pushErr == nil {
+		return nil
+	}
+
+	if attempt < act.RetryAttempts {
+		if err := s.enqueueRetry(ctx, act, release, status, attempt, pushErr); err != nil {
+			s.log.Error().Err(err).Msgf("release.runRetry: error queueing retry for action: %s", act.Name)
+		}
+
+		return pushErr
+	}
+
+	s.log.Warn().Msgf("Action '%s' for '%s' failed after %d retries: %v", act.Name, release.TorrentName, attempt, pushErr)
+
+	payload := domain.NotificationPayload{
+		Subject:        "Push retries failed",
+		Message:        fmt.Sprintf("%s failed after %d retries", release.TorrentName, attempt),
+		Event:          domain.NotificationEventPushRetryFailed,
+		ReleaseName:    release.TorrentName,
+		Filter:         f.Name,
+		FilterID:       f.ID,
+		Indexer:        release.Indexer,
+		InfoHash:       release.TorrentHash,
+		Size:           release.Size,
+		Status:         domain.ReleasePushStatusErr,
+		Action:         act.Name,
+		ActionType:     act.Type,
+		Rejections:     []string{pushErr.Error()},
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+	}
+
+	if act.Client != nil {
+		payload.ActionClient = act.Client.Name
+	}
+
+	s.notificationSvc.Send(domain.NotificationEventPushRetryFailed, payload)
+
+	return pushErr
+}
+
+// findAction finds the action by id, or by name since filter actions are recreated with new ids when a filter is saved
+func findAction(actions []*domain.Action, id int, name string) *domain.Action {
+	for _, a := range actions {
+		if a.ID == id {
+			return a
+		}
+	}
+
+	for _, a := range actions {
+		if a.Name == name {
+			return a
+		}
+	}
+
+	return nil
+}
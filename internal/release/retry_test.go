@@ -0,0 +1,149 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/mock"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func retryAction() *domain.Action {
+	return &domain.Action{ID: 1, Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, RetryAttempts: 3, RetryInterval: 60}
+}
+
+func storeRetry(t *testing.T, repo *mock.ReleaseRepo, actionStatusID int64, attempts int, nextRunAt time.Time) domain.ReleaseActionRetry {
+	retry := domain.ReleaseActionRetry{ReleaseID: 1, ActionStatusID: actionStatusID, FilterID: 1, ActionID: 1, Action: "qbit", Attempts: attempts, NextRunAt: nextRunAt}
+	if err := repo.StoreRetry(context.Background(), &retry); err != nil {
+		t.Fatal(err)
+	}
+
+	return retry
+}
+
+func TestService_Process_QueuesRetry(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(), testFilter(1, retryAction()))
+	s.filters.match[1] = true
+	s.actions.errs["qbit"] = errors.New("connection refused")
+
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2023.1080p.WEB.H264-GROUP"}
+	s.Process(release)
+
+	statuses, _ := s.repo.GetActionStatusByReleaseID(ctx, release.ID)
+	if !assert.Len(t, statuses, 1) {
+		return
+	}
+	assert.Equal(t, domain.ReleasePushStatusErr, statuses[0].Status)
+
+	retries, _ := s.repo.FindRetries(ctx)
+	if assert.Len(t, retries, 1) {
+		assert.Equal(t, statuses[0].ID, retries[0].ActionStatusID)
+		assert.Equal(t, release.ID, retries[0].ReleaseID)
+		assert.Equal(t, 0, retries[0].Attempts)
+		assert.Equal(t, "connection refused", retries[0].LastError)
+		assert.WithinDuration(t, time.Now().Add(60*time.Second), retries[0].NextRunAt, 5*time.Second)
+	}
+
+	// actions without retries are not queued
+	s = newTestService(mock.NewReleaseRepo(), testFilter(1, &domain.Action{ID: 1, Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1}))
+	s.filters.match[1] = true
+	s.actions.errs["qbit"] = errors.New("connection refused")
+
+	s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Movie.2023.1080p.WEB.H264-GROUP"})
+	assert.Empty(t, s.repo.Retries)
+}
+
+func TestService_ProcessRetries(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()), testFilter(1, retryAction()))
+	s.actions.errs["qbit"] = errors.New("connection refused")
+
+	due := storeRetry(t, s.repo, 10, 0, time.Now().Add(-time.Minute))
+	later := storeRetry(t, s.repo, 11, 0, time.Now().Add(time.Hour))
+
+	assert.NoError(t, s.ProcessRetries(ctx))
+	assert.Equal(t, []string{"qbit"}, s.actions.Runs())
+
+	// the failed attempt is queued again with a doubled backoff and updates the same action status
+	assert.NotContains(t, s.repo.Retries, due.ID)
+	assert.Contains(t, s.repo.Retries, later.ID)
+
+	retry, err := s.repo.FindRetryByActionStatusID(ctx, 10)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, retry.Attempts)
+		assert.WithinDuration(t, time.Now().Add(120*time.Second), retry.NextRunAt, 5*time.Second)
+	}
+
+	assert.Len(t, s.repo.Statuses, 1)
+	assert.Equal(t, domain.ReleasePushStatusErr, s.repo.Statuses[10].Status)
+
+	// the next attempt succeeds
+	delete(s.actions.errs, "qbit")
+	retry.NextRunAt = time.Now().Add(-time.Minute)
+	s.repo.Retries[retry.ID] = *retry
+
+	assert.NoError(t, s.ProcessRetries(ctx))
+	assert.Equal(t, []string{"qbit", "qbit"}, s.actions.Runs())
+	assert.Equal(t, domain.ReleasePushStatusApproved, s.repo.Statuses[10].Status)
+
+	_, err = s.repo.FindRetryByActionStatusID(ctx, 10)
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+	assert.Empty(t, s.notifications.sent)
+}
+
+func TestService_runRetry_FinalAttempt(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()), testFilter(1, retryAction()))
+	s.actions.errs["qbit"] = errors.New("connection refused")
+
+	retry := storeRetry(t, s.repo, 10, 2, time.Now().Add(-time.Minute))
+
+	err := s.runRetry(ctx, retry)
+	assert.EqualError(t, err, "connection refused")
+
+	// no more attempts are queued and the failure is notified
+	assert.Empty(t, s.repo.Retries)
+	assert.Equal(t, domain.ReleasePushStatusErr, s.repo.Statuses[10].Status)
+
+	if assert.Len(t, s.notifications.sent, 1) {
+		payload := s.notifications.sent[0]
+		assert.Equal(t, domain.NotificationEventPushRetryFailed, payload.Event)
+		assert.Equal(t, "qbit", payload.Action)
+		assert.Equal(t, "filter 1", payload.Filter)
+		assert.Equal(t, []string{"connection refused"}, payload.Rejections)
+		assert.Contains(t, payload.Message, "failed after 3 retries")
+	}
+}
+
+func TestService_RetryNow(t *testing.T) {
+	ctx := context.Background()
+
+	s := newTestService(mock.NewReleaseRepo(delayedRelease()), testFilter(1, retryAction()))
+
+	// not due for another hour
+	retry := storeRetry(t, s.repo, 10, 0, time.Now().Add(time.Hour))
+
+	assert.NoError(t, s.RetryNow(ctx, 10))
+	assert.Equal(t, []string{"qbit"}, s.actions.Runs())
+	assert.Empty(t, s.repo.Retries)
+	assert.Equal(t, domain.ReleasePushStatusApproved, s.repo.Statuses[10].Status)
+
+	// the retry was claimed so it can't run again
+	assert.ErrorIs(t, s.RetryNow(ctx, 10), domain.ErrRecordNotFound)
+	assert.Error(t, s.runRetry(ctx, retry))
+	assert.Equal(t, []string{"qbit"}, s.actions.Runs())
+
+	// keyed on the action status, not the retry id
+	assert.ErrorIs(t, s.RetryNow(ctx, retry.ID), domain.ErrRecordNotFound)
+}
@@ -5,13 +5,19 @@ package release
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/internal/scheduler"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/rs/zerolog"
 )
@@ -24,9 +30,19 @@ type Service interface {
 	Store(ctx context.Context, release *domain.Release) error
 	StoreReleaseActionStatus(ctx context.Context, actionStatus *domain.ReleaseActionStatus) error
 	Delete(ctx context.Context) error
+	Prune(ctx context.Context, rule domain.ReleasePruneRule) (int64, error)
+	FindDelayed(ctx context.Context) ([]domain.ReleaseDelay, error)
+	CancelDelayed(ctx context.Context, id int64) error
+	FindRetries(ctx context.Context) ([]domain.ReleaseActionRetry, error)
+	RetryNow(ctx context.Context, actionStatusID int64) error
+	RetryAction(ctx context.Context, releaseID int64, actionID int) (*domain.ReleaseActionStatus, error)
+	RetryActions(ctx context.Context, releaseID int64) ([]*domain.ReleaseActionStatus, error)
 
 	Process(release *domain.Release)
 	ProcessMultiple(releases []*domain.Release)
+	ProcessDelayed(ctx context.Context) error
+	ProcessRetries(ctx context.Context) error
+	Start() error
 }
 
 type actionClientTypeKey struct {
@@ -35,19 +51,32 @@ type actionClientTypeKey struct {
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.ReleaseRepo
-
-	actionSvc action.Service
-	filterSvc filter.Service
+	log    zerolog.Logger
+	config *domain.Config
+	repo   domain.ReleaseRepo
+
+	feedRepo        domain.FeedRepo
+	actionSvc       action.Service
+	filterSvc       filter.Service
+	indexerSvc      indexer.Service
+	notificationSvc notification.Service
+	scheduler       scheduler.Service
+
+	dupeLocks *keyedMutex
 }
 
-func NewService(log logger.Logger, repo domain.ReleaseRepo, actionSvc action.Service, filterSvc filter.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.ReleaseRepo, feedRepo domain.FeedRepo, actionSvc action.Service, filterSvc filter.Service, indexerSvc indexer.Service, notificationSvc notification.Service, scheduler scheduler.Service) Service {
 	return &service{
-		log:       log.With().Str("module", "release").Logger(),
-		repo:      repo,
-		actionSvc: actionSvc,
-		filterSvc: filterSvc,
+		log:             log.With().Str("module", "release").Logger(),
+		config:          config,
+		repo:            repo,
+		feedRepo:        feedRepo,
+		actionSvc:       actionSvc,
+		filterSvc:       filterSvc,
+		indexerSvc:      indexerSvc,
+		notificationSvc: notificationSvc,
+		scheduler:       scheduler,
+		dupeLocks:       newKeyedMutex(),
 	}
 }
 
@@ -84,6 +113,23 @@ func (s *service) Delete(ctx context.Context) error {
 	return s.repo.Delete(ctx)
 }
 
+// Prune deletes the releases matching the rule and returns how many were deleted
+func (s *service) Prune(ctx context.Context, rule domain.ReleasePruneRule) (int64, error) {
+	if err := rule.Validate(); err != nil {
+		return 0, err
+	}
+
+	deleted, err := s.repo.Prune(ctx, rule.Params(time.Now()))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not prune releases %s", rule)
+		return 0, err
+	}
+
+	s.log.Info().Msgf("pruned %d releases %s", deleted, rule)
+
+	return deleted, nil
+}
+
 func (s *service) Process(release *domain.Release) {
 	if release == nil {
 		return
@@ -101,10 +147,6 @@ func (s *service) Process(release *domain.Release) {
 
 	ctx := context.Background()
 
-	// TODO check in config for "Save all releases"
-	// TODO cross-seed check
-	// TODO dupe checks
-
 	// get filters by priority
 	filters, err := s.filterSvc.FindByIndexerIdentifier(ctx, release.Indexer)
 	if err != nil {
@@ -114,12 +156,37 @@ func (s *service) Process(release *domain.Release) {
 
 	if len(filters) == 0 {
 		s.log.Warn().Msgf("no active filters found for indexer: %s", release.Indexer)
+
+		s.storeRejected(ctx, release, []string{fmt.Sprintf("no active filters found for indexer: %s", release.Indexer)})
 		return
 	}
 
+	// the same release announced on several indexers at once would pass the duplicate check on all of them
+	// before the first one is pushed, so check and push releases that can be duplicates one at a time
+	if s.config.DupeProtection {
+		unlock := s.dupeLocks.Lock(release.DupeKey(s.config.DupeMatchEpisode))
+		defer unlock()
+	}
+
 	// keep track of action clients to avoid sending the same thing all over again
 	// save both client type and client id to potentially try another client of same type
-	triedActionClients := map[actionClientTypeKey]struct{}{}
+	filterRejections, err := s.processFilters(ctx, release, filters, map[actionClientTypeKey]struct{}{})
+	if err != nil {
+		return
+	}
+
+	// release was not stored by a matching filter so no filter wanted it
+	if release.ID == 0 {
+		s.storeRejected(ctx, release, filterRejections)
+	}
+
+	return
+}
+
+// processFilters checks the filters in order and runs the actions of the first match, falling through to the next
+// filter if an arr rejects the release. It returns the rejections per filter in case no filter matches.
+func (s *service) processFilters(ctx context.Context, release *domain.Release, filters []domain.Filter, triedActionClients map[actionClientTypeKey]struct{}) ([]string, error) {
+	var filterRejections []string
 
 	// loop over and check filters
 	for _, f := range filters {
@@ -134,80 +201,68 @@ func (s *service) Process(release *domain.Release) {
 		match, err := s.filterSvc.CheckFilter(ctx, f, release)
 		if err != nil {
 			l.Error().Err(err).Msg("release.Process: error checking filter")
-			return
+			return nil, err
 		}
 
 		if !match {
+			metrics.FilterChecksTotal.WithLabelValues(f.Name, "rejected").Inc()
+
 			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s, no match. rejections: %s", release.Indexer, release.Filter.Name, release.TorrentName, release.RejectionsString())
 
 			l.Debug().Msgf("release rejected: %s", release.RejectionsString())
-			continue
-		}
-
-		l.Info().Msgf("Matched '%s' (%s) for %s", release.TorrentName, release.Filter.Name, release.Indexer)
 
-		// save release here to only save those with rejections from actions instead of all releases
-		if release.ID == 0 {
-			release.FilterStatus = domain.ReleaseStatusFilterApproved
-			if err = s.Store(ctx, release); err != nil {
-				l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
-				return
+			if len(release.Rejections) > 0 {
+				filterRejections = append(filterRejections, fmt.Sprintf("%s: %s", f.Name, release.RejectionsString()))
 			}
-		}
 
-		// sleep for the delay period specified in the filter before running actions
-		delay := release.Filter.Delay
-		if delay > 0 {
-			l.Debug().Msgf("Delaying processing of '%s' (%s) for %s by %d seconds as specified in the filter", release.TorrentName, release.Filter.Name, release.Indexer, delay)
-			time.Sleep(time.Duration(delay) * time.Second)
+			continue
 		}
 
-		var rejections []string
+		// reject releases already grabbed from this or other indexers
+		if s.config.DupeProtection {
+			dupe, err := s.findDuplicate(ctx, release)
+			if err != nil {
+				l.Error().Err(err).Msg("release.Process: error checking for duplicates")
+			}
 
-		// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
-		for _, a := range release.Filter.Actions {
-			act := a
+			if dupe != nil {
+				metrics.FilterChecksTotal.WithLabelValues(f.Name, "duplicate").Inc()
 
-			// only run enabled actions
-			if !act.Enabled {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.Filter.Name, release.TorrentName, act.Name)
-				continue
-			}
+				release.AddRejectionF("duplicate of %s from %s grabbed at %s", dupe.TorrentName, dupe.Indexer, dupe.Timestamp.Format(time.RFC3339))
 
-			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.Filter.Name, release.TorrentName, act.Name)
+				l.Debug().Msgf("release rejected: %s", release.RejectionsString())
+
+				filterRejections = append(filterRejections, fmt.Sprintf("%s: %s", f.Name, release.RejectionsString()))
 
-			// keep track of actiom clients to avoid sending the same thing all over again
-			_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
-			if tried {
-				l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer, release.Filter.Name, release.TorrentName)
 				continue
 			}
+		}
 
-			// run action
-			status, err := s.runAction(ctx, act, release)
-			if err != nil {
-				l.Error().Stack().Err(err).Msgf("release.Process: error running actions for filter: %s", release.Filter.Name)
-				//continue
-			}
+		metrics.FilterChecksTotal.WithLabelValues(f.Name, "match").Inc()
 
-			rejections = status.Rejections
+		l.Info().Msgf("Matched '%s' (%s) for %s", release.TorrentName, release.Filter.Name, release.Indexer)
 
-			if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
-				s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.Filter.Name)
+		// save release here to only save those with rejections from actions instead of all releases
+		if release.ID == 0 {
+			release.FilterStatus = domain.ReleaseStatusFilterApproved
+			if err = s.Store(ctx, release); err != nil {
+				l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+				return nil, err
 			}
+		}
 
-			if len(rejections) > 0 {
-				// if we get action rejection, remember which action client it was from
-				triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
-
-				// log something and fire events
-				l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
+		// delayed releases are queued and run by the scheduler once the delay has passed,
+		// the filters after this one are checked then if an arr rejects the release
+		if release.Filter.Delay > 0 {
+			if err := s.enqueueDelayed(ctx, release); err != nil {
+				l.Error().Err(err).Msgf("release.Process: error queueing delayed release for filter: %s", release.Filter.Name)
 			}
 
-			// if no rejections consider action approved, run next
-			continue
+			break
 		}
 
+		rejections := s.runActions(ctx, l, release, triedActionClients)
+
 		// if we have rejections from arr, continue to next filter
 		if len(rejections) > 0 {
 			continue
@@ -217,7 +272,58 @@ func (s *service) Process(release *domain.Release) {
 		break
 	}
 
-	return
+	return filterRejections, nil
+}
+
+// findDuplicate finds an already grabbed release that is a duplicate of the release within the duplicate window
+func (s *service) findDuplicate(ctx context.Context, release *domain.Release) (*domain.Release, error) {
+	params := domain.ReleaseDuplicateParams{
+		Release:      release,
+		MatchEpisode: s.config.DupeMatchEpisode,
+	}
+
+	// only releases from the same indexer are duplicates if the filter allows them from other indexers
+	if release.Filter.AllowDupeOtherIndexers {
+		params.Indexer = release.Indexer
+	}
+
+	if s.config.DupeWindow > 0 {
+		params.Since = time.Now().Add(-time.Duration(s.config.DupeWindow) * time.Hour)
+	}
+
+	candidates, err := s.repo.FindDuplicates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if release.IsDuplicateOf(candidate, s.config.DupeMatchEpisode) {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// storeRejected stores a release rejected by all filters if saving rejected releases is enabled
+func (s *service) storeRejected(ctx context.Context, release *domain.Release, rejections []string) {
+	if !s.config.SaveRejectedReleases {
+		return
+	}
+
+	release.Filter = nil
+	release.FilterName = ""
+	release.FilterID = 0
+	release.FilterStatus = domain.ReleaseStatusFilterRejected
+	release.Rejections = rejections
+
+	if release.Rejections == nil {
+		release.Rejections = []string{}
+	}
+
+	if err := s.Store(ctx, release); err != nil {
+		s.log.Error().Err(err).Msgf("release.storeRejected: error writing rejected release to database: %s", release.TorrentName)
+	}
 }
 
 func (s *service) ProcessMultiple(releases []*domain.Release) {
@@ -232,10 +338,154 @@ func (s *service) ProcessMultiple(releases []*domain.Release) {
 	}
 }
 
+// RetryAction runs an action again on a stored release, recording a new action status
+func (s *service) RetryAction(ctx context.Context, releaseID int64, actionID int) (*domain.ReleaseActionStatus, error) {
+	release, err := s.rebuildRelease(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	act, err := s.actionSvc.FindByID(ctx, actionID)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, act.FilterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find filter for action: %s", act.Name)
+	}
+
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	s.log.Info().Msgf("Manually running action '%s' (%s) for '%s'", act.Name, f.Name, release.TorrentName)
+
+	status, err := s.runAction(ctx, act, release)
+
+	if storeErr := s.StoreReleaseActionStatus(ctx, status); storeErr != nil {
+		s.log.Error().Err(storeErr).Msgf("release.RetryAction: error storing action status for filter: %s", f.Name)
+	}
+
+	return status, err
+}
+
+// RetryActions runs all enabled actions of the release filter again on a stored release
+func (s *service) RetryActions(ctx context.Context, releaseID int64) ([]*domain.ReleaseActionStatus, error) {
+	release, err := s.rebuildRelease(ctx, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release.CleanupTemporaryFiles()
+
+	if release.FilterID == 0 {
+		return nil, errors.New("release was not matched by a filter: %d", releaseID)
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, release.FilterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find filter: %d", release.FilterID)
+	}
+
+	release.Filter = f
+	release.FilterName = f.Name
+
+	s.log.Info().Msgf("Manually running actions (%s) for '%s'", f.Name, release.TorrentName)
+
+	statuses := make([]*domain.ReleaseActionStatus, 0)
+
+	for _, act := range f.Actions {
+		if !act.Enabled {
+			continue
+		}
+
+		status, err := s.runAction(ctx, act, release)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("release.RetryActions: error running action: %s", act.Name)
+		}
+
+		if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+			s.log.Error().Err(err).Msgf("release.RetryActions: error storing action status for filter: %s", f.Name)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// runActions runs the enabled actions of the release filter and returns the rejections of the last action run
+func (s *service) runActions(ctx context.Context, l zerolog.Logger, release *domain.Release, triedActionClients map[actionClientTypeKey]struct{}) []string {
+	var rejections []string
+
+	// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
+	for _, a := range release.Filter.Actions {
+		act := a
+
+		// only run enabled actions
+		if !act.Enabled {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action '%s' not enabled, skip", release.Indexer, release.Filter.Name, release.TorrentName, act.Name)
+			continue
+		}
+
+		l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s , run action: %s", release.Indexer, release.Filter.Name, release.TorrentName, act.Name)
+
+		// keep track of actiom clients to avoid sending the same thing all over again
+		_, tried := triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}]
+		if tried {
+			l.Trace().Msgf("release.Process: indexer: %s, filter: %s release: %s action client already tried, skip", release.Indexer, release.Filter.Name, release.TorrentName)
+			continue
+		}
+
+		// run action
+		status, err := s.runAction(ctx, act, release)
+		if err != nil {
+			l.Error().Stack().Err(err).Msgf("release.Process: error running actions for filter: %s", release.Filter.Name)
+			//continue
+		}
+
+		rejections = status.Rejections
+
+		if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
+			s.log.Error().Err(err).Msgf("release.Process: error storing action status for filter: %s", release.Filter.Name)
+		}
+
+		// queue failed pushes for retry if enabled on the action
+		if err != nil && act.RetryAttempts > 0 {
+			if err := s.enqueueRetry(ctx, act, release, status, 0, err); err != nil {
+				l.Error().Err(err).Msgf("release.Process: error queueing retry for action: %s", act.Name)
+			}
+		}
+
+		if len(rejections) > 0 {
+			// if we get action rejection, remember which action client it was from
+			triedActionClients[actionClientTypeKey{Type: act.Type, ClientID: act.ClientID}] = struct{}{}
+
+			// log something and fire events
+			l.Debug().Str("action", act.Name).Str("action_type", string(act.Type)).Msgf("release rejected: %s", strings.Join(rejections, ", "))
+		}
+
+		// if no rejections consider action approved, run next
+		continue
+	}
+
+	return rejections
+}
+
 func (s *service) runAction(ctx context.Context, action *domain.Action, release *domain.Release) (*domain.ReleaseActionStatus, error) {
 	// add action status as pending
 	status := domain.NewReleaseActionStatus(action, release)
 
+	return s.pushAction(ctx, action, release, status)
+}
+
+// pushAction marks the action status as pending and runs the action, updating the status with the result
+func (s *service) pushAction(ctx context.Context, action *domain.Action, release *domain.Release, status *domain.ReleaseActionStatus) (*domain.ReleaseActionStatus, error) {
+	defer observeActionPush(action, status)
+
 	if err := s.StoreReleaseActionStatus(ctx, status); err != nil {
 		s.log.Error().Err(err).Msgf("release.runAction: error storing action for filter: %s", release.Filter.Name)
 	}
@@ -261,3 +511,13 @@ func (s *service) runAction(ctx context.Context, action *domain.Action, release
 
 	return status, nil
 }
+
+// observeActionPush counts the push by action type, download client and resulting status
+func observeActionPush(action *domain.Action, status *domain.ReleaseActionStatus) {
+	client := ""
+	if action.Client != nil {
+		client = action.Client.Name
+	}
+
+	metrics.ActionPushesTotal.WithLabelValues(string(action.Type), client, string(status.Status)).Inc()
+}
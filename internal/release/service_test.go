@@ -0,0 +1,156 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/mock"
+	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/internal/scheduler"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+// the fakes embed the service interfaces and only implement what the release service calls
+
+type fakeFilterService struct {
+	filter.Service
+
+	filters []domain.Filter
+	// match is the result of CheckFilter by filter id
+	match   map[int]bool
+	checked []int
+}
+
+func (f *fakeFilterService) FindByID(ctx context.Context, filterID int) (*domain.Filter, error) {
+	for _, filter := range f.filters {
+		if filter.ID == filterID {
+			filter := filter
+			return &filter, nil
+		}
+	}
+
+	return nil, errors.New("filter not found: %d", filterID)
+}
+
+func (f *fakeFilterService) FindByIndexerIdentifier(ctx context.Context, indexer string) ([]domain.Filter, error) {
+	return f.filters, nil
+}
+
+func (f *fakeFilterService) CheckFilter(ctx context.Context, filter domain.Filter, release *domain.Release) (bool, error) {
+	f.checked = append(f.checked, filter.ID)
+	return f.match[filter.ID], nil
+}
+
+type fakeActionService struct {
+	action.Service
+
+	m    sync.Mutex
+	runs []string
+	// rejections and errs are the result of RunAction by action name
+	rejections map[string][]string
+	errs       map[string]error
+	ran        chan string
+}
+
+func (a *fakeActionService) RunAction(ctx context.Context, act *domain.Action, release *domain.Release) ([]string, error) {
+	a.m.Lock()
+	a.runs = append(a.runs, act.Name)
+	a.m.Unlock()
+
+	if a.ran != nil {
+		a.ran <- act.Name
+	}
+
+	return a.rejections[act.Name], a.errs[act.Name]
+}
+
+func (a *fakeActionService) Runs() []string {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return append([]string{}, a.runs...)
+}
+
+type fakeIndexerService struct {
+	indexer.Service
+}
+
+func (i *fakeIndexerService) GetMappedDefinitionByName(name string) *domain.IndexerDefinition {
+	return nil
+}
+
+type fakeNotificationService struct {
+	notification.Service
+
+	sent []domain.NotificationPayload
+}
+
+func (n *fakeNotificationService) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+	n.sent = append(n.sent, payload)
+}
+
+type fakeScheduler struct {
+	scheduler.Service
+
+	jobs []string
+}
+
+func (s *fakeScheduler) AddJob(job cron.Job, interval time.Duration, identifier string) (int, error) {
+	s.jobs = append(s.jobs, identifier)
+	return len(s.jobs), nil
+}
+
+type testService struct {
+	*service
+
+	repo          *mock.ReleaseRepo
+	filters       *fakeFilterService
+	actions       *fakeActionService
+	notifications *fakeNotificationService
+	scheduler     *fakeScheduler
+}
+
+func newTestService(repo *mock.ReleaseRepo, filters ...domain.Filter) *testService {
+	t := &testService{
+		repo:          repo,
+		filters:       &fakeFilterService{filters: filters, match: map[int]bool{}},
+		actions:       &fakeActionService{rejections: map[string][]string{}, errs: map[string]error{}},
+		notifications: &fakeNotificationService{},
+		scheduler:     &fakeScheduler{},
+	}
+
+	t.service = &service{
+		log:             zerolog.Nop(),
+		config:          &domain.Config{},
+		repo:            repo,
+		actionSvc:       t.actions,
+		filterSvc:       t.filters,
+		indexerSvc:      &fakeIndexerService{},
+		notificationSvc: t.notifications,
+		scheduler:       t.scheduler,
+		dupeLocks:       newKeyedMutex(),
+	}
+
+	return t
+}
+
+func testFilter(id int, actions ...*domain.Action) domain.Filter {
+	for _, a := range actions {
+		a.FilterID = id
+		a.Enabled = true
+	}
+
+	return domain.Filter{ID: id, Name: fmt.Sprintf("filter %d", id), Enabled: true, Actions: actions}
+}
@@ -5,15 +5,120 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/update"
 
+	"github.com/dustin/go-humanize"
 	"github.com/rs/zerolog"
 )
 
+type PruneReleasesJob struct {
+	Name        string
+	Log         zerolog.Logger
+	ReleaseRepo domain.ReleaseRepo
+	Rules       []domain.ReleasePruneRule
+}
+
+func (j *PruneReleasesJob) Run() {
+	now := time.Now()
+
+	for _, rule := range j.Rules {
+		deleted, err := j.ReleaseRepo.Prune(context.TODO(), rule.Params(now))
+		if err != nil {
+			j.Log.Error().Err(err).Msgf("could not prune releases %s", rule)
+			continue
+		}
+
+		if deleted > 0 {
+			j.Log.Debug().Msgf("deleted %d releases %s", deleted, rule)
+		}
+	}
+}
+
+type DatabaseBackupJob struct {
+	Name string
+	Log  zerolog.Logger
+	DB   *database.DB
+}
+
+func (j *DatabaseBackupJob) Run() {
+	if _, err := j.DB.Backup(context.TODO()); err != nil {
+		j.Log.Error().Err(err).Msg("could not backup database")
+	}
+}
+
+type DailyDigestJob struct {
+	Name        string
+	Log         zerolog.Logger
+	NotifSvc    notification.Service
+	ReleaseRepo domain.ReleaseRepo
+	Hour        int
+
+	lastSent time.Time
+}
+
+// digestMaxReleases is the max number of release names listed in the digest
+const digestMaxReleases = 20
+
+func (j *DailyDigestJob) Run() {
+	now := time.Now()
+
+	if now.Hour() != j.Hour {
+		return
+	}
+
+	// only send once a day
+	if y, m, d := j.lastSent.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return
+	}
+
+	releases, err := j.ReleaseRepo.FindGrabbedSince(context.TODO(), now.Add(-24*time.Hour))
+	if err != nil {
+		j.Log.Error().Err(err).Msg("could not find grabbed releases")
+		return
+	}
+
+	j.lastSent = now
+
+	j.NotifSvc.Send(domain.NotificationEventDailyDigest, domain.NotificationPayload{
+		Subject:   "Daily digest",
+		Message:   digestMessage(releases),
+		Event:     domain.NotificationEventDailyDigest,
+		Timestamp: now,
+	})
+}
+
+func digestMessage(releases []*domain.Release) string {
+	var b strings.Builder
+
+	var size uint64
+	for _, r := range releases {
+		size += r.Size
+	}
+
+	fmt.Fprintf(&b, "%d releases grabbed in the last 24 hours", len(releases))
+	if size > 0 {
+		fmt.Fprintf(&b, " (%s)", humanize.Bytes(size))
+	}
+
+	for i, r := range releases {
+		if i == digestMaxReleases {
+			fmt.Fprintf(&b, "\n...and %d more", len(releases)-digestMaxReleases)
+			break
+		}
+
+		fmt.Fprintf(&b, "\n%s [%s]", r.TorrentName, r.Indexer)
+	}
+
+	return b.String()
+}
+
 type CheckUpdatesJob struct {
 	Name          string
 	Log           zerolog.Logger
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
@@ -30,18 +31,22 @@ type service struct {
 	version         string
 	notificationSvc notification.Service
 	updateSvc       *update.Service
+	releaseRepo     domain.ReleaseRepo
+	db              *database.DB
 
 	cron *cron.Cron
 	jobs map[string]cron.EntryID
 	m    sync.RWMutex
 }
 
-func NewService(log logger.Logger, config *domain.Config, notificationSvc notification.Service, updateSvc *update.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, db *database.DB, notificationSvc notification.Service, updateSvc *update.Service, releaseRepo domain.ReleaseRepo) Service {
 	return &service{
 		log:             log.With().Str("module", "scheduler").Logger(),
 		config:          config,
 		notificationSvc: notificationSvc,
 		updateSvc:       updateSvc,
+		releaseRepo:     releaseRepo,
+		db:              db,
 		cron: cron.New(cron.WithChain(
 			cron.Recover(cron.DefaultLogger),
 		)),
@@ -78,6 +83,74 @@ func (s *service) addAppJobs() {
 			s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
 		}
 	}
+
+	if rules := s.releasePruneRules(); len(rules) > 0 {
+		pruneReleases := &PruneReleasesJob{
+			Name:        "release-prune",
+			Log:         s.log.With().Str("job", "release-prune").Logger(),
+			ReleaseRepo: s.releaseRepo,
+			Rules:       rules,
+		}
+
+		if id, err := s.AddJob(pruneReleases, 6*time.Hour, "release-prune"); err != nil {
+			s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
+		}
+	}
+
+	if s.config.DigestHour >= 0 {
+		dailyDigest := &DailyDigestJob{
+			Name:        "notification-daily-digest",
+			Log:         s.log.With().Str("job", "notification-daily-digest").Logger(),
+			NotifSvc:    s.notificationSvc,
+			ReleaseRepo: s.releaseRepo,
+			Hour:        s.config.DigestHour,
+		}
+
+		if id, err := s.AddJob(dailyDigest, 15*time.Minute, "notification-daily-digest"); err != nil {
+			s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
+		}
+	}
+
+	if s.db.Driver == "sqlite" && s.config.DatabaseBackupInterval > 0 {
+		databaseBackup := &DatabaseBackupJob{
+			Name: "database-backup",
+			Log:  s.log.With().Str("job", "database-backup").Logger(),
+			DB:   s.db,
+		}
+
+		if id, err := s.AddJob(databaseBackup, time.Duration(s.config.DatabaseBackupInterval)*time.Hour, "database-backup"); err != nil {
+			s.log.Error().Err(err).Msgf("scheduler.addAppJobs: error adding job: %v", id)
+		}
+	}
+}
+
+// releasePruneRules returns the valid release retention rules, including the rejected releases retention
+func (s *service) releasePruneRules() []domain.ReleasePruneRule {
+	var rules []domain.ReleasePruneRule
+
+	if s.config.RejectedReleasesRetention > 0 {
+		rules = append(rules, domain.ReleasePruneRule{
+			Days:     s.config.RejectedReleasesRetention,
+			Statuses: []string{string(domain.ReleaseStatusFilterRejected)},
+		})
+	}
+
+	for _, rule := range s.config.ReleaseRetention {
+		if err := rule.Validate(); err != nil {
+			s.log.Error().Err(err).Msgf("skipping release retention rule: %s", rule)
+			continue
+		}
+
+		// scheduled rules must keep something, use the prune endpoint to delete everything
+		if rule.Days == 0 {
+			s.log.Error().Msgf("skipping release retention rule without days: %s", rule)
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
 }
 
 func (s *service) Stop() {
@@ -13,6 +13,7 @@ import (
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/irc"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/update"
 
@@ -26,6 +27,7 @@ type Server struct {
 	indexerService indexer.Service
 	ircService     irc.Service
 	feedService    feed.Service
+	releaseService release.Service
 	scheduler      scheduler.Service
 	updateService  *update.Service
 
@@ -33,13 +35,14 @@ type Server struct {
 	lock   sync.Mutex
 }
 
-func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
+func NewServer(log logger.Logger, config *domain.Config, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, releaseSvc release.Service, scheduler scheduler.Service, updateSvc *update.Service) *Server {
 	return &Server{
 		log:            log.With().Str("module", "server").Logger(),
 		config:         config,
 		indexerService: indexerSvc,
 		ircService:     ircSvc,
 		feedService:    feedSvc,
+		releaseService: releaseSvc,
 		scheduler:      scheduler,
 		updateService:  updateSvc,
 	}
@@ -65,6 +68,11 @@ func (s *Server) Start() error {
 		s.log.Error().Err(err).Msg("Could not start feed service")
 	}
 
+	// resume delayed releases
+	if err := s.releaseService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start release service")
+	}
+
 	return nil
 }
 
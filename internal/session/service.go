@@ -0,0 +1,131 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// MaxAge matches the default max age of the session cookie
+	MaxAge = 30 * 24 * time.Hour
+
+	// touchInterval limits how often last seen is written
+	touchInterval = time.Minute
+)
+
+type Service interface {
+	Create(ctx context.Context, userID int, ip, userAgent string) (*domain.Session, error)
+	Validate(ctx context.Context, id string, userID int) bool
+	List(ctx context.Context, userID int) ([]domain.Session, error)
+	Revoke(ctx context.Context, userID int, id string) error
+	RevokeOthers(ctx context.Context, userID int, current string) error
+}
+
+type service struct {
+	log  zerolog.Logger
+	repo domain.SessionRepo
+
+	now func() time.Time
+}
+
+func NewService(log logger.Logger, repo domain.SessionRepo) Service {
+	return &service{
+		log:  log.With().Str("module", "session").Logger(),
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Create stores a new session for the user and removes expired ones
+func (s *service) Create(ctx context.Context, userID int, ip, userAgent string) (*domain.Session, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, errors.Wrap(err, "could not generate session id")
+	}
+
+	now := s.now()
+
+	session := &domain.Session{
+		ID:        hex.EncodeToString(b),
+		UserID:    userID,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+
+	if err := s.repo.Store(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteExpired(ctx, now.Add(-MaxAge)); err != nil {
+		s.log.Error().Err(err).Msg("could not delete expired sessions")
+	}
+
+	return session, nil
+}
+
+// Validate checks that the session exists, belongs to the user and has not expired
+func (s *service) Validate(ctx context.Context, id string, userID int) bool {
+	if id == "" {
+		return false
+	}
+
+	session, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not find session")
+		return false
+	}
+
+	if session == nil || session.UserID != userID {
+		return false
+	}
+
+	now := s.now()
+
+	if now.Sub(session.LastSeen) > MaxAge {
+		return false
+	}
+
+	if now.Sub(session.LastSeen) > touchInterval {
+		if err := s.repo.Touch(ctx, id, now); err != nil {
+			s.log.Error().Err(err).Msg("could not update session last seen")
+		}
+	}
+
+	return true
+}
+
+func (s *service) List(ctx context.Context, userID int) ([]domain.Session, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Revoke deletes a session of the user
+func (s *service) Revoke(ctx context.Context, userID int, id string) error {
+	session, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserID != userID {
+		return errors.New("session not found")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// RevokeOthers deletes all sessions of the user except the current one
+func (s *service) RevokeOthers(ctx context.Context, userID int, current string) error {
+	return s.repo.DeleteByUser(ctx, userID, current)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/mock"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService() (*service, *mock.SessionRepo) {
+	repo := mock.NewSessionRepo()
+	return &service{log: zerolog.Nop(), repo: repo, now: time.Now}, repo
+}
+
+func TestService_Validate(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestService()
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	session, err := s.Create(ctx, 1, "10.0.0.1", "firefox")
+	assert.NoError(t, err)
+	assert.Len(t, session.ID, 64)
+
+	assert.True(t, s.Validate(ctx, session.ID, 1))
+	assert.False(t, s.Validate(ctx, session.ID, 2), "session of another user")
+	assert.False(t, s.Validate(ctx, "unknown", 1))
+	assert.False(t, s.Validate(ctx, "", 1))
+
+	// last seen is updated on use
+	now = now.Add(time.Hour)
+	assert.True(t, s.Validate(ctx, session.ID, 1))
+	assert.Equal(t, now, repo.Sessions[session.ID].LastSeen)
+
+	// idle sessions expire
+	now = now.Add(MaxAge + time.Minute)
+	assert.False(t, s.Validate(ctx, session.ID, 1))
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestService()
+
+	first, _ := s.Create(ctx, 1, "10.0.0.1", "firefox")
+	second, _ := s.Create(ctx, 1, "10.0.0.2", "chrome")
+	third, _ := s.Create(ctx, 1, "10.0.0.3", "curl")
+	other, _ := s.Create(ctx, 2, "10.0.0.4", "safari")
+
+	// users can only revoke their own sessions
+	assert.Error(t, s.Revoke(ctx, 1, other.ID))
+
+	assert.NoError(t, s.Revoke(ctx, 1, second.ID))
+	assert.False(t, s.Validate(ctx, second.ID, 1))
+
+	assert.NoError(t, s.RevokeOthers(ctx, 1, first.ID))
+	assert.True(t, s.Validate(ctx, first.ID, 1))
+	assert.False(t, s.Validate(ctx, third.ID, 1))
+	assert.True(t, s.Validate(ctx, other.ID, 2))
+	assert.Len(t, repo.Sessions, 2)
+}
@@ -7,22 +7,33 @@ import (
 	"context"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/argon2id"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
 type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	FindByID(ctx context.Context, id int) (*domain.User, error)
+	FindByOIDCSubject(ctx context.Context, subject string) (*domain.User, error)
+	List(ctx context.Context) ([]domain.User, error)
 	CreateUser(ctx context.Context, req domain.CreateUserRequest) error
+	Create(ctx context.Context, req domain.CreateUserRequest) error
+	Update(ctx context.Context, req domain.UpdateUserRequest) error
+	Delete(ctx context.Context, id int) error
+	SetTwoFactor(ctx context.Context, id int, enabled bool, secret string, recoveryCodes []string) error
+	LinkOIDC(ctx context.Context, id int, subject string) error
 }
 
 type service struct {
-	repo domain.UserRepo
+	repo        domain.UserRepo
+	sessionRepo domain.SessionRepo
 }
 
-func NewService(repo domain.UserRepo) Service {
+func NewService(repo domain.UserRepo, sessionRepo domain.SessionRepo) Service {
 	return &service{
-		repo: repo,
+		repo:        repo,
+		sessionRepo: sessionRepo,
 	}
 }
 
@@ -39,15 +50,188 @@ func (s *service) FindByUsername(ctx context.Context, username string) (*domain.
 	return user, nil
 }
 
+func (s *service) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *service) FindByOIDCSubject(ctx context.Context, subject string) (*domain.User, error) {
+	return s.repo.FindByOIDCSubject(ctx, subject)
+}
+
+func (s *service) List(ctx context.Context) ([]domain.User, error) {
+	return s.repo.List(ctx)
+}
+
+// CreateUser stores the user as is, the password must already be hashed
 func (s *service) CreateUser(ctx context.Context, req domain.CreateUserRequest) error {
-	userCount, err := s.repo.GetUserCount(ctx)
+	if req.Role == "" {
+		req.Role = domain.UserRoleAdmin
+	}
+
+	return s.repo.Store(ctx, req)
+}
+
+// Create validates the request, hashes the password and stores the new user
+func (s *service) Create(ctx context.Context, req domain.CreateUserRequest) error {
+	if req.Username == "" {
+		return errors.New("validation error: empty username supplied")
+	} else if req.Password == "" {
+		return errors.New("validation error: empty password supplied")
+	} else if !req.Role.IsValid() {
+		return errors.New("validation error: invalid role: %q", req.Role)
+	}
+
+	existing, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		return err
 	}
 
-	if userCount > 0 {
-		return errors.New("only 1 user account is supported at the moment")
+	if existing != nil {
+		return errors.New("validation error: username already taken: %s", req.Username)
+	}
+
+	hashed, err := argon2id.CreateHash(req.Password, argon2id.DefaultParams)
+	if err != nil {
+		return errors.New("failed to hash password")
 	}
 
+	req.Password = hashed
+
 	return s.repo.Store(ctx, req)
 }
+
+func (s *service) Update(ctx context.Context, req domain.UpdateUserRequest) error {
+	user, err := s.repo.FindByID(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found: %d", req.ID)
+	}
+
+	// the user has to log in again after losing access or a password reset
+	revokeSessions := false
+
+	if req.Role != "" && req.Role != user.Role {
+		if !req.Role.IsValid() {
+			return errors.New("validation error: invalid role: %q", req.Role)
+		}
+
+		if user.Role == domain.UserRoleAdmin {
+			if err := s.checkLastAdmin(ctx); err != nil {
+				return err
+			}
+		}
+
+		revokeSessions = req.Role.Rank() < user.Role.Rank()
+		user.Role = req.Role
+	}
+
+	if req.Password != "" {
+		hashed, err := argon2id.CreateHash(req.Password, argon2id.DefaultParams)
+		if err != nil {
+			return errors.New("failed to hash password")
+		}
+
+		user.Password = hashed
+		revokeSessions = true
+	}
+
+	if err := s.repo.Update(ctx, *user); err != nil {
+		return err
+	}
+
+	if revokeSessions {
+		if err := s.sessionRepo.DeleteByUser(ctx, user.ID, ""); err != nil {
+			return errors.Wrap(err, "could not revoke sessions of user: %s", user.Username)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found: %d", id)
+	}
+
+	if user.Role == domain.UserRoleAdmin {
+		if err := s.checkLastAdmin(ctx); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// SetTwoFactor stores the two-factor state of the user, the recovery codes must already be hashed
+func (s *service) SetTwoFactor(ctx context.Context, id int, enabled bool, secret string, recoveryCodes []string) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found: %d", id)
+	}
+
+	user.TwoFactorEnabled = enabled
+	user.TwoFactorSecret = secret
+	user.RecoveryCodes = recoveryCodes
+
+	return s.repo.Update(ctx, *user)
+}
+
+// LinkOIDC links the user to the subject of an OpenID Connect identity, an empty subject removes the link
+func (s *service) LinkOIDC(ctx context.Context, id int, subject string) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found: %d", id)
+	}
+
+	if subject != "" {
+		linked, err := s.repo.FindByOIDCSubject(ctx, subject)
+		if err != nil {
+			return err
+		}
+
+		if linked != nil && linked.ID != id {
+			return errors.New("validation error: oidc identity is already linked to another user")
+		}
+	}
+
+	user.OIDCSubject = subject
+
+	return s.repo.Update(ctx, *user)
+}
+
+// checkLastAdmin returns an error if there is only one admin left
+func (s *service) checkLastAdmin(ctx context.Context) error {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	admins := 0
+	for _, u := range users {
+		if u.Role == domain.UserRoleAdmin {
+			admins++
+		}
+	}
+
+	if admins <= 1 {
+		return errors.New("validation error: can not remove the last admin")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_service_Create(t *testing.T) {
+	repo := &mock.UserRepo{Users: []domain.User{{ID: 1, Username: "admin", Role: domain.UserRoleAdmin}}}
+	s := NewService(repo, mock.NewSessionRepo())
+
+	err := s.Create(context.Background(), domain.CreateUserRequest{Username: "viewer", Password: "secret", Role: domain.UserRoleViewer})
+	assert.NoError(t, err)
+	assert.Len(t, repo.Users, 2)
+	assert.NotEqual(t, "secret", repo.Users[1].Password)
+
+	err = s.Create(context.Background(), domain.CreateUserRequest{Username: "viewer", Password: "secret", Role: domain.UserRoleViewer})
+	assert.ErrorContains(t, err, "username already taken")
+
+	err = s.Create(context.Background(), domain.CreateUserRequest{Username: "owner", Password: "secret", Role: "owner"})
+	assert.ErrorContains(t, err, "invalid role")
+}
+
+func Test_service_LastAdmin(t *testing.T) {
+	repo := &mock.UserRepo{Users: []domain.User{
+		{ID: 1, Username: "admin", Role: domain.UserRoleAdmin},
+		{ID: 2, Username: "editor", Role: domain.UserRoleEditor},
+	}}
+	s := NewService(repo, mock.NewSessionRepo())
+
+	err := s.Update(context.Background(), domain.UpdateUserRequest{ID: 1, Role: domain.UserRoleViewer})
+	assert.ErrorContains(t, err, "last admin")
+
+	err = s.Delete(context.Background(), 1)
+	assert.ErrorContains(t, err, "last admin")
+
+	// promote the editor, then the first admin can be demoted
+	assert.NoError(t, s.Update(context.Background(), domain.UpdateUserRequest{ID: 2, Role: domain.UserRoleAdmin}))
+	assert.NoError(t, s.Update(context.Background(), domain.UpdateUserRequest{ID: 1, Role: domain.UserRoleViewer}))
+
+	u, err := s.FindByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.UserRoleViewer, u.Role)
+}
+
+func Test_service_LinkOIDC(t *testing.T) {
+	repo := &mock.UserRepo{Users: []domain.User{
+		{ID: 1, Username: "admin", Role: domain.UserRoleAdmin},
+		{ID: 2, Username: "editor", Role: domain.UserRoleEditor},
+	}}
+	s := NewService(repo, mock.NewSessionRepo())
+
+	assert.NoError(t, s.LinkOIDC(context.Background(), 1, "sub-1"))
+
+	u, err := s.FindByOIDCSubject(context.Background(), "sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, u.ID)
+
+	err = s.LinkOIDC(context.Background(), 2, "sub-1")
+	assert.ErrorContains(t, err, "already linked")
+
+	assert.NoError(t, s.LinkOIDC(context.Background(), 1, ""))
+
+	u, err = s.FindByOIDCSubject(context.Background(), "sub-1")
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func Test_service_Update_RevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	repo := &mock.UserRepo{Users: []domain.User{
+		{ID: 1, Username: "admin", Role: domain.UserRoleAdmin},
+		{ID: 2, Username: "editor", Role: domain.UserRoleEditor},
+	}}
+	sessions := mock.NewSessionRepo(
+		domain.Session{ID: "a", UserID: 1},
+		domain.Session{ID: "b", UserID: 2},
+		domain.Session{ID: "c", UserID: 2},
+	)
+	s := NewService(repo, sessions)
+
+	// promotions keep the sessions
+	assert.NoError(t, s.Update(ctx, domain.UpdateUserRequest{ID: 2, Role: domain.UserRoleAdmin}))
+	assert.Len(t, sessions.Sessions, 3)
+
+	assert.NoError(t, s.Update(ctx, domain.UpdateUserRequest{ID: 2, Role: domain.UserRoleViewer}))
+	assert.Len(t, sessions.Sessions, 1)
+	assert.Contains(t, sessions.Sessions, "a")
+
+	assert.NoError(t, s.Update(ctx, domain.UpdateUserRequest{ID: 1, Password: "new-password"}))
+	assert.Empty(t, sessions.Sessions)
+}
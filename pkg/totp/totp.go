@@ -0,0 +1,133 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+// Package totp implements time-based one-time passwords (RFC 6238)
+// compatible with the common authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is the time step in seconds
+	Period = 30
+
+	// Digits is the length of the generated codes
+	Digits = 6
+
+	// Skew is the number of periods before and after the current one that are accepted
+	Skew = 1
+)
+
+// ErrInvalidSecret is returned if the secret is not valid base32.
+var ErrInvalidSecret = errors.New("totp: secret is not valid base32")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random 160 bit secret encoded as base32.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// GenerateCode returns the code for the secret at time t.
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generate(key, counter(t), Digits), nil
+}
+
+// Validate checks the code against the secret at time t, allowing for Skew periods of clock drift.
+func Validate(code, secret string, t time.Time) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != Digits {
+		return false
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return false
+	}
+
+	c := counter(t)
+	for i := -Skew; i <= Skew; i++ {
+		if subtle.ConstantTimeCompare([]byte(generate(key, uint64(int64(c)+int64(i)), Digits)), []byte(code)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// URL returns the otpauth:// key uri that authenticator apps import, usually through a QR code.
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func URL(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(Period))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(secret, " ", ""), "="))
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return key, nil
+}
+
+func counter(t time.Time) uint64 {
+	return uint64(t.Unix() / Period)
+}
+
+// generate implements HOTP (RFC 4226) with dynamic truncation
+func generate(key []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+// SPDX-License-Identifier: GPL-2.0-or-later
+
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// test vectors for SHA1 from RFC 6238 appendix B
+func TestGenerate_RFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tc := range testCases {
+		got := generate(key, counter(time.Unix(tc.unix, 0)), 8)
+		if got != tc.want {
+			t.Errorf("generate(%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	code, err := GenerateCode(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code != "050471" {
+		t.Errorf("GenerateCode() = %s, want 050471", code)
+	}
+
+	testCases := []struct {
+		name   string
+		code   string
+		secret string
+		t      time.Time
+		want   bool
+	}{
+		{name: "current", code: code, secret: secret, t: now, want: true},
+		{name: "previous_period", code: code, secret: secret, t: now.Add(Period * time.Second), want: true},
+		{name: "next_period", code: code, secret: secret, t: now.Add(-Period * time.Second), want: true},
+		{name: "too_old", code: code, secret: secret, t: now.Add(3 * Period * time.Second), want: false},
+		{name: "spaces", code: "050 471", secret: secret, t: now, want: true},
+		{name: "wrong_code", code: "123456", secret: secret, t: now, want: false},
+		{name: "short_code", code: "05047", secret: secret, t: now, want: false},
+		{name: "invalid_secret", code: code, secret: "not base32!", t: now, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Validate(tc.code, tc.secret, tc.t); got != tc.want {
+				t.Errorf("Validate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	s1, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s1) != 32 {
+		t.Errorf("expected 32 characters, got %d", len(s1))
+	}
+
+	if s1 == s2 {
+		t.Error("expected secrets to differ")
+	}
+
+	if _, err := decodeSecret(s1); err != nil {
+		t.Errorf("secret should decode: %v", err)
+	}
+}
+
+func TestURL(t *testing.T) {
+	u, err := url.Parse(URL("autobrr", "alice", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/autobrr:alice" {
+		t.Errorf("unexpected url: %s", u)
+	}
+
+	if got := u.Query().Get("secret"); got != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("unexpected secret: %s", got)
+	}
+
+	if got := u.Query().Get("issuer"); got != "autobrr" {
+		t.Errorf("unexpected issuer: %s", got)
+	}
+}
@@ -21,7 +21,8 @@ export async function HttpClient<T>(
 ): Promise<T> {
   const config = {
     method: method,
-    body: body ? JSON.stringify(body) : undefined,
+    // files are sent as is
+    body: body instanceof Blob ? body : body ? JSON.stringify(body) : undefined,
     headers: {
       "Content-Type": "application/json"
     },
@@ -64,17 +65,34 @@ const appClient = {
 
 export const APIClient = {
   auth: {
-    login: (username: string, password: string) => appClient.Post("api/auth/login", {
+    login: (username: string, password: string) => appClient.Post<LoginResponse>("api/auth/login", {
       username: username,
       password: password
     }),
+    loginTwoFactor: (code: string) => appClient.Post("api/auth/login/2fa", { code }),
     logout: () => appClient.Post("api/auth/logout"),
-    validate: () => appClient.Get<void>("api/auth/validate"),
+    validate: () => appClient.Get<User>("api/auth/validate"),
     onboard: (username: string, password: string) => appClient.Post("api/auth/onboard", {
       username: username,
       password: password
     }),
-    canOnboard: () => appClient.Get("api/auth/onboard")
+    canOnboard: () => appClient.Get("api/auth/onboard"),
+    oidcConfig: () => appClient.Get<{ enabled: boolean }>("api/auth/oidc"),
+    oidcLink: {
+      status: () => appClient.Get<{ linked: boolean }>("api/auth/oidc/link"),
+      unlink: () => appClient.Delete("api/auth/oidc/link")
+    },
+    sessions: {
+      getAll: () => appClient.Get<Session[]>("api/auth/sessions"),
+      revoke: (id: string) => appClient.Delete(`api/auth/sessions/${id}`),
+      revokeOthers: () => appClient.Delete("api/auth/sessions")
+    },
+    twoFactor: {
+      status: () => appClient.Get<{ enabled: boolean }>("api/auth/2fa"),
+      setup: () => appClient.Post<TwoFactorSetup>("api/auth/2fa/setup"),
+      enable: (code: string) => appClient.Post<{ recovery_codes: string[] }>("api/auth/2fa/enable", { code }),
+      disable: (code: string) => appClient.Post("api/auth/2fa/disable", { code })
+    }
   },
   actions: {
     create: (action: Action) => appClient.Post("api/actions", action),
@@ -87,6 +105,19 @@ export const APIClient = {
     create: (key: APIKey) => appClient.Post("api/keys", key),
     delete: (key: string) => appClient.Delete(`api/keys/${key}`)
   },
+  backup: {
+    // used as a download link so the browser saves the file
+    exportUrl: (format: BackupFormat, redact: boolean) => `${baseUrl()}api/backup?format=${format}&redact=${redact}`,
+    import: (file: File) => HttpClient<BackupImportResult>("api/backup/import", "POST", {
+      body: file,
+      headers: { "Content-Type": "application/octet-stream" }
+    }),
+    database: {
+      getAll: () => appClient.Get<DatabaseBackup[]>("api/backup/database"),
+      create: () => appClient.Post<DatabaseBackup>("api/backup/database"),
+      downloadUrl: (name: string) => `${baseUrl()}api/backup/database/${encodeURIComponent(name)}`
+    }
+  },
   config: {
     get: () => appClient.Get<Config>("api/config"),
     update: (config: ConfigUpdate) => appClient.Patch("api/config", config)
@@ -121,7 +152,15 @@ export const APIClient = {
     getByID: (id: number) => appClient.Get<Filter>(`api/filters/${id}`),
     create: (filter: Filter) => appClient.Post<Filter>("api/filters", filter),
     update: (filter: Filter) => appClient.Put<Filter>(`api/filters/${filter.id}`, filter),
-    duplicate: (id: number) => appClient.Get<Filter>(`api/filters/${id}/duplicate`),
+    duplicate: (id: number) => appClient.Post<Filter>(`api/filters/${id}/duplicate`),
+    // used as a download link, exports all filters when no ids are given
+    exportUrl: (ids: number[] = []) => {
+      const params = new URLSearchParams();
+      ids.forEach((id) => params.append("id", id.toString()));
+
+      return `${baseUrl()}api/filters/export?${params.toString()}`;
+    },
+    import: (data: FilterExport) => appClient.Post<FilterImportResult>("api/filters/import", data),
     toggleEnable: (id: number, enabled: boolean) => appClient.Put(`api/filters/${id}/enabled`, { enabled }),
     delete: (id: number) => appClient.Delete(`api/filters/${id}`)
   },
@@ -150,7 +189,7 @@ export const APIClient = {
     createNetwork: (network: IrcNetworkCreate) => appClient.Post("api/irc", network),
     updateNetwork: (network: IrcNetwork) => appClient.Put(`api/irc/network/${network.id}`, network),
     deleteNetwork: (id: number) => appClient.Delete(`api/irc/network/${id}`),
-    restartNetwork: (id: number) => appClient.Get(`api/irc/network/${id}/restart`)
+    restartNetwork: (id: number) => appClient.Post(`api/irc/network/${id}/restart`)
   },
   logs: {
     files: () => appClient.Get<LogFileResponse>("api/logs/files"),
@@ -164,7 +203,8 @@ export const APIClient = {
     create: (notification: Notification) => appClient.Post("api/notification", notification),
     update: (notification: Notification) => appClient.Put(`api/notification/${notification.id}`, notification),
     delete: (id: number) => appClient.Delete(`api/notification/${id}`),
-    test: (n: Notification) => appClient.Post("api/notification/test", n)
+    test: (n: Notification) => appClient.Post("api/notification/test", n),
+    preview: (n: Notification) => appClient.Post<NotificationPreview[]>("api/notification/preview", n)
   },
   release: {
     find: (query?: string) => appClient.Get<ReleaseFindResponse>(`api/release${query}`),
@@ -193,10 +233,18 @@ export const APIClient = {
     },
     indexerOptions: () => appClient.Get<string[]>("api/release/indexers"),
     stats: () => appClient.Get<ReleaseStats>("api/release/stats"),
-    delete: () => appClient.Delete("api/release/all")
+    delete: () => appClient.Delete("api/release/all"),
+    prune: (rule: ReleasePruneRule) => appClient.Post<ReleasePruneResponse>("api/release/prune", rule)
   },
   updates: {
     check: () => appClient.Get("api/updates/check"),
     getLatestRelease: () => appClient.Get<GithubRelease | undefined>("api/updates/latest")
+  },
+  users: {
+    getAll: () => appClient.Get<User[]>("api/users"),
+    create: (user: UserCreate) => appClient.Post("api/users", user),
+    update: (user: UserUpdate) => appClient.Put(`api/users/${user.id}`, user),
+    delete: (id: number) => appClient.Delete(`api/users/${id}`),
+    audit: (limit = 50) => appClient.Get<AuditEvent[]>(`api/users/audit?limit=${limit}`)
   }
 };
@@ -354,6 +354,11 @@ export const ActionRtorrentRenameOptions: SelectGenericOption<ActionContentLayou
   { label: "Yes", description: "Yes", value: "SUBFOLDER_NONE" }
 ];
 
+export const ActionCrossSeedOptions: SelectGenericOption<ActionCrossSeed>[] = [
+  { label: "Skip", description: "Skip if the content already exists in the client", value: "SKIP" },
+  { label: "Inject", description: "Add pointing at the existing content and recheck", value: "INJECT" }
+];
+
 export interface OptionBasic {
   label: string;
   value: string;
@@ -395,6 +400,42 @@ export const NotificationTypeOptions: OptionBasicTyped<NotificationType>[] = [
   {
     label: "Pushover",
     value: "PUSHOVER"
+  },
+  {
+    label: "Slack",
+    value: "SLACK"
+  },
+  {
+    label: "Mattermost",
+    value: "MATTERMOST"
+  },
+  {
+    label: "Rocket.Chat",
+    value: "ROCKETCHAT"
+  },
+  {
+    label: "Matrix",
+    value: "MATRIX"
+  },
+  {
+    label: "Pushbullet",
+    value: "PUSH_BULLET"
+  },
+  {
+    label: "IFTTT",
+    value: "IFTTT"
+  },
+  {
+    label: "Join",
+    value: "JOIN"
+  },
+  {
+    label: "ntfy",
+    value: "NTFY"
+  },
+  {
+    label: "Gotify",
+    value: "GOTIFY"
   }
 ];
 
@@ -440,6 +481,21 @@ export const downloadsPerUnitOptions: OptionBasic[] = [
   }
 ];
 
+export const episodeTypeOptions: OptionBasic[] = [
+  {
+    label: "Any",
+    value: ""
+  },
+  {
+    label: "Season packs only",
+    value: "SEASON_PACK"
+  },
+  {
+    label: "Single episodes only",
+    value: "EPISODE"
+  }
+];
+
 export const DownloadRuleConditionOptions: OptionBasic[] = [
   {
     label: "Always",
@@ -483,6 +539,11 @@ export const EventOptions: SelectOption[] = [
     value: "PUSH_ERROR",
     description: "On push error for the arrs or download client"
   },
+  {
+    label: "Push Retries Failed",
+    value: "PUSH_RETRY_FAILED",
+    description: "When the last retry of a failed push errors"
+  },
   {
     label: "IRC Disconnected",
     value: "IRC_DISCONNECTED",
@@ -497,6 +558,26 @@ export const EventOptions: SelectOption[] = [
     label: "New update",
     value: "APP_UPDATE_AVAILABLE",
     description: "Get notified on updates"
+  },
+  {
+    label: "IRC channel quiet",
+    value: "IRC_CHANNEL_QUIET",
+    description: "Get notified when a monitored channel has not announced for a while"
+  },
+  {
+    label: "Feed failed",
+    value: "FEED_FAILED",
+    description: "Get notified when a feed fails several times in a row"
+  },
+  {
+    label: "Download client error",
+    value: "DOWNLOAD_CLIENT_ERROR",
+    description: "Get notified when a download client can not be reached"
+  },
+  {
+    label: "Daily digest",
+    value: "DAILY_DIGEST",
+    description: "Get a daily summary of grabbed releases"
   }
 ];
 
@@ -521,3 +602,28 @@ export const tagsMatchLogicOptions: OptionBasic[] = [
     value: "ALL"
   }
 ];
+
+// sensitive resources hold credentials and can only be read with write access
+const APIKeyScopeResources = [
+  { value: "actions", label: "Actions", sensitive: true },
+  { value: "config", label: "Config", sensitive: true },
+  { value: "download_clients", label: "Download clients", sensitive: true },
+  { value: "events", label: "Events" },
+  { value: "feeds", label: "Feeds", sensitive: true },
+  { value: "filters", label: "Filters" },
+  { value: "indexers", label: "Indexers", sensitive: true },
+  { value: "irc", label: "IRC", sensitive: true },
+  { value: "logs", label: "Logs", sensitive: true },
+  { value: "metrics", label: "Metrics" },
+  { value: "notifications", label: "Notifications", sensitive: true },
+  { value: "releases", label: "Releases" },
+  { value: "updates", label: "Updates" }
+];
+
+export const APIKeyScopeOptions: MultiSelectOption[] = [
+  { value: "admin", label: "Admin (full access)" },
+  ...APIKeyScopeResources.flatMap((r) => [
+    ...(r.sensitive ? [] : [{ value: `${r.value}:read`, label: `${r.label}: read` }]),
+    { value: `${r.value}:write`, label: `${r.label}: read/write` }
+  ])
+];
@@ -14,15 +14,18 @@ import { Logs } from "@screens/Logs";
 import { Releases } from "@screens/releases";
 import Settings from "@screens/Settings";
 import {
+  AccountSettings,
   APISettings,
   ApplicationSettings,
+  BackupSettings,
   DownloadClientSettings,
   FeedSettings,
   IndexerSettings,
   IrcSettings,
   LogSettings,
   NotificationSettings,
-  ReleaseSettings
+  ReleaseSettings,
+  UserSettings
 } from "@screens/settings/index";
 import { RegexPlayground } from "@screens/settings/RegexPlayground";
 import { NotFound } from "@components/alerts/NotFound";
@@ -46,12 +49,15 @@ export const LocalRouter = ({ isLoggedIn }: { isLoggedIn: boolean }) => (
             <Route index element={<ApplicationSettings />} />
             <Route path="logs" element={<LogSettings />} />
             <Route path="api-keys" element={<APISettings />} />
+            <Route path="users" element={<UserSettings />} />
+            <Route path="account" element={<AccountSettings />} />
             <Route path="indexers" element={<IndexerSettings />} />
             <Route path="feeds" element={<FeedSettings />} />
             <Route path="irc" element={<IrcSettings />} />
             <Route path="clients" element={<DownloadClientSettings />} />
             <Route path="notifications" element={<NotificationSettings />} />
             <Route path="releases" element={<ReleaseSettings />} />
+            <Route path="backup" element={<BackupSettings />} />
             <Route path="regex-playground" element={<RegexPlayground />} />
           </Route>
         </Route>
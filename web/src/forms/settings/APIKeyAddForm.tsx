@@ -13,6 +13,8 @@ import { Field, Form, Formik, FormikErrors, FormikValues } from "formik";
 
 import { APIClient } from "@api/APIClient";
 import DEBUG from "@components/debug";
+import { MultiSelect } from "@components/inputs";
+import { APIKeyScopeOptions } from "@domain/constants";
 import Toast from "@components/notifications/Toast";
 import { apiKeys } from "@screens/settings/Api";
 
@@ -123,6 +125,24 @@ function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
                               )}
                             </Field>
                           </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label
+                                htmlFor="scopes"
+                                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+                              >
+                                Scopes
+                              </label>
+                              <p className="text-xs text-gray-500 dark:text-gray-400">
+                                Leave empty for full access.
+                              </p>
+                            </div>
+                            <div className="sm:col-span-2">
+                              <MultiSelect name="scopes" options={APIKeyScopeOptions} />
+                            </div>
+                          </div>
                         </div>
                       </div>
 
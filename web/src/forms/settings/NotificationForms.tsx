@@ -4,13 +4,14 @@
  */
 
 import { Dialog, Transition } from "@headlessui/react";
-import { Fragment } from "react";
+import { Fragment, useState } from "react";
 import type { FieldProps } from "formik";
-import { Field, Form, Formik, FormikErrors, FormikValues } from "formik";
+import { Field, Form, Formik, FormikErrors, FormikValues, useFormikContext } from "formik";
 import { XMarkIcon } from "@heroicons/react/24/solid";
 import Select, { components, ControlProps, InputProps, MenuProps, OptionProps } from "react-select";
-import { useMutation, useQueryClient } from "@tanstack/react-query";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { toast } from "react-hot-toast";
+import { MultiSelect as RMSC } from "react-multi-select-component";
 
 import { NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide } from "@components/inputs";
 import DEBUG from "@components/debug";
@@ -20,6 +21,8 @@ import Toast from "@components/notifications/Toast";
 import { SlideOver } from "@components/panels";
 import { componentMapType } from "./DownloadClientForms";
 import { notificationKeys } from "@screens/settings/Notifications";
+import { MultiSelectOption } from "@components/inputs/select";
+import { SettingsContext } from "@utils/Context";
 
 const Input = (props: InputProps) => {
   return (
@@ -155,11 +158,319 @@ function FormFieldsPushover() {
   );
 }
 
+function FormFieldsSlack() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Create an <a href="https://api.slack.com/messaging/webhooks" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">incoming webhook</a> in your workspace.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Slack incoming webhook url"
+        placeholder="https://hooks.slack.com/services/xx/xx/xx"
+      />
+      <TextFieldWide
+        name="channel"
+        label="Channel"
+        help="Optional channel override"
+        placeholder="#autobrr"
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+        help="Optional username override"
+      />
+      <TextFieldWide
+        name="icon"
+        label="Icon"
+        help="Optional emoji like :robot_face: or image url"
+      />
+    </div>
+  );
+}
+
+function FormFieldsMattermost() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Create an <a href="https://developers.mattermost.com/integrate/webhooks/incoming/" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">incoming webhook</a> in your team.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Mattermost incoming webhook url"
+        placeholder="https://mattermost.example.com/hooks/xx"
+      />
+      <TextFieldWide
+        name="channel"
+        label="Channel"
+        help="Optional channel override"
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+        help="Optional username override"
+      />
+      <TextFieldWide
+        name="icon"
+        label="Icon URL"
+        help="Optional icon image url"
+      />
+    </div>
+  );
+}
+
+function FormFieldsRocketChat() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Create an <a href="https://docs.rocket.chat/use-rocket.chat/workspace-administration/integrations" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">incoming webhook integration</a> in your workspace.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="webhook"
+        label="Webhook URL"
+        help="Rocket.Chat incoming webhook url"
+        placeholder="https://rocketchat.example.com/hooks/xx/xx"
+      />
+      <TextFieldWide
+        name="channel"
+        label="Channel"
+        help="Optional channel override"
+        placeholder="#autobrr"
+      />
+      <TextFieldWide
+        name="username"
+        label="Alias"
+        help="Optional name shown for the messages"
+      />
+      <TextFieldWide
+        name="icon"
+        label="Avatar"
+        help="Optional emoji like :robot: or avatar url"
+      />
+    </div>
+  );
+}
+
+function FormFieldsMatrix() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Use the access token of a user that has joined the rooms.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="Homeserver"
+        help="Homeserver url"
+        placeholder="https://matrix.org"
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Access token"
+        help="Access token"
+      />
+      <TextFieldWide
+        name="rooms"
+        label="Rooms"
+        help="Comma separated room ids"
+        placeholder="!xxxx:matrix.org"
+      />
+    </div>
+  );
+}
+
+function FormFieldsPushBullet() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Create an access token in your <a href="https://www.pushbullet.com/#settings/account" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">account settings</a>.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="api_key"
+        label="Access token"
+        help="Access token"
+      />
+      <TextFieldWide
+        name="devices"
+        label="Devices"
+        help="Optional comma separated device idens, pushes to all devices if empty"
+      />
+      <TextFieldWide
+        name="channel"
+        label="Channel tag"
+        help="Optional channel tag to push to subscribers"
+      />
+    </div>
+  );
+}
+
+function FormFieldsIFTTT() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Connect the <a href="https://ifttt.com/maker_webhooks" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">Webhooks service</a>. Title, message and event are sent as value1, value2 and value3.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="api_key"
+        label="Webhook key"
+        help="Webhook key"
+      />
+      <TextFieldWide
+        name="targets"
+        label="Event names"
+        help="Comma separated event names to trigger"
+        placeholder="autobrr"
+      />
+    </div>
+  );
+}
+
+function FormFieldsJoin() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Get your API key from the <a href="https://joinjoaomgcd.appspot.com/" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">Join web app</a>.
+        </p>
+      </div>
+
+      <PasswordFieldWide
+        name="api_key"
+        label="API Key"
+        help="API Key"
+      />
+      <TextFieldWide
+        name="devices"
+        label="Devices"
+        help="Optional comma separated device ids or groups, pushes to all devices if empty"
+        placeholder="group.all"
+      />
+      <TextFieldWide
+        name="icon"
+        label="Icon URL"
+        help="Optional notification icon url"
+      />
+    </div>
+  );
+}
+
+function FormFieldsNtfy() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Publish to a <a href="https://docs.ntfy.sh/publish/" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">ntfy topic</a> on ntfy.sh or your own server.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="Server URL"
+        help="Defaults to https://ntfy.sh"
+        placeholder="https://ntfy.sh"
+      />
+      <TextFieldWide
+        name="channel"
+        label="Topic"
+        help="Topic to publish to"
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Access token"
+        help="Optional access token for protected topics"
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+        help="Optional username, used if no access token is set"
+      />
+      <PasswordFieldWide
+        name="password"
+        label="Password"
+        help="Optional password"
+      />
+      <NumberFieldWide
+        name="priority"
+        label="Priority"
+        help="-2 (min), -1, 0 (default), 1, or 2 (max)"
+        required={true}
+      />
+    </div>
+  );
+}
+
+function FormFieldsGotify() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Create an application in Gotify and add its token here.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="Server URL"
+        help="Gotify server url"
+        placeholder="https://gotify.example.com"
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Application token"
+        help="Application token"
+      />
+      <NumberFieldWide
+        name="priority"
+        label="Priority"
+        help="-2 (silent), -1 (low), 0 (default), 1 (high), or 2 (max)"
+        required={true}
+      />
+    </div>
+  );
+}
+
 const componentMap: componentMapType = {
   DISCORD: <FormFieldsDiscord />,
   NOTIFIARR: <FormFieldsNotifiarr />,
   TELEGRAM: <FormFieldsTelegram />,
-  PUSHOVER: <FormFieldsPushover />
+  PUSHOVER: <FormFieldsPushover />,
+  SLACK: <FormFieldsSlack />,
+  MATTERMOST: <FormFieldsMattermost />,
+  ROCKETCHAT: <FormFieldsRocketChat />,
+  MATRIX: <FormFieldsMatrix />,
+  PUSH_BULLET: <FormFieldsPushBullet />,
+  IFTTT: <FormFieldsIFTTT />,
+  JOIN: <FormFieldsJoin />,
+  NTFY: <FormFieldsNtfy />,
+  GOTIFY: <FormFieldsGotify />
 };
 
 interface NotificationAddFormValues {
@@ -351,6 +662,8 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                           </div>
                         </div>
                         {componentMap[values.type]}
+                        {values.type && <ScopeFields />}
+                        {values.type && <TemplateFields />}
                       </div>
 
                       <div className="flex-shrink-0 px-4 border-t border-gray-200 dark:border-gray-700 py-4 sm:px-6">
@@ -420,6 +733,151 @@ const EventCheckBoxes = () => (
   </fieldset>
 );
 
+interface ScopeSelectProps {
+  name: string;
+  label: string;
+  help: string;
+  options: MultiSelectOption[];
+}
+
+// ScopeSelect stores only the option values, and looks up the labels from the options
+function ScopeSelect({ name, label, help, options }: ScopeSelectProps) {
+  const settingsContext = SettingsContext.useValue();
+
+  return (
+    <div className="space-y-1 p-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4">
+      <div>
+        <label htmlFor={name} className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+          {label}
+        </label>
+      </div>
+      <div className="sm:col-span-2">
+        <Field name={name} type="select" multiple={true}>
+          {({ field, form: { setFieldValue } }: FieldProps) => (
+            <RMSC
+              options={options}
+              labelledBy={name}
+              value={options.filter((o) => (field.value ?? []).includes(o.value))}
+              onChange={(values: MultiSelectOption[]) => setFieldValue(field.name, values.map((v) => v.value))}
+              overrideStrings={{ selectSomeItems: "All" }}
+              className={settingsContext.darkTheme ? "dark" : ""}
+            />
+          )}
+        </Field>
+        <p className="mt-2 text-sm text-gray-500 dark:text-gray-400">{help}</p>
+      </div>
+    </div>
+  );
+}
+
+function ScopeFields() {
+  const { data: filters } = useQuery({
+    queryKey: ["notifications", "filter_options"],
+    queryFn: () => APIClient.filters.getAll(),
+    refetchOnWindowFocus: false
+  });
+
+  const { data: indexers } = useQuery({
+    queryKey: ["notifications", "indexer_options"],
+    queryFn: () => APIClient.indexers.getOptions(),
+    refetchOnWindowFocus: false
+  });
+
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Scope</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Optionally only send release events for some filters and indexers. Other events are always sent.
+        </p>
+      </div>
+
+      <ScopeSelect
+        name="filters"
+        label="Filters"
+        help="Leave empty for all filters"
+        options={(filters ?? []).map((f) => ({ value: f.id, label: f.name }))}
+      />
+      <ScopeSelect
+        name="indexers"
+        label="Indexers"
+        help="Leave empty for all indexers"
+        options={(indexers ?? []).map((i) => ({ value: i.identifier, label: i.name }))}
+      />
+    </div>
+  );
+}
+
+function TemplateFields() {
+  const { values } = useFormikContext<Notification>();
+  const [previews, setPreviews] = useState<NotificationPreview[]>([]);
+
+  const previewMutation = useMutation({
+    mutationFn: (n: Notification) => APIClient.notifications.preview(n),
+    onSuccess: (data) => setPreviews(data),
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Templates could not be parsed" t={t} />);
+    }
+  });
+
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Templates</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Optionally replace the default message with a <a href="https://pkg.go.dev/text/template" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">Go template</a>. Use fields like {"{{ .ReleaseName }}"}, {"{{ .Indexer }}"}, {"{{ .Filter }}"} and {"{{ .SizeString }}"}, and the <a href="https://masterminds.github.io/sprig/" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">sprig functions</a>.
+        </p>
+      </div>
+
+      {EventOptions.filter((e) => values.events.includes(e.value as NotificationEvent)).map((e) => (
+        <div key={e.value}>
+          <TextFieldWide
+            name={`templates.${e.value}.title`}
+            label={`${e.label} title`}
+            placeholder="Default title"
+          />
+          <div className="space-y-1 p-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4">
+            <label htmlFor={`templates.${e.value}.body`} className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+              {`${e.label} body`}
+            </label>
+            <div className="sm:col-span-2">
+              <Field
+                as="textarea"
+                id={`templates.${e.value}.body`}
+                name={`templates.${e.value}.body`}
+                rows={3}
+                placeholder="Default message"
+                className="block w-full shadow-sm sm:text-sm rounded-md border-gray-300 dark:border-gray-700 focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 dark:bg-gray-800 dark:text-gray-100"
+              />
+            </div>
+          </div>
+        </div>
+      ))}
+
+      <div className="px-4 flex justify-end">
+        <button
+          type="button"
+          className="bg-white dark:bg-gray-700 py-2 px-4 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-200 hover:bg-gray-50 dark:hover:bg-gray-600 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+          onClick={() => previewMutation.mutate(values)}
+        >
+          Preview
+        </button>
+      </div>
+
+      {previews.filter((p) => values.events.includes(p.event as NotificationEvent)).map((p) => (
+        <div key={p.event} className="mx-4 mt-4 p-3 rounded-md bg-gray-100 dark:bg-gray-900 text-sm">
+          <p className="font-medium text-gray-900 dark:text-white">{p.title}</p>
+          {p.error ? (
+            <p className="text-red-500">{p.error}</p>
+          ) : (
+            <p className="whitespace-pre-wrap text-gray-600 dark:text-gray-400">{p.body}</p>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+}
+
 interface UpdateProps {
     isOpen: boolean;
     toggle: () => void;
@@ -436,7 +894,17 @@ interface InitialValues {
   api_key?: string;
   priority?: number;
   channel?: string;
+  icon?: string;
+  username?: string;
+  password?: string;
+  host?: string;
+  rooms?: string;
+  targets?: string;
+  devices?: string;
+  templates?: NotificationTemplates;
   events: NotificationEvent[];
+  filters?: number[];
+  indexers?: string[];
 }
 
 export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateProps) {
@@ -484,7 +952,17 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
     api_key: notification.api_key,
     priority: notification.priority,
     channel: notification.channel,
-    events: notification.events || []
+    icon: notification.icon,
+    username: notification.username,
+    password: notification.password,
+    host: notification.host,
+    rooms: notification.rooms,
+    targets: notification.targets,
+    devices: notification.devices,
+    templates: notification.templates,
+    events: notification.events || [],
+    filters: notification.filters || [],
+    indexers: notification.indexers || []
   };
 
   return (
@@ -563,6 +1041,8 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
             </div>
           </div>
           {componentMap[values.type]}
+          {values.type && <ScopeFields />}
+          {values.type && <TemplateFields />}
         </div>
       )}
     </SlideOver>
@@ -5,6 +5,7 @@
 
 import { NavLink, Outlet, useLocation } from "react-router-dom";
 import {
+  ArchiveBoxIcon,
   BellIcon,
   ChatBubbleLeftRightIcon,
   CogIcon,
@@ -12,7 +13,9 @@ import {
   KeyIcon,
   RectangleStackIcon,
   RssIcon,
-  Square3Stack3DIcon
+  ShieldCheckIcon,
+  Square3Stack3DIcon,
+  UsersIcon
 } from "@heroicons/react/24/outline";
 
 import { classNames } from "@utils";
@@ -32,7 +35,10 @@ const subNavigation: NavTabType[] = [
   { name: "Clients", href: "clients", icon: FolderArrowDownIcon },
   { name: "Notifications", href: "notifications", icon: BellIcon },
   { name: "API keys", href: "api-keys", icon: KeyIcon },
-  { name: "Releases", href: "releases", icon: RectangleStackIcon }
+  { name: "Users", href: "users", icon: UsersIcon },
+  { name: "Account", href: "account", icon: ShieldCheckIcon },
+  { name: "Releases", href: "releases", icon: RectangleStackIcon },
+  { name: "Backup", href: "backup", icon: ArchiveBoxIcon }
   // {name: 'Regex Playground', href: 'regex-playground', icon: CogIcon, current: false}
   // {name: 'Rules', href: 'rules', icon: ClipboardCheckIcon, current: false},
 ];
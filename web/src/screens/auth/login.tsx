@@ -3,16 +3,17 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-import { useEffect } from "react";
+import { useEffect, useState } from "react";
 import { useForm } from "react-hook-form";
-import { useNavigate } from "react-router-dom";
-import { useMutation } from "@tanstack/react-query";
+import { useNavigate, useSearchParams } from "react-router-dom";
+import { useMutation, useQuery } from "@tanstack/react-query";
 import toast from "react-hot-toast";
 import { Tooltip } from "react-tooltip";
 
 import logo from "@app/logo.png";
 import { APIClient } from "@api/APIClient";
 import { AuthContext } from "@utils/Context";
+import { baseUrl } from "@utils";
 import { PasswordInput, TextInput } from "@components/inputs/text";
 import Toast from "@components/notifications/Toast";
 
@@ -27,9 +28,36 @@ export const Login = () => {
     mode: "onBlur"
   });
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
   const [, setAuthContext] = AuthContext.use();
+  const [twoFactorUser, setTwoFactorUser] = useState("");
+
+  const { data: oidc } = useQuery({
+    queryKey: ["auth", "oidc"],
+    queryFn: APIClient.auth.oidcConfig,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
 
   useEffect(() => {
+    // coming back from the identity provider with a fresh session
+    if (searchParams.get("oidc") === "success") {
+      APIClient.auth.validate()
+        .then((user) => {
+          setAuthContext({
+            username: user.username,
+            isLoggedIn: true
+          });
+          navigate("/");
+        })
+        .catch(() => {
+          toast.custom((t) => (
+            <Toast type="error" body="Could not sign in with OpenID Connect" t={t} />
+          ));
+        });
+      return;
+    }
+
     // remove user session when visiting login page'
     APIClient.auth.logout()
       .then(() => {
@@ -45,22 +73,48 @@ export const Login = () => {
 
   const loginMutation = useMutation({
     mutationFn: (data: LoginFormFields) => APIClient.auth.login(data.username, data.password),
-    onSuccess: (_, variables: LoginFormFields) => {
+    onSuccess: (data, variables: LoginFormFields) => {
+      // password was correct, ask for the code before the session is authenticated
+      if (data?.two_factor_required) {
+        setTwoFactorUser(variables.username);
+        return;
+      }
+
       setAuthContext({
         username: variables.username,
         isLoggedIn: true
       });
       navigate("/");
     },
-    onError: () => {
+    onError: (err: Error) => {
+      // locked out after too many failed attempts
+      const body = err.message.includes("too many failed login attempts")
+        ? "Too many failed login attempts, try again later!"
+        : "Wrong password or username!";
+
       toast.custom((t) => (
-        <Toast type="error" body="Wrong password or username!" t={t} />
+        <Toast type="error" body={body} t={t} />
       ));
     }
   });
 
   const onSubmit = (data: LoginFormFields) => loginMutation.mutate(data);
 
+  if (twoFactorUser) {
+    return (
+      <TwoFactorForm
+        onSuccess={() => {
+          setAuthContext({
+            username: twoFactorUser,
+            isLoggedIn: true
+          });
+          navigate("/");
+        }}
+        onCancel={() => setTwoFactorUser("")}
+      />
+    );
+  }
+
   return (
     <div className="min-h-screen flex flex-col justify-center py-12 sm:px-6 lg:px-8">
       <div className="sm:mx-auto sm:w-full sm:max-w-md mb-6">
@@ -101,6 +155,14 @@ export const Login = () => {
               >
                 Sign in
               </button>
+              {oidc?.enabled && (
+                <a
+                  href={`${baseUrl()}api/auth/oidc/login`}
+                  className="mt-3 w-full flex justify-center py-2 px-4 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+                >
+                  Sign in with OpenID Connect
+                </a>
+              )}
               <div>
                 <span className="flex float-right items-center mt-3 text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide cursor-pointer" id="forgot">
                   Forgot?<svg className="ml-1 w-3 h-3 text-gray-500 dark:text-gray-400 fill-current" viewBox="0 0 72 72"><path d="M32 2C15.432 2 2 15.432 2 32s13.432 30 30 30s30-13.432 30-30S48.568 2 32 2m5 49.75H27v-24h10v24m-5-29.5a5 5 0 1 1 0-10a5 5 0 0 1 0 10"/></svg>
@@ -114,3 +176,78 @@ export const Login = () => {
     </div>
   );
 };
+
+type TwoFactorFormFields = {
+  code: string;
+};
+
+interface TwoFactorFormProps {
+  onSuccess: () => void;
+  onCancel: () => void;
+}
+
+const TwoFactorForm = ({ onSuccess, onCancel }: TwoFactorFormProps) => {
+  const { handleSubmit, register, formState } = useForm<TwoFactorFormFields>({
+    defaultValues: { code: "" },
+    mode: "onBlur"
+  });
+
+  const mutation = useMutation({
+    mutationFn: (data: TwoFactorFormFields) => APIClient.auth.loginTwoFactor(data.code),
+    onSuccess: onSuccess,
+    onError: (err: Error) => {
+      const body = err.message.includes("too many failed login attempts")
+        ? "Too many failed login attempts, try again later!"
+        : "Invalid two-factor code!";
+
+      toast.custom((t) => (
+        <Toast type="error" body={body} t={t} />
+      ));
+    }
+  });
+
+  return (
+    <div className="min-h-screen flex flex-col justify-center py-12 sm:px-6 lg:px-8">
+      <div className="sm:mx-auto sm:w-full sm:max-w-md mb-6">
+        <img className="mx-auto h-12 w-auto" src={logo} alt="logo"/>
+        <h1 className="text-center text-gray-900 dark:text-gray-200 font-bold pt-2 text-2xl">
+          autobrr
+        </h1>
+      </div>
+      <div className="sm:mx-auto sm:w-full sm:max-w-md shadow-lg">
+        <div className="bg-white dark:bg-gray-800 py-10 px-4 sm:rounded-lg sm:px-10">
+          <form onSubmit={handleSubmit((data) => mutation.mutate(data))}>
+            <div className="space-y-6">
+              <TextInput<TwoFactorFormFields>
+                name="code"
+                id="code"
+                label="authenticator or recovery code"
+                type="text"
+                register={register}
+                rules={{ required: "Code is required" }}
+                errors={formState.errors}
+                autoComplete="one-time-code"
+              />
+            </div>
+
+            <div className="mt-6">
+              <button
+                type="submit"
+                className="w-full flex justify-center py-2 px-4 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500"
+              >
+                Verify
+              </button>
+              <button
+                type="button"
+                onClick={onCancel}
+                className="mt-3 w-full flex justify-center text-sm text-gray-500 dark:text-gray-400 hover:underline"
+              >
+                Back
+              </button>
+            </div>
+          </form>
+        </div>
+      </div>
+    </div>
+  );
+};
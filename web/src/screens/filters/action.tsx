@@ -12,6 +12,7 @@ import { Link } from "react-router-dom";
 
 import {
   ActionContentLayoutOptions,
+  ActionCrossSeedOptions,
   ActionRtorrentRenameOptions,
   ActionTypeNameMap,
   ActionTypeOptions
@@ -68,6 +69,8 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
     webhook_method: "",
     webhook_data: "",
     webhook_headers: [],
+    retry_attempts: 0,
+    retry_interval: 60,
     client_id: 0
   };
 
@@ -645,6 +648,36 @@ function FilterActionsItem({ action, clients, idx, initialEdit, remove }: Filter
 
             <TypeForm action={action} clients={clients} idx={idx}/>
 
+            {["QBITTORRENT", "DELUGE_V1", "DELUGE_V2", "RTORRENT", "TRANSMISSION", "PORLA"].includes(action.type) && (
+              <CollapsableSection title="Cross-seed" subtitle="Check the client for existing content">
+                <div className="col-span-6">
+                  <Select
+                    name={`actions.${idx}.cross_seed`}
+                    label="Existing content"
+                    optionDefaultText="Don't check"
+                    options={ActionCrossSeedOptions}
+                  />
+                </div>
+              </CollapsableSection>
+            )}
+
+            <CollapsableSection title="Retry" subtitle="Retry failed pushes">
+              <div className="col-span-12">
+                <div className="mt-6 grid grid-cols-12 gap-6">
+                  <NumberField
+                    name={`actions.${idx}.retry_attempts`}
+                    label="Retry attempts"
+                    placeholder="0 disables retries"
+                  />
+                  <NumberField
+                    name={`actions.${idx}.retry_interval`}
+                    label="Retry interval. Wait X seconds, doubled every attempt"
+                    placeholder="60 is default"
+                  />
+                </div>
+              </div>
+            </CollapsableSection>
+
             <div className="pt-6 divide-y divide-gray-200">
               <div className="mt-4 pt-4 flex justify-between">
                 <button
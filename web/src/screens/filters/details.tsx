@@ -16,6 +16,7 @@ import {
   CODECS_OPTIONS,
   CONTAINER_OPTIONS,
   downloadsPerUnitOptions,
+  episodeTypeOptions,
   FORMATS_OPTIONS,
   HDR_OPTIONS,
   LANGUAGE_OPTIONS,
@@ -184,6 +185,9 @@ const actionSchema = z.object({
   reannounce_delete: z.boolean().optional(),
   reannounce_interval: z.number().optional(),
   reannounce_max_attempts: z.number().optional(),
+  retry_attempts: z.number().optional(),
+  retry_interval: z.number().optional(),
+  cross_seed: z.string().optional(),
   webhook_host: z.string().optional(),
   webhook_type: z.string().optional(),
   webhook_method: z.string().optional(),
@@ -342,7 +346,10 @@ export default function FilterDetails() {
                 except_other: filter.except_other || [],
                 seasons: filter.seasons,
                 episodes: filter.episodes,
+                episode_type: filter.episode_type,
                 smart_episode: filter.smart_episode,
+                smart_episode_upgrade: filter.smart_episode_upgrade,
+                allow_dupe_other_indexers: filter.allow_dupe_other_indexers,
                 match_releases: filter.match_releases,
                 except_releases: filter.except_releases,
                 match_release_groups: filter.match_release_groups,
@@ -468,12 +475,15 @@ export function MoviesTv() {
         <TitleSubtitle title="Seasons and Episodes" subtitle="Set season and episode match constraints." />
 
         <div className="mt-6 grid grid-cols-12 gap-6">
-          <TextField name="seasons" label="Seasons" columns={8} placeholder="eg. 1,3,2-6"  tooltip={<div><p>See docs for information about how to <b>only</b> grab season packs:</p><a href='https://autobrr.com/filters/examples#only-season-packs' className='text-blue-400 visited:text-blue-400' target='_blank'>https://autobrr.com/filters/examples#only-season-packs</a></div>} />
-          <TextField name="episodes" label="Episodes" columns={4} placeholder="eg. 2,4,10-20"  tooltip={<div><p>See docs for information about how to <b>only</b> grab episodes:</p><a href='https://autobrr.com/filters/examples/#skip-season-packs' className='text-blue-400 visited:text-blue-400' target='_blank'>https://autobrr.com/filters/examples/#skip-season-packs</a></div>} />
+          <TextField name="seasons" label="Seasons" columns={4} placeholder="eg. 1,3,2-6"  tooltip={<div><p>Daily shows numbered by air date match the year as season.</p><p>See docs for information about how to <b>only</b> grab season packs:</p><a href='https://autobrr.com/filters/examples#only-season-packs' className='text-blue-400 visited:text-blue-400' target='_blank'>https://autobrr.com/filters/examples#only-season-packs</a></div>} />
+          <TextField name="episodes" label="Episodes" columns={4} placeholder="eg. 2,4,10-20"  tooltip={<div><p>Multi-episode releases match if any of their episodes match. Anime without a season match the absolute episode number.</p><p>See docs for information about how to <b>only</b> grab episodes:</p><a href='https://autobrr.com/filters/examples/#skip-season-packs' className='text-blue-400 visited:text-blue-400' target='_blank'>https://autobrr.com/filters/examples/#skip-season-packs</a></div>} />
+          <Select name="episode_type" label="Episode type" columns={4} options={episodeTypeOptions} optionDefaultText="Any" tooltip={<div><p>Only match season packs, or only single and multi-episode releases including daily shows.</p></div>} />
         </div>
 
         <div className="mt-6">
           <CheckboxField name="smart_episode" label="Smart Episode" sublabel="Do not match episodes older than the last one matched."/> {/*Do not match older or already existing episodes.*/}
+          <CheckboxField name="smart_episode_upgrade" label="Smart Episode Upgrades" sublabel="Allow better resolutions or sources of an already matched episode. Propers and repacks of the same quality are always allowed."/>
+          <CheckboxField name="allow_dupe_other_indexers" label="Allow duplicates from other indexers" sublabel="Only reject duplicates grabbed from the same indexer when duplicate protection is enabled."/>
         </div>
       </div>
 
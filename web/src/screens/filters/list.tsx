@@ -92,7 +92,22 @@ export default function Filters() {
   const handleImportJson = async () => {
     try {
       const importedData = JSON.parse(importJson);
-  
+
+      // Filters exported with their actions from another instance are mapped by the backend
+      if (typeof importedData.version === "number" && Array.isArray(importedData.filters)) {
+        const result = await APIClient.filters.import(importedData as FilterExport);
+
+        queryClient.invalidateQueries({ queryKey: filterKeys.lists() });
+
+        toast.custom((t) => <Toast type="success" body={`Imported ${result.filters.length} filter(s), they are disabled until reviewed.`} t={t} />);
+        if (result.warnings.length > 0) {
+          toast.custom((t) => <Toast type="warning" body={result.warnings.join("; ")} t={t} />, { duration: 15000 });
+        }
+
+        setShowImportModal(false);
+        return;
+      }
+
       // Extract the filter data and name from the imported object
       const importedFilter = importedData.data;
       const filterName = importedData.name;
@@ -186,6 +201,20 @@ export default function Filters() {
                           </button>
                         )}
                       </Menu.Item>
+                      <Menu.Item>
+                        {({ active }) => (
+                          <a
+                            href={APIClient.filters.exportUrl()}
+                            className={`${
+                              active
+                                ? "bg-gray-50 dark:bg-gray-600"
+                                : ""
+                            } block w-full text-left py-2 px-4 text-sm font-medium text-gray-700 dark:text-gray-200 rounded-md focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 dark:focus:ring-blue-500`}
+                          >
+                            Export all filters
+                          </a>
+                        )}
+                      </Menu.Item>
                     </Menu.Items>
                   </Transition>
                 </>
@@ -569,6 +598,26 @@ const FilterItemDropdown = ({ filter, onToggle }: FilterItemDropdownProps) => {
                 </button>
               )}
             </Menu.Item>
+            <Menu.Item>
+              {({ active }) => (
+                <a
+                  href={APIClient.filters.exportUrl([filter.id])}
+                  className={classNames(
+                    active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                    "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                  )}
+                >
+                  <ArrowDownTrayIcon
+                    className={classNames(
+                      active ? "text-white" : "text-blue-500",
+                      "w-5 h-5 mr-2"
+                    )}
+                    aria-hidden="true"
+                  />
+                  Export with actions
+                </a>
+              )}
+            </Menu.Item>
             <Menu.Item>
               {({ active }) => (
                 <button
@@ -0,0 +1,356 @@
+/*
+ * Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+
+import { APIClient } from "@api/APIClient";
+import Toast from "@components/notifications/Toast";
+import { baseUrl, classNames, simplifyDate } from "@utils";
+
+const twoFactorKeys = {
+  status: ["auth", "2fa"] as const
+};
+
+const sessionKeys = {
+  all: ["auth", "sessions"] as const
+};
+
+const oidcKeys = {
+  config: ["auth", "oidc"] as const,
+  link: ["auth", "oidc", "link"] as const
+};
+
+const inputClass = "block w-full shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md";
+const buttonClass = "inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 disabled:opacity-50";
+
+function AccountSettings() {
+  const { data } = useQuery({
+    queryKey: twoFactorKeys.status,
+    queryFn: APIClient.auth.twoFactor.status,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Two-factor authentication
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Require a code from an authenticator app when signing in with your password.
+          </p>
+        </div>
+
+        {data?.enabled ? <TwoFactorDisable /> : <TwoFactorEnroll />}
+      </div>
+
+      <OIDCLink />
+
+      <Sessions />
+    </div>
+  );
+}
+
+function OIDCLink() {
+  const queryClient = useQueryClient();
+
+  const { data: config } = useQuery({
+    queryKey: oidcKeys.config,
+    queryFn: APIClient.auth.oidcConfig,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  const { data } = useQuery({
+    queryKey: oidcKeys.link,
+    queryFn: APIClient.auth.oidcLink.status,
+    enabled: !!config?.enabled,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  const unlinkMutation = useMutation({
+    mutationFn: APIClient.auth.oidcLink.unlink,
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: oidcKeys.link });
+      toast.custom((t) => <Toast type="success" body="Identity provider unlinked" t={t} />);
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+    }
+  });
+
+  if (!config?.enabled) {
+    return null;
+  }
+
+  return (
+    <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+      <div className="flex justify-between items-start">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            OpenID Connect
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            {data?.linked
+              ? "Your account is linked to the identity provider and can sign in with it."
+              : "Link your account to sign in with the identity provider."}
+          </p>
+        </div>
+        {data?.linked ? (
+          <button
+            type="button"
+            className="inline-flex justify-center py-2 px-4 border border-gray-300 dark:border-gray-700 shadow-sm text-sm font-medium rounded-md text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 disabled:opacity-50"
+            disabled={unlinkMutation.isLoading}
+            onClick={() => unlinkMutation.mutate()}
+          >
+            Unlink
+          </button>
+        ) : (
+          <a href={`${baseUrl()}api/auth/oidc/login?link=true`} className={buttonClass}>
+            Link
+          </a>
+        )}
+      </div>
+    </div>
+  );
+}
+
+function Sessions() {
+  const queryClient = useQueryClient();
+
+  const { data: sessions } = useQuery({
+    queryKey: sessionKeys.all,
+    queryFn: APIClient.auth.sessions.getAll,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  const onError = (err: Error) => {
+    toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+  };
+
+  const revokeMutation = useMutation({
+    mutationFn: (id: string) => APIClient.auth.sessions.revoke(id),
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: sessionKeys.all }),
+    onError
+  });
+
+  const revokeOthersMutation = useMutation({
+    mutationFn: APIClient.auth.sessions.revokeOthers,
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: sessionKeys.all });
+      toast.custom((t) => <Toast type="success" body="Signed out all other sessions" t={t} />);
+    },
+    onError
+  });
+
+  return (
+    <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+      <div className="flex justify-between items-start">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Sessions
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Devices signed in to your account.
+          </p>
+        </div>
+        <button
+          type="button"
+          className="inline-flex justify-center py-2 px-4 border border-gray-300 dark:border-gray-700 shadow-sm text-sm font-medium rounded-md text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 disabled:opacity-50"
+          disabled={!sessions || sessions.length < 2}
+          onClick={() => revokeOthersMutation.mutate()}
+        >
+          Sign out other sessions
+        </button>
+      </div>
+
+      {sessions && sessions.length > 0 && (
+        <ol className="mt-4 min-w-full">
+          {sessions.map((s) => (
+            <li key={s.id} className="grid grid-cols-12 gap-4 items-center py-2 text-sm text-gray-500 dark:text-gray-400 border-b border-gray-200 dark:border-gray-700">
+              <span className="col-span-3 font-medium text-gray-900 dark:text-white truncate">
+                {s.ip}
+                {s.current && <span className="ml-2 text-xs font-normal text-gray-500 dark:text-gray-400">(this device)</span>}
+              </span>
+              <span className="col-span-5 truncate" title={s.user_agent}>{s.user_agent}</span>
+              <span className="col-span-3">{simplifyDate(s.last_seen)}</span>
+              <span className="col-span-1 text-right">
+                {!s.current && (
+                  <button
+                    type="button"
+                    className="text-red-600 dark:text-red-500 hover:underline"
+                    onClick={() => revokeMutation.mutate(s.id)}
+                  >
+                    Revoke
+                  </button>
+                )}
+              </span>
+            </li>
+          ))}
+        </ol>
+      )}
+    </div>
+  );
+}
+
+function TwoFactorEnroll() {
+  const queryClient = useQueryClient();
+  const [setup, setSetup] = useState<TwoFactorSetup | null>(null);
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+  const [code, setCode] = useState("");
+
+  const onError = (err: Error) => {
+    toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+  };
+
+  const setupMutation = useMutation({
+    mutationFn: APIClient.auth.twoFactor.setup,
+    onSuccess: (data) => setSetup(data),
+    onError
+  });
+
+  const enableMutation = useMutation({
+    mutationFn: (code: string) => APIClient.auth.twoFactor.enable(code),
+    onSuccess: (data) => {
+      setRecoveryCodes(data.recovery_codes);
+      setSetup(null);
+      setCode("");
+      toast.custom((t) => <Toast type="success" body="Two-factor authentication enabled" t={t} />);
+    },
+    onError
+  });
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <div className="mt-6">
+        <p className="text-sm text-gray-700 dark:text-gray-300">
+          Store these recovery codes somewhere safe. Each one can be used once to sign in if you lose your authenticator. They will not be shown again.
+        </p>
+        <ul className="mt-4 grid grid-cols-2 gap-2 font-mono text-sm text-gray-900 dark:text-white">
+          {recoveryCodes.map((c) => <li key={c}>{c}</li>)}
+        </ul>
+        <button
+          type="button"
+          className={classNames(buttonClass, "mt-4")}
+          onClick={() => {
+            setRecoveryCodes([]);
+            queryClient.invalidateQueries({ queryKey: twoFactorKeys.status });
+          }}
+        >
+          Done
+        </button>
+      </div>
+    );
+  }
+
+  if (!setup) {
+    return (
+      <button
+        type="button"
+        className={classNames(buttonClass, "mt-6")}
+        disabled={setupMutation.isLoading}
+        onClick={() => setupMutation.mutate()}
+      >
+        Set up two-factor authentication
+      </button>
+    );
+  }
+
+  return (
+    <div className="mt-6">
+      <p className="text-sm text-gray-700 dark:text-gray-300">
+        Add this key to your authenticator app, or open the link on a device that has one installed, then enter the code it shows.
+      </p>
+      <p className="mt-2 font-mono text-sm text-gray-900 dark:text-white break-all">{setup.secret}</p>
+      <a href={setup.url} className="mt-1 block text-sm text-blue-600 dark:text-blue-400 hover:underline break-all">
+        {setup.url}
+      </a>
+
+      <form
+        className="mt-4 flex gap-4 items-end"
+        onSubmit={(e) => {
+          e.preventDefault();
+          enableMutation.mutate(code);
+        }}
+      >
+        <div>
+          <label htmlFor="code" className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">Code</label>
+          <input
+            id="code"
+            type="text"
+            inputMode="numeric"
+            autoComplete="one-time-code"
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            className={classNames(inputClass, "mt-1")}
+          />
+        </div>
+        <button type="submit" className={buttonClass} disabled={!code || enableMutation.isLoading}>
+          Enable
+        </button>
+      </form>
+    </div>
+  );
+}
+
+function TwoFactorDisable() {
+  const queryClient = useQueryClient();
+  const [code, setCode] = useState("");
+
+  const mutation = useMutation({
+    mutationFn: (code: string) => APIClient.auth.twoFactor.disable(code),
+    onSuccess: () => {
+      setCode("");
+      queryClient.invalidateQueries({ queryKey: twoFactorKeys.status });
+      toast.custom((t) => <Toast type="success" body="Two-factor authentication disabled" t={t} />);
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+    }
+  });
+
+  return (
+    <div className="mt-6">
+      <p className="text-sm text-gray-700 dark:text-gray-300">
+        Two-factor authentication is enabled. Enter a code or a recovery code to turn it off.
+      </p>
+
+      <form
+        className="mt-4 flex gap-4 items-end"
+        onSubmit={(e) => {
+          e.preventDefault();
+          mutation.mutate(code);
+        }}
+      >
+        <div>
+          <label htmlFor="code" className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">Code</label>
+          <input
+            id="code"
+            type="text"
+            autoComplete="one-time-code"
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            className={classNames(inputClass, "mt-1")}
+          />
+        </div>
+        <button
+          type="submit"
+          className="inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-red-600 hover:bg-red-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-red-500 disabled:opacity-50"
+          disabled={!code || mutation.isLoading}
+        >
+          Disable
+        </button>
+      </form>
+    </div>
+  );
+}
+
+export default AccountSettings;
@@ -132,7 +132,12 @@ function APIListItem({ apikey }: ApiKeyItemProps) {
       <div className="sm:grid grid-cols-12 gap-4 items-center py-2">
         <div className="col-span-5 px-2 sm:px-6 py-2 sm:py-0 truncate block sm:text-sm text-md font-medium text-gray-900 dark:text-white">
           <div className="flex justify-between">
-            <div className="pl-1 py-2">{apikey.name}</div>
+            <div className="pl-1 py-2">
+              {apikey.name}
+              <p className="text-xs font-normal text-gray-500 dark:text-gray-400 truncate" title={apikey.scopes?.join(", ")}>
+                {apikey.scopes?.length ? apikey.scopes.join(", ") : "admin"}
+              </p>
+            </div>
             <div>
               <button
                 className={classNames(
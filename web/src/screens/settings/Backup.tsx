@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useRef, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+import { formatDistanceToNowStrict } from "date-fns";
+
+import { APIClient } from "@api/APIClient";
+import Toast from "@components/notifications/Toast";
+
+const databaseBackupKeys = {
+  all: ["backup", "database"] as const
+};
+
+const buttonClass = "inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 disabled:opacity-50";
+
+const summarize = (counts: Record<string, number>) => Object.entries(counts)
+  .filter(([, count]) => count > 0)
+  .map(([kind, count]) => `${count} ${kind.replace("_", " ")}`)
+  .join(", ");
+
+function BackupSettings() {
+  const [format, setFormat] = useState<BackupFormat>("zip");
+  const [redact, setRedact] = useState(false);
+  const [result, setResult] = useState<BackupImportResult | null>(null);
+  const fileRef = useRef<HTMLInputElement>(null);
+
+  const importMutation = useMutation({
+    mutationFn: (file: File) => APIClient.backup.import(file),
+    onSuccess: (data) => {
+      setResult(data);
+      toast.custom((t) => <Toast type="success" body="Backup imported" t={t} />);
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+    },
+    onSettled: () => {
+      if (fileRef.current) {
+        fileRef.current.value = "";
+      }
+    }
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Export
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Download filters, actions, indexers, IRC networks, feeds, download clients and notifications as a single file.
+          </p>
+        </div>
+
+        <div className="mt-6 flex flex-wrap gap-6 items-center">
+          <select
+            value={format}
+            onChange={(e) => setFormat(e.target.value as BackupFormat)}
+            className="block shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-blue-500 focus:border-blue-500 rounded-md"
+          >
+            <option value="zip">zip</option>
+            <option value="json">json</option>
+          </select>
+
+          <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+            <input
+              type="checkbox"
+              checked={redact}
+              onChange={(e) => setRedact(e.target.checked)}
+              className="rounded border-gray-300 dark:border-gray-700 text-blue-600 focus:ring-blue-500"
+            />
+            Remove passwords, api keys and other secrets
+          </label>
+
+          <a href={APIClient.backup.exportUrl(format, redact)} className={buttonClass}>
+            Download backup
+          </a>
+        </div>
+      </div>
+
+      <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Import
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Restore a backup. Items that already exist are skipped. Restart autobrr afterwards so IRC networks, feeds and indexers are picked up.
+          </p>
+        </div>
+
+        <input
+          ref={fileRef}
+          type="file"
+          accept=".json,.zip"
+          disabled={importMutation.isLoading}
+          onChange={(e) => {
+            const file = e.target.files?.[0];
+            if (file) {
+              importMutation.mutate(file);
+            }
+          }}
+          className="mt-6 block text-sm text-gray-700 dark:text-gray-300"
+        />
+
+        {result && (
+          <div className="mt-4 text-sm text-gray-700 dark:text-gray-300">
+            <p>Created: {summarize(result.created) || "nothing"}</p>
+            <p>Skipped: {summarize(result.skipped) || "nothing"}</p>
+            {result.warnings.length > 0 && (
+              <ul className="mt-2 list-disc list-inside text-yellow-600 dark:text-yellow-500">
+                {result.warnings.map((warning) => (
+                  <li key={warning}>{warning}</li>
+                ))}
+              </ul>
+            )}
+          </div>
+        )}
+      </div>
+
+      <DatabaseBackups />
+    </div>
+  );
+}
+
+const formatSize = (bytes: number) => bytes < 1024 * 1024
+  ? `${(bytes / 1024).toFixed(0)} KB`
+  : `${(bytes / 1024 / 1024).toFixed(1)} MB`;
+
+function DatabaseBackups() {
+  const queryClient = useQueryClient();
+
+  const { data: backups } = useQuery({
+    queryKey: databaseBackupKeys.all,
+    queryFn: APIClient.backup.database.getAll,
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  const createMutation = useMutation({
+    mutationFn: APIClient.backup.database.create,
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: databaseBackupKeys.all });
+      toast.custom((t) => <Toast type="success" body="Database backup created" t={t} />);
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+    }
+  });
+
+  return (
+    <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+      <div className="flex justify-between items-start">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Database backups
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Copies of the SQLite database, taken on a schedule and before every schema upgrade. Interval and retention are set in config.toml.
+          </p>
+        </div>
+        <button
+          type="button"
+          className={buttonClass}
+          disabled={createMutation.isLoading}
+          onClick={() => createMutation.mutate()}
+        >
+          Back up now
+        </button>
+      </div>
+
+      {backups && backups.length > 0 ? (
+        <ol className="mt-4 min-w-full">
+          {backups.map((b) => (
+            <li key={b.name} className="grid grid-cols-12 gap-4 items-center py-2 text-sm text-gray-500 dark:text-gray-400 border-b border-gray-200 dark:border-gray-700">
+              <span className="col-span-6 font-medium text-gray-900 dark:text-white truncate" title={b.name}>{b.name}</span>
+              <span className="col-span-2">{formatSize(b.size)}</span>
+              <span className="col-span-3" title={b.created_at}>
+                {formatDistanceToNowStrict(new Date(b.created_at), { addSuffix: true })}
+              </span>
+              <span className="col-span-1 text-right">
+                <a href={APIClient.backup.database.downloadUrl(b.name)} className="text-blue-600 dark:text-blue-400 hover:underline">
+                  Download
+                </a>
+              </span>
+            </li>
+          ))}
+        </ol>
+      ) : (
+        <p className="mt-4 text-sm text-gray-500 dark:text-gray-400">No database backups yet.</p>
+      )}
+    </div>
+  );
+}
+
+export default BackupSettings;
@@ -3,8 +3,8 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-import { useRef } from "react";
-import { useMutation, useQueryClient } from "@tanstack/react-query";
+import { useRef, useState } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 import { toast } from "react-hot-toast";
 
 import { APIClient } from "@api/APIClient";
@@ -59,6 +59,8 @@ function ReleaseSettings() {
         </div>
       </div>
 
+      <PruneReleases />
+
       <div className="pb-6 divide-y divide-gray-200 dark:divide-gray-700">
         <div className="px-4 py-5 sm:p-0">
           <div className="px-4 py-5 sm:p-6">
@@ -84,4 +86,103 @@ function ReleaseSettings() {
   );
 }
 
+const pruneStatuses = ["FILTER_REJECTED", "PUSH_REJECTED", "PUSH_ERROR", "PUSH_APPROVED", "PENDING"];
+
+function PruneReleases() {
+  const [days, setDays] = useState(30);
+  const [statuses, setStatuses] = useState<string[]>(["FILTER_REJECTED", "PUSH_REJECTED"]);
+  const [indexer, setIndexer] = useState("");
+  const queryClient = useQueryClient();
+
+  const { data: indexers } = useQuery({
+    queryKey: ["releases", "indexers"],
+    queryFn: APIClient.release.indexerOptions,
+    refetchOnWindowFocus: false
+  });
+
+  const pruneMutation = useMutation({
+    mutationFn: APIClient.release.prune,
+    onSuccess: (data) => {
+      toast.custom((t) => (
+        <Toast type="success" body={`Deleted ${data.deleted} releases`} t={t}/>
+      ));
+
+      queryClient.invalidateQueries({ queryKey: releaseKeys.lists() });
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t}/>);
+    }
+  });
+
+  const toggleStatus = (status: string) => setStatuses((prev) =>
+    prev.includes(status) ? prev.filter((s) => s !== status) : [...prev, status]
+  );
+
+  return (
+    <div className="px-4 pb-6 sm:px-6">
+      <div>
+        <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+          Prune releases
+        </h3>
+        <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+          Delete releases older than a number of days, 0 deletes them regardless of age. Releases waiting for a delay or retry are kept. A release counts as approved if any action pushed it. Scheduled pruning is set with releaseRetention in config.toml.
+        </p>
+      </div>
+
+      <div className="mt-4 flex flex-wrap gap-6 items-center">
+        <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+          Older than
+          <input
+            type="number"
+            min={0}
+            value={days}
+            onChange={(e) => setDays(Math.max(0, parseInt(e.target.value) || 0))}
+            className="w-20 shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-blue-500 focus:border-blue-500 rounded-md"
+          />
+          days
+        </label>
+
+        <select
+          value={indexer}
+          onChange={(e) => setIndexer(e.target.value)}
+          className="block shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-blue-500 focus:border-blue-500 rounded-md"
+        >
+          <option value="">All indexers</option>
+          {indexers?.map((i) => (
+            <option key={i} value={i}>{i}</option>
+          ))}
+        </select>
+      </div>
+
+      <div className="mt-4 flex flex-wrap gap-4">
+        {pruneStatuses.map((status) => (
+          <label key={status} className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+            <input
+              type="checkbox"
+              checked={statuses.includes(status)}
+              onChange={() => toggleStatus(status)}
+              className="rounded border-gray-300 dark:border-gray-700 text-blue-600 focus:ring-blue-500"
+            />
+            {status}
+          </label>
+        ))}
+      </div>
+
+      <button
+        type="button"
+        disabled={pruneMutation.isLoading || statuses.length === 0}
+        onClick={() => pruneMutation.mutate({
+          days,
+          statuses,
+          indexers: indexer ? [indexer] : undefined,
+          all: days === 0
+        })}
+        className="mt-4 inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500 disabled:opacity-50"
+      >
+        Prune
+      </button>
+    </div>
+  );
+}
+
 export default ReleaseSettings;
\ No newline at end of file
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+import { useRef } from "react";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { toast } from "react-hot-toast";
+import { TrashIcon } from "@heroicons/react/24/outline";
+import { Field, Form, Formik, FormikErrors } from "formik";
+
+import { APIClient } from "@api/APIClient";
+import { DeleteModal } from "@components/modals";
+import Toast from "@components/notifications/Toast";
+import { useToggle } from "@hooks/hooks";
+import { AuthContext } from "@utils/Context";
+import { classNames, simplifyDate } from "@utils";
+
+export const userKeys = {
+  all: ["users"] as const,
+  lists: () => [...userKeys.all, "list"] as const,
+  audit: () => [...userKeys.all, "audit"] as const
+};
+
+const roles: { value: UserRole; label: string }[] = [
+  { value: "admin", label: "Admin" },
+  { value: "editor", label: "Editor" },
+  { value: "viewer", label: "Viewer" }
+];
+
+const inputClass = "block w-full shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-blue-500 dark:focus:ring-blue-500 focus:border-blue-500 dark:focus:border-blue-500 rounded-md";
+
+function UserSettings() {
+  const { data: users } = useQuery({
+    queryKey: userKeys.lists(),
+    queryFn: APIClient.users.getAll,
+    retry: false,
+    refetchOnWindowFocus: false,
+    onError: (err) => console.log(err)
+  });
+
+  const { data: audit } = useQuery({
+    queryKey: userKeys.audit(),
+    queryFn: () => APIClient.users.audit(),
+    retry: false,
+    refetchOnWindowFocus: false
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Users
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Admins manage everything, editors can change everything except users, API keys and config, viewers can only look and never see clients, indexers, IRC, feeds, notifications, actions, config or logs since those hold credentials.
+          </p>
+        </div>
+
+        <UserAddForm />
+
+        {users && users.length > 0 && (
+          <section className="mt-6 light:bg-white dark:bg-gray-800 light:shadow sm:rounded-md">
+            <ol className="min-w-full relative">
+              <li className="hidden sm:grid grid-cols-12 gap-4 mb-2 border-b border-gray-200 dark:border-gray-700">
+                <div className="col-span-6 px-6 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                  Username
+                </div>
+                <div className="col-span-5 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider">
+                  Role
+                </div>
+              </li>
+
+              {users.map((u) => <UserListItem key={u.id} user={u} />)}
+            </ol>
+          </section>
+        )}
+      </div>
+
+      <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
+        <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+          Audit log
+        </h3>
+        <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+          Latest changes and who made them.
+        </p>
+
+        {audit && audit.length > 0 ? (
+          <ol className="mt-4 min-w-full">
+            {audit.map((e) => (
+              <li key={e.id} className="grid grid-cols-12 gap-4 py-2 text-sm text-gray-500 dark:text-gray-400 border-b border-gray-200 dark:border-gray-700">
+                <span className="col-span-3">{simplifyDate(e.timestamp)}</span>
+                <span className="col-span-3 font-medium text-gray-900 dark:text-white truncate">{e.username}</span>
+                <span className="col-span-6 truncate" title={e.path}>{e.action} {e.path}</span>
+              </li>
+            ))}
+          </ol>
+        ) : (
+          <p className="mt-4 text-sm text-gray-500 dark:text-gray-400">No changes recorded yet.</p>
+        )}
+      </div>
+    </div>
+  );
+}
+
+function UserAddForm() {
+  const queryClient = useQueryClient();
+
+  const mutation = useMutation({
+    mutationFn: (user: UserCreate) => APIClient.users.create(user),
+    onSuccess: (_, user) => {
+      queryClient.invalidateQueries({ queryKey: userKeys.all });
+      toast.custom((t) => <Toast type="success" body={`User ${user.username} was added`} t={t} />);
+    },
+    onError: (err: Error) => {
+      toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+    }
+  });
+
+  const validate = (values: UserCreate) => {
+    const errors = {} as FormikErrors<UserCreate>;
+    if (!values.username) {
+      errors.username = "Required";
+    }
+    if (!values.password) {
+      errors.password = "Required";
+    }
+    return errors;
+  };
+
+  return (
+    <Formik
+      initialValues={{ username: "", password: "", role: "viewer" } as UserCreate}
+      onSubmit={(values, { resetForm }) => {
+        mutation.mutate(values);
+        resetForm();
+      }}
+      validate={validate}
+    >
+      <Form className="mt-6 grid grid-cols-12 gap-4 items-end">
+        <div className="col-span-12 sm:col-span-4">
+          <label htmlFor="username" className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">Username</label>
+          <Field id="username" name="username" type="text" autoComplete="off" className={classNames(inputClass, "mt-1")} />
+        </div>
+        <div className="col-span-12 sm:col-span-4">
+          <label htmlFor="password" className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">Password</label>
+          <Field id="password" name="password" type="password" autoComplete="new-password" className={classNames(inputClass, "mt-1")} />
+        </div>
+        <div className="col-span-8 sm:col-span-2">
+          <label htmlFor="role" className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">Role</label>
+          <Field id="role" name="role" as="select" className={classNames(inputClass, "mt-1")}>
+            {roles.map((r) => <option key={r.value} value={r.value}>{r.label}</option>)}
+          </Field>
+        </div>
+        <div className="col-span-4 sm:col-span-2">
+          <button
+            type="submit"
+            className="w-full inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          >
+            Add user
+          </button>
+        </div>
+      </Form>
+    </Formik>
+  );
+}
+
+interface UserListItemProps {
+  user: User;
+}
+
+function UserListItem({ user }: UserListItemProps) {
+  const cancelModalButtonRef = useRef(null);
+  const [deleteModalIsOpen, toggleDeleteModal] = useToggle(false);
+  const authContext = AuthContext.useValue();
+
+  const queryClient = useQueryClient();
+
+  const onError = (err: Error) => {
+    toast.custom((t) => <Toast type="error" body={err.message} t={t} />);
+  };
+
+  const updateMutation = useMutation({
+    mutationFn: (role: UserRole) => APIClient.users.update({ id: user.id, role }),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: userKeys.all });
+      toast.custom((t) => <Toast type="success" body={`User ${user.username} was updated`} t={t} />);
+    },
+    onError
+  });
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => APIClient.users.delete(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: userKeys.all });
+      toast.custom((t) => <Toast type="success" body={`User ${user.username} was deleted`} t={t} />);
+    },
+    onError
+  });
+
+  const isSelf = authContext.username === user.username;
+
+  return (
+    <li className="text-gray-500 dark:text-gray-400">
+      <DeleteModal
+        isOpen={deleteModalIsOpen}
+        toggle={toggleDeleteModal}
+        buttonRef={cancelModalButtonRef}
+        deleteAction={() => {
+          deleteMutation.mutate(user.id);
+          toggleDeleteModal();
+        }}
+        title={`Remove user: ${user.username}`}
+        text="Are you sure you want to remove this user? This action cannot be undone."
+      />
+
+      <div className="grid grid-cols-12 gap-4 items-center py-2">
+        <div className="col-span-6 px-2 sm:px-6 truncate text-sm font-medium text-gray-900 dark:text-white">
+          {user.username}
+          {isSelf && <span className="ml-2 text-xs font-normal text-gray-500 dark:text-gray-400">(you)</span>}
+        </div>
+        <div className="col-span-5">
+          <select
+            value={user.role}
+            disabled={isSelf}
+            onChange={(e) => updateMutation.mutate(e.target.value as UserRole)}
+            className={inputClass}
+          >
+            {roles.map((r) => <option key={r.value} value={r.value}>{r.label}</option>)}
+          </select>
+        </div>
+        <div className="col-span-1 flex items-center">
+          <button
+            className={classNames(
+              "text-gray-900 dark:text-gray-300",
+              "font-medium group flex rounded-md items-center px-2 py-2 text-sm disabled:opacity-50"
+            )}
+            disabled={isSelf}
+            onClick={toggleDeleteModal}
+            title="Delete user"
+          >
+            <TrashIcon className="text-red-500 w-5 h-5" aria-hidden="true" />
+          </button>
+        </div>
+      </div>
+    </li>
+  );
+}
+
+export default UserSettings;
@@ -3,8 +3,10 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
+export { default as AccountSettings } from "./Account";
 export { default as APISettings } from "./Api";
 export { default as ApplicationSettings } from "./Application";
+export { default as BackupSettings } from "./Backup";
 export { default as DownloadClientSettings } from "./DownloadClient";
 export { default as FeedSettings } from "./Feed";
 export { default as IndexerSettings } from "./Indexer";
@@ -12,3 +14,4 @@ export { default as IrcSettings } from "./Irc";
 export { default as LogSettings } from "./Logs";
 export { default as NotificationSettings } from "./Notifications";
 export { default as ReleaseSettings } from "./Releases";
+export { default as UserSettings } from "./Users";
@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+type BackupFormat = "json" | "zip";
+
+interface BackupImportResult {
+  created: Record<string, number>;
+  skipped: Record<string, number>;
+  warnings: string[];
+}
+
+interface DatabaseBackup {
+  name: string;
+  size: number;
+  created_at: string;
+}
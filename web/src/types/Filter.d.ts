@@ -31,7 +31,10 @@ interface Filter {
   shows: string;
   seasons: string;
   episodes: string;
+  episode_type: string;
   smart_episode: boolean;
+  smart_episode_upgrade: boolean;
+  allow_dupe_other_indexers: boolean;
   resolutions: string[];
   codecs: string[];
   sources: string[];
@@ -106,10 +109,35 @@ interface Action {
   webhook_method: string;
   webhook_data: string,
   webhook_headers: string[];
+  retry_attempts?: number;
+  retry_interval?: number;
+  cross_seed?: ActionCrossSeed;
   filter_id?: number;
   client_id?: number;
 }
 
+type ActionCrossSeed = "SKIP" | "INJECT";
+
 type ActionContentLayout = "ORIGINAL" | "SUBFOLDER_CREATE" | "SUBFOLDER_NONE";
 
 type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | DownloadClientType;
+
+interface FilterExportAction extends Action {
+  client?: string;
+}
+
+interface FilterExportItem extends Omit<Filter, "indexers" | "actions"> {
+  indexers: string[];
+  actions?: FilterExportAction[];
+}
+
+interface FilterExport {
+  version: number;
+  exported_at: string;
+  filters: FilterExportItem[];
+}
+
+interface FilterImportResult {
+  filters: Filter[];
+  warnings: string[];
+}
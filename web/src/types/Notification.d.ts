@@ -3,8 +3,8 @@
  * SPDX-License-Identifier: GPL-2.0-or-later
  */
 
-type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER";
-type NotificationEvent = "PUSH_APPROVED" | "PUSH_REJECTED" | "PUSH_ERROR" | "IRC_DISCONNECTED" | "IRC_RECONNECTED" | "APP_UPDATE_AVAILABLE";
+type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "PUSHOVER" | "SLACK" | "MATTERMOST" | "ROCKETCHAT" | "MATRIX" | "PUSH_BULLET" | "IFTTT" | "JOIN" | "NTFY" | "GOTIFY";
+type NotificationEvent = "PUSH_APPROVED" | "PUSH_REJECTED" | "PUSH_ERROR" | "PUSH_RETRY_FAILED" | "IRC_DISCONNECTED" | "IRC_RECONNECTED" | "APP_UPDATE_AVAILABLE" | "IRC_CHANNEL_QUIET" | "FEED_FAILED" | "DOWNLOAD_CLIENT_ERROR" | "DAILY_DIGEST";
 
 interface Notification {
   id: number;
@@ -12,9 +12,33 @@ interface Notification {
   enabled: boolean;
   type: NotificationType;
   events: NotificationEvent[];
+  filters?: number[];
+  indexers?: string[];
   webhook?: string;
   token?: string;
   api_key?: string;
   channel?: string;
   priority?: number;
+  icon?: string;
+  username?: string;
+  password?: string;
+  host?: string;
+  rooms?: string;
+  targets?: string;
+  devices?: string;
+  templates?: NotificationTemplates;
+}
+
+interface NotificationTemplate {
+  title: string;
+  body: string;
+}
+
+type NotificationTemplates = Partial<Record<NotificationEvent, NotificationTemplate>>;
+
+interface NotificationPreview {
+  event: NotificationEvent | "TEST";
+  title: string;
+  body: string;
+  error?: string;
 }
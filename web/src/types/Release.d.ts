@@ -48,3 +48,14 @@ interface ReleaseFilter {
   id: string;
   value: string;
 }
+
+interface ReleasePruneRule {
+  days: number;
+  statuses?: string[];
+  indexers?: string[];
+  all?: boolean;
+}
+
+interface ReleasePruneResponse {
+  deleted: number;
+}
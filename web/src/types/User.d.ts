@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2021 - 2023, Ludvig Lundgren and the autobrr contributors.
+ * SPDX-License-Identifier: GPL-2.0-or-later
+ */
+
+type UserRole = "admin" | "editor" | "viewer";
+
+interface User {
+  id: number;
+  username: string;
+  role: UserRole;
+  two_factor_enabled: boolean;
+  created_at: string;
+}
+
+interface UserCreate {
+  username: string;
+  password: string;
+  role: UserRole;
+}
+
+interface UserUpdate {
+  id: number;
+  password?: string;
+  role?: UserRole;
+}
+
+interface AuditEvent {
+  id: number;
+  user_id?: number;
+  username: string;
+  action: string;
+  resource: string;
+  path: string;
+  timestamp: string;
+}
+
+interface LoginResponse {
+  two_factor_required?: boolean;
+}
+
+interface TwoFactorSetup {
+  secret: string;
+  url: string;
+}
+
+interface Session {
+  id: string;
+  user_id: number;
+  ip: string;
+  user_agent: string;
+  created_at: string;
+  last_seen: string;
+  current: boolean;
+}